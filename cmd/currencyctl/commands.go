@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"currency-converter/pkg/client"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type command struct {
+	client *client.Client
+	out    printer
+}
+
+func usageError(format string, args ...any) error {
+	fmt.Fprintf(os.Stderr, "usage: currencyctl "+format+"\n", args...)
+	return errUsage
+}
+
+func (c *command) convert(ctx context.Context, args []string) error {
+	if len(args) != 3 {
+		return usageError("convert AMOUNT FROM TO")
+	}
+	amount, err := strconv.ParseFloat(args[0], 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount %q", args[0])
+	}
+
+	conv, err := c.client.Convert(ctx, amount, strings.ToUpper(args[1]), strings.ToUpper(args[2]))
+	if err != nil {
+		return err
+	}
+	return c.out.conversions([]*client.Conversion{conv})
+}
+
+func (c *command) rates(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return usageError("rates list|get|set")
+	}
+
+	switch args[0] {
+	case "list":
+		list, err := c.client.ListRates(ctx)
+		if err != nil {
+			return err
+		}
+		return c.out.currencies(list)
+
+	case "get":
+		if len(args) != 2 {
+			return usageError("rates get CODE")
+		}
+		cur, err := c.client.GetRate(ctx, strings.ToUpper(args[1]))
+		if err != nil {
+			return err
+		}
+		return c.out.currencies([]*client.Currency{cur})
+
+	case "set":
+		return c.setRate(ctx, args[1:])
+	}
+	return usageError("rates list|get|set")
+}
+
+// setRate обновляет курс; имя и символ существующей валюты сохраняются, если не заданы флагами.
+func (c *command) setRate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("rates set", flag.ContinueOnError)
+	name := fs.String("name", "", "currency name (required for a new currency)")
+	symbol := fs.String("symbol", "", "currency symbol (required for a new currency)")
+	if len(args) < 2 {
+		return usageError("rates set CODE RATE [-name N] [-symbol S]")
+	}
+	if err := fs.Parse(args[2:]); err != nil {
+		return errUsage
+	}
+	code := strings.ToUpper(args[0])
+	rate, err := strconv.ParseFloat(args[1], 64)
+	if err != nil {
+		return fmt.Errorf("invalid rate %q", args[1])
+	}
+
+	cur := &client.Currency{Code: code, Rate: rate, Name: *name, Symbol: *symbol}
+	if cur.Name == "" || cur.Symbol == "" {
+		existing, err := c.client.GetRate(ctx, code)
+		if errors.Is(err, client.ErrNotFound) {
+			return fmt.Errorf("currency %s not found, pass -name and -symbol to create it", code)
+		} else if err != nil {
+			return err
+		}
+		if cur.Name == "" {
+			cur.Name = existing.Name
+		}
+		if cur.Symbol == "" {
+			cur.Symbol = existing.Symbol
+		}
+	}
+
+	saved, err := c.client.SetRate(ctx, cur)
+	if err != nil {
+		return err
+	}
+	return c.out.currencies([]*client.Currency{saved})
+}
+
+func (c *command) history(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return usageError("history")
+	}
+	list, err := c.client.History(ctx)
+	if err != nil {
+		return err
+	}
+	return c.out.conversions(list)
+}
+
+// watch периодически запрашивает курсы и печатает только изменившиеся (первый раз - все).
+// Ошибки отдельных опросов выводятся в stderr и не прерывают наблюдение.
+func (c *command) watch(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	interval := fs.Duration("interval", 30*time.Second, "polling interval")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	codes := make(map[string]bool, fs.NArg())
+	for _, code := range fs.Args() {
+		codes[strings.ToUpper(code)] = true
+	}
+
+	var printErr error
+	seen := make(map[string]float64)
+	onUpdate := func(snap *client.Snapshot) {
+		var changed []*client.Currency
+		for code, cur := range snap.Rates {
+			if len(codes) > 0 && !codes[code] {
+				continue
+			}
+			if rate, ok := seen[code]; !ok || rate != cur.Rate {
+				seen[code] = cur.Rate
+				changed = append(changed, &cur)
+			}
+		}
+		if len(changed) == 0 {
+			return
+		}
+		sortCurrencies(changed)
+		if err := c.out.currencies(changed); err != nil && printErr == nil {
+			printErr = err
+		}
+	}
+	onError := func(err error) {
+		fmt.Fprintln(os.Stderr, "error:", err)
+	}
+
+	err := c.client.WatchRates(ctx, *interval, onUpdate, onError)
+	if printErr != nil {
+		return printErr
+	}
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
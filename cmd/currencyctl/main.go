@@ -0,0 +1,167 @@
+// currencyctl - консольный клиент конвертера валют, работающий через REST или gRPC.
+//
+//	currencyctl [flags] convert 100 USD EUR
+//	currencyctl [flags] rates list
+//	currencyctl [flags] rates get USD
+//	currencyctl [flags] rates set USD 92.5 [-name "US Dollar" -symbol $]
+//	currencyctl [flags] history
+//	currencyctl [flags] watch [-interval 30s] [USD EUR ...]
+//
+// Адрес, транспорт и учётные данные берутся из флагов или переменных окружения CURRENCYCTL_*.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"currency-converter/internal/tlsconfig"
+	"currency-converter/pkg/client"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// errUsage - неверные аргументы; справка уже выведена.
+var errUsage = errors.New("invalid usage")
+
+type globalOptions struct {
+	transport  string
+	addr       string
+	output     string
+	timeout    time.Duration
+	apiKey     string
+	token      string
+	useTLS     bool
+	caFile     string
+	certFile   string
+	keyFile    string
+	serverName string
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:]); err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, "error:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	var opts globalOptions
+	fs := flag.NewFlagSet("currencyctl", flag.ContinueOnError)
+	fs.StringVar(&opts.transport, "transport", envString("CURRENCYCTL_TRANSPORT", string(client.REST)), "transport: rest or grpc")
+	fs.StringVar(&opts.addr, "addr", envString("CURRENCYCTL_ADDR", ""), "server address (default localhost:8080 for rest, localhost:9090 for grpc)")
+	fs.StringVar(&opts.output, "o", envString("CURRENCYCTL_OUTPUT", formatTable), "output format: table, json or csv")
+	fs.DurationVar(&opts.timeout, "timeout", 10*time.Second, "timeout for a single request")
+	fs.StringVar(&opts.apiKey, "api-key", envString("CURRENCYCTL_API_KEY", ""), "API key")
+	fs.StringVar(&opts.token, "token", envString("CURRENCYCTL_TOKEN", ""), "JWT bearer token")
+	fs.BoolVar(&opts.useTLS, "tls", envString("CURRENCYCTL_TLS", "") == "true", "connect over TLS")
+	fs.StringVar(&opts.caFile, "ca", envString("CURRENCYCTL_TLS_CA", ""), "CA certificate to verify the server (default: system roots)")
+	fs.StringVar(&opts.certFile, "cert", envString("CURRENCYCTL_TLS_CERT", ""), "client certificate for mTLS")
+	fs.StringVar(&opts.keyFile, "key", envString("CURRENCYCTL_TLS_KEY", ""), "client key for mTLS")
+	fs.StringVar(&opts.serverName, "server-name", "", "override server name for certificate verification")
+	fs.Usage = func() { usage(fs) }
+
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() == 0 {
+		usage(fs)
+		return errUsage
+	}
+	if !validFormat(opts.output) {
+		return fmt.Errorf("unknown output format %q", opts.output)
+	}
+
+	c, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	cmd := &command{
+		client: c,
+		out:    printer{w: os.Stdout, format: opts.output},
+	}
+
+	rest := fs.Args()[1:]
+	switch fs.Arg(0) {
+	case "convert":
+		return cmd.convert(ctx, rest)
+	case "rates":
+		return cmd.rates(ctx, rest)
+	case "history":
+		return cmd.history(ctx, rest)
+	case "watch":
+		return cmd.watch(ctx, rest)
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", fs.Arg(0))
+	usage(fs)
+	return errUsage
+}
+
+func newClient(opts globalOptions) (*client.Client, error) {
+	var tlsCfg *tls.Config
+	if opts.useTLS {
+		cfg, err := tlsconfig.Client(opts.caFile, opts.certFile, opts.keyFile, opts.serverName)
+		if err != nil {
+			return nil, fmt.Errorf("tls: %w", err)
+		}
+		tlsCfg = cfg
+	}
+
+	transport := client.Transport(opts.transport)
+	addr := opts.addr
+	switch transport {
+	case client.REST:
+		if addr == "" {
+			addr = "localhost:8080"
+		}
+	case client.GRPC:
+		if addr == "" {
+			addr = "localhost:9090"
+		}
+	default:
+		return nil, fmt.Errorf("unknown transport %q, expected rest or grpc", opts.transport)
+	}
+
+	return client.New(client.Options{
+		Transport: transport,
+		Addr:      addr,
+		APIKey:    opts.apiKey,
+		Token:     opts.token,
+		TLSConfig: tlsCfg,
+		Timeout:   opts.timeout,
+	})
+}
+
+func usage(fs *flag.FlagSet) {
+	w := fs.Output()
+	fmt.Fprint(w, `Usage: currencyctl [flags] <command> [args]
+
+Commands:
+  convert AMOUNT FROM TO                    convert an amount between currencies
+  rates list                                list all currencies and rates
+  rates get CODE                            show a single currency
+  rates set CODE RATE [-name N] [-symbol S] create or update a currency rate
+  history                                   show conversion history
+  watch [-interval D] [CODE ...]            poll rates and print changes
+
+Flags:
+`)
+	fs.PrintDefaults()
+}
+
+func envString(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return def
+}
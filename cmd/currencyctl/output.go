@@ -0,0 +1,105 @@
+package main
+
+import (
+	"currency-converter/pkg/client"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+func validFormat(format string) bool {
+	switch format {
+	case formatTable, formatJSON, formatCSV:
+		return true
+	}
+	return false
+}
+
+// printer выводит результаты команд в выбранном формате.
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func (p printer) currencies(list []*client.Currency) error {
+	if p.format == formatJSON {
+		return p.json(list)
+	}
+	rows := make([][]string, 0, len(list))
+	for _, cur := range list {
+		rows = append(rows, []string{cur.Code, cur.Name, cur.Symbol, formatFloat(cur.Rate), formatChange(cur)})
+	}
+	return p.rows([]string{"CODE", "NAME", "SYMBOL", "RATE", "CHANGE"}, rows)
+}
+
+func (p printer) conversions(list []*client.Conversion) error {
+	if p.format == formatJSON {
+		return p.json(list)
+	}
+	rows := make([][]string, 0, len(list))
+	for _, conv := range list {
+		rows = append(rows, []string{
+			formatFloat(conv.Amount), conv.From.Code,
+			formatFloat(conv.Result), conv.To.Code,
+		})
+	}
+	return p.rows([]string{"AMOUNT", "FROM", "RESULT", "TO"}, rows)
+}
+
+func (p printer) json(v any) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (p printer) rows(header []string, rows [][]string) error {
+	if p.format == formatCSV {
+		w := csv.NewWriter(p.w)
+		w.Write(header)
+		w.WriteAll(rows)
+		return w.Error()
+	}
+
+	w := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	writeTabRow(w, header)
+	for _, row := range rows {
+		writeTabRow(w, row)
+	}
+	return w.Flush()
+}
+
+func writeTabRow(w io.Writer, row []string) {
+	for i, cell := range row {
+		if i > 0 {
+			fmt.Fprint(w, "\t")
+		}
+		fmt.Fprint(w, cell)
+	}
+	fmt.Fprintln(w)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// formatChange - изменение за день в процентах; пусто, если предыдущий курс неизвестен.
+func formatChange(cur *client.Currency) string {
+	if cur.PreviousRate <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(cur.ChangePercent, 'f', 2, 64) + "%"
+}
+
+func sortCurrencies(list []*client.Currency) {
+	sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+}
@@ -4,21 +4,26 @@ import (
 	_ "currency-converter/docs"
 	"currency-converter/internal/api/cbr"
 	"currency-converter/internal/app"
+	"currency-converter/internal/audit"
+	"currency-converter/internal/auth"
+	"currency-converter/internal/config"
 	"currency-converter/internal/handler"
+	"currency-converter/internal/health"
+	"currency-converter/internal/history"
+	"currency-converter/internal/lifecycle"
+	"currency-converter/internal/logging"
+	"currency-converter/internal/ratelimit"
 	"currency-converter/internal/repository"
 	"currency-converter/internal/service"
-	"currency-converter/proto"
+	"currency-converter/internal/tracing"
 
 	"context"
-	"fmt"
-	"log"
-	"net"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
-	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 // @title Currency Converter API
@@ -26,6 +31,12 @@ import (
 // @description REST API для управления валютами и конвертациями
 // @host localhost:8080
 // @BasePath /
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -35,65 +46,153 @@ func main() {
 
 	go func() {
 		sig := <-signalChan
-		fmt.Println("Received shutdown signal: ", sig)
+		slog.Info("received shutdown signal", "signal", sig.String())
 		cancel()
 	}()
 
+	cfg := config.Load()
+	logging.Setup(cfg.LogFormat, cfg.LogLevel)
+
+	tracer, err := tracing.Setup(ctx, tracing.Options{
+		Endpoint:    cfg.OTLPEndpoint,
+		ServiceName: cfg.ServiceName,
+		SampleRatio: cfg.TraceSampleRatio,
+	})
+	if err != nil {
+		slog.Error("failed to configure tracing", "error", err)
+		os.Exit(1)
+	}
+
+	authn, err := auth.New(auth.Options{
+		APIKeys:          cfg.APIKeys,
+		HS256Secret:      cfg.JWTHS256Secret,
+		HS256SecretFile:  cfg.JWTHS256SecretFile,
+		RS256PublicKey:   cfg.JWTRS256PublicKey,
+		Issuer:           cfg.JWTIssuer,
+		AllowedClockSkew: cfg.JWTAllowedClockSkew,
+		Disabled:         cfg.AuthDisabled,
+	})
+	if err != nil {
+		slog.Error("failed to configure authentication", "error", err)
+		os.Exit(1)
+	}
+	if !authn.Enabled() {
+		slog.Error("AUTHENTICATION IS DISABLED (AUTH_DISABLED=true): anyone who can reach the REST and gRPC ports can read and change rates",
+			"rest_addr", cfg.RESTAddr, "grpc_addr", cfg.GRPCAddr)
+	}
+
+	rateRules, err := ratelimit.ParseRules(cfg.RateLimit, cfg.RateLimitRules, cfg.RateLimitAuth)
+	if err != nil {
+		slog.Error("failed to configure rate limiting", "error", err)
+		os.Exit(1)
+	}
+	limiter := ratelimit.New(rateRules)
+
+	// Audit
+	auditLog, err := audit.Open(cfg.AuditLogPath)
+	if err != nil {
+		slog.Error("failed to open audit log", "error", err)
+		os.Exit(1)
+	}
+	defer auditLog.Close()
+
+	// История курсов
+	rateHistory, err := history.Open(cfg.HistoryPath)
+	if err != nil {
+		slog.Error("failed to open rate history", "error", err)
+		auditLog.Close()
+		os.Exit(1)
+	}
+	defer rateHistory.Close()
+
 	// Repository
-	repo := repository.NewRepository()
-	if err := repo.LoadCurrencies(); err != nil {
-		fmt.Println("Failed to load currency data: ", err)
+	repo := repository.NewRepository(auditLog, rateHistory)
+	currenciesErr := repo.LoadCurrencies(ctx)
+	if currenciesErr != nil {
+		slog.Error("failed to load currency data", "error", currenciesErr)
+	}
+	if err := repo.LoadConversions(ctx); err != nil {
+		slog.Error("failed to load conversion data", "error", err)
+	}
+	if err := repo.LoadOverrides(ctx); err != nil {
+		slog.Error("failed to load rate overrides", "error", err)
 	}
-	if err := repo.LoadConversions(); err != nil {
-		fmt.Println("Failed to load conversion data:", err)
+	if err := repo.LoadAlerts(ctx); err != nil {
+		slog.Error("failed to load alert rules", "error", err)
+	}
+	if err := repo.LoadWebhooks(ctx); err != nil {
+		slog.Error("failed to load webhook subscriptions", "error", err)
 	}
 	//API ЦБ РФ
 	cbrClient := cbr.NewCBRClient()
 
 	//Service
-	srvc := service.InitService(ctx, repo, cbrClient)
+	srvc := service.NewService(repo, cbrClient, service.Options{
+		WriteMode:           service.WriteMode(cfg.WriteMode),
+		QueueSize:           cfg.QueueSize,
+		StoreTimeout:        cfg.StoreTimeout,
+		RatePolicy:          service.RatePolicy(cfg.RatePolicy),
+		WebhookTimeout:      cfg.WebhookTimeout,
+		WebhookWorkers:      cfg.WebhookWorkers,
+		WebhookMaxAttempts:  cfg.WebhookMaxAttempts,
+		WebhookRetryBackoff: cfg.WebhookRetryBackoff,
+	})
+
+	// Health
+	checker := health.NewChecker(srvc, cfg.RatesStaleAfter)
+	if currenciesErr == nil {
+		checker.MarkCurrenciesLoaded()
+	}
 
 	//Handlers
 	curHandler := handler.NewCurrencyHandler(srvc)
 	convHandler := handler.NewConversionHandler(srvc)
-
-	//Запуск REST
-	server := app.New(":8080", curHandler, convHandler)
-	go func() {
-		if err := server.Start(); err != nil {
-			fmt.Println("REST API server error: ", err)
-			cancel()
-		}
-	}()
-
-	//Запуск gRPC
-	go func() {
-		lis, err := net.Listen("tcp", ":9090")
-		if err != nil {
-			log.Fatalf("Failed to listen on gRPC port: %v", err)
-		}
-
-		grpcServer := grpc.NewServer()
-
-		proto.RegisterCurrencyServiceServer(grpcServer,
-			app.NewCurrencyServer(srvc))
-		proto.RegisterConversionServiceServer(grpcServer,
-			app.NewConversionServer(srvc))
-
-		log.Println("gRPC server starting on port: 9090")
-		if err := grpcServer.Serve(lis); err != nil {
-			log.Fatalf("gRPC server failed: %v", err)
-		}
-	}()
-
-	<-ctx.Done()
-
-	shutdownCtx, cancelShutdown := context.WithTimeout(
-		context.Background(), 2*time.Second)
-	defer cancelShutdown()
-	if err := server.Stop(shutdownCtx); err != nil {
-		fmt.Println("Error during server shutdown:", err)
+	auditHandler := handler.NewAuditHandler(auditLog)
+	alertHandler := handler.NewAlertHandler(srvc)
+	webhookHandler := handler.NewWebhookHandler(srvc)
+	historyHandler := handler.NewHistoryHandler(rateHistory)
+	transferHandler := handler.NewTransferHandler(srvc)
+
+	grpcServer, err := app.NewGRPCServer(cfg.GRPCAddr, srvc, rateHistory, authn, limiter, app.GRPCOptions{
+		TLSCertFile:       cfg.GRPCTLSCertFile,
+		TLSKeyFile:        cfg.GRPCTLSKeyFile,
+		TLSClientCAFile:   cfg.GRPCTLSClientCAFile,
+		Reflection:        cfg.GRPCReflection,
+		MaxRecvMsgSize:    cfg.GRPCMaxRecvMsgSize,
+		MaxSendMsgSize:    cfg.GRPCMaxSendMsgSize,
+		KeepaliveTime:     cfg.GRPCKeepaliveTime,
+		KeepaliveTimeout:  cfg.GRPCKeepaliveTimeout,
+		KeepaliveMinTime:  cfg.GRPCKeepaliveMinTime,
+		MaxConnectionIdle: cfg.GRPCMaxConnectionIdle,
+	})
+	if err != nil {
+		slog.Error("failed to configure gRPC server", "error", err)
+		auditLog.Close()
+		rateHistory.Close()
+		os.Exit(1)
+	}
+	grpcServer.Register(&healthpb.Health_ServiceDesc, checker.GRPCServer())
+
+	// Порядок важен: первой снимается готовность, затем останавливаются транспорты,
+	// доставка webhook и очередь записи - после того как новые запросы и синхронизации прекратились,
+	// трассировка - последней, чтобы успеть отправить спаны остановки.
+	manager := lifecycle.New(cfg.ShutdownTimeout)
+	manager.Add(
+		tracer,
+		srvc.PersistenceWorker(),
+		srvc.WebhookDispatcher(),
+		srvc.SyncLoop(),
+		grpcServer,
+		app.New(cfg.RESTAddr, curHandler, convHandler, auditHandler, alertHandler, webhookHandler, historyHandler, transferHandler, checker, authn, limiter),
+		checker,
+	)
+
+	if err := manager.Run(ctx); err != nil {
+		slog.Error("application terminated with errors", "error", err)
+		auditLog.Close()
+		rateHistory.Close()
+		os.Exit(1)
 	}
 
-	fmt.Println("Application terminated successfully")
+	slog.Info("application terminated successfully")
 }
@@ -15,39 +15,99 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/conversion": {
-            "post": {
-                "description": "Converts amount from one currency to another using current Central Bank of Russia exchange rates and saves the conversion result",
-                "consumes": [
-                    "application/json"
+        "/admin/audit": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Returns every recorded change of an exchange rate (who, source, old and new rate, request ID). Use format=csv or format=jsonl to download an export",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "tags": [
-                    "conversion"
+                    "admin"
                 ],
-                "summary": "Convert currency amount",
+                "summary": "Get rate change audit trail",
                 "parameters": [
                     {
-                        "description": "Conversion request parameters",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.ConversionRequest"
-                        }
+                        "type": "string",
+                        "example": "USD",
+                        "description": "Currency code",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Actor (API key name or JWT subject)",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "api",
+                            "cbr_sync",
+                            "system",
+                            "import"
+                        ],
+                        "type": "string",
+                        "description": "Change source",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Request ID",
+                        "name": "request_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the period, RFC 3339 (inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the period, RFC 3339 (exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Return only the last N matching entries",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "jsonl"
+                        ],
+                        "type": "string",
+                        "description": "Response format",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Successfully converted currency",
+                    "200": {
+                        "description": "Matching audit entries in chronological order",
                         "schema": {
-                            "$ref": "#/definitions/model.Conversion"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/audit.Entry"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid request parameters",
+                        "description": "Invalid filter",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -55,17 +115,57 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Currency not found",
+                    "500": {
+                        "description": "Audit log could not be read",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/admin/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
                     },
-                    "422": {
-                        "description": "Invalid conversion parameters",
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Downloads a versioned zip archive with currencies, rate history and conversions for moving data between environments. The archive contains manifest.json (format version, record counts) and one file per section in JSON Lines or CSV",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Export data",
+                "parameters": [
+                    {
+                        "enum": [
+                            "jsonl",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Format of the sections (default jsonl)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Zip archive",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Unsupported format",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -85,26 +185,92 @@ const docTemplate = `{
                 }
             }
         },
-        "/conversions": {
-            "get": {
-                "description": "Retrieves history of all currency conversions performed",
+        "/admin/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Imports an archive produced by GET /admin/export (any supported version and format). All records are validated first; invalid records (422) or, with on_conflict=fail, currencies that already exist with different data (409) abort the whole import and the report lists them. Rate history and conversions are merged: records that already exist are skipped. With dry_run=true nothing is changed and the report shows what would happen",
+                "consumes": [
+                    "application/zip"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "conversion"
+                    "admin"
+                ],
+                "summary": "Import data",
+                "parameters": [
+                    {
+                        "description": "Zip archive from /admin/export",
+                        "name": "archive",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate and report",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "fail",
+                            "skip",
+                            "overwrite"
+                        ],
+                        "type": "string",
+                        "description": "What to do with currencies that differ (default fail)",
+                        "name": "on_conflict",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get conversion history",
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved conversion history",
+                        "description": "Import report",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.Conversion"
+                            "$ref": "#/definitions/model.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid archive or parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflicting currencies, nothing imported",
+                        "schema": {
+                            "$ref": "#/definitions/model.ImportReport"
+                        }
+                    },
+                    "413": {
+                        "description": "Archive is too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
+                    "422": {
+                        "description": "Invalid records, nothing imported",
+                        "schema": {
+                            "$ref": "#/definitions/model.ImportReport"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -117,23 +283,31 @@ const docTemplate = `{
                 }
             }
         },
-        "/currencies": {
+        "/alerts": {
             "get": {
-                "description": "Retrieves all currencies with current exchange rates from Central Bank of Russia",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all alert rules in creation order",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "currency"
+                    "alert"
                 ],
-                "summary": "Get list of all available currencies",
+                "summary": "List rate alerts",
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved currencies map",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "$ref": "#/definitions/model.Currency"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.AlertRule"
                             }
                         }
                     },
@@ -147,11 +321,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/currency": {
+            },
             "post": {
-                "description": "Adds a new currency to the storage",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a rule that is evaluated after every Central Bank of Russia sync and POSTs an event to webhook_url when it fires. Conditions: above, below (rate of base in quote crosses threshold since the previous sync) and change_pct (day-over-day change in percent exceeds threshold). A rule fires once per crossing; last_rate is the rate it was last checked against",
                 "consumes": [
                     "application/json"
                 ],
@@ -159,17 +339,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "currency"
+                    "alert"
                 ],
-                "summary": "Create currency",
+                "summary": "Create rate alert",
                 "parameters": [
                     {
-                        "description": "Currency data",
-                        "name": "currency",
+                        "description": "Alert rule",
+                        "name": "alert",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.Currency"
+                            "$ref": "#/definitions/model.AlertRequest"
                         }
                     }
                 ],
@@ -177,11 +357,26 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/model.Currency"
+                            "$ref": "#/definitions/model.AlertRule"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid alert rule, listed by field",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body is too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -190,7 +385,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Failed to persist alert rule",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -201,44 +396,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/currency/{code}": {
+        "/alerts/{id}": {
             "get": {
-                "description": "Retrieves detailed information about specific currency including exchange rate from Central Bank of Russia",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "currency"
+                    "alert"
                 ],
-                "summary": "Get currency details by code",
+                "summary": "Get rate alert",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "USD",
-                        "description": "Currency code (ISO 4217 format)",
-                        "name": "code",
+                        "description": "Alert rule ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved currency details",
-                        "schema": {
-                            "$ref": "#/definitions/model.Currency"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid currency code format",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/model.AlertRule"
                         }
                     },
                     "404": {
-                        "description": "Currency not found",
+                        "description": "Alert rule not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -258,7 +450,15 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Updates the exchange rate for a specific currency. Note: Normally rates are updated automatically from Central Bank of Russia",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the rule parameters; the time it last fired is kept, so the cooldown still applies",
                 "consumes": [
                     "application/json"
                 ],
@@ -266,46 +466,106 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "currency"
+                    "alert"
                 ],
-                "summary": "Update currency exchange rate",
+                "summary": "Update rate alert",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "USD",
-                        "description": "Currency code to update (ISO 4217 format)",
-                        "name": "code",
+                        "description": "Alert rule ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Currency data with updated exchange rate",
-                        "name": "currency",
+                        "description": "Alert rule",
+                        "name": "alert",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.Currency"
+                            "$ref": "#/definitions/model.AlertRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully updated currency",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Currency"
+                            "$ref": "#/definitions/model.AlertRule"
                         }
                     },
                     "400": {
-                        "description": "Invalid input data or currency code mismatch",
+                        "description": "Invalid alert rule, listed by field",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Alert rule not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request body is too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "alert"
+                ],
+                "summary": "Delete rate alert",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Alert rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Alert rule deleted"
                     },
                     "404": {
-                        "description": "Currency not found",
+                        "description": "Alert rule not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -324,15 +584,1859 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.Conversion": {
-            "type": "object",
+        },
+        "/conversion": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Converts amount from one currency to another using current Central Bank of Russia exchange rates and saves the conversion result",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "conversion"
+                ],
+                "summary": "Convert currency amount",
+                "parameters": [
+                    {
+                        "description": "Conversion request parameters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ConversionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully converted currency",
+                        "schema": {
+                            "$ref": "#/definitions/model.Conversion"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters or unknown fields",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Currency not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request body is too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid conversion parameters, listed by field",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded, see Retry-After header",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to persist conversion",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Write queue is full or service is shutting down",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Timed out waiting for persistence",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/conversions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves history of all currency conversions performed. The response format is chosen by the Accept header",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/csv",
+                    "application/yaml",
+                    "application/x-protobuf"
+                ],
+                "tags": [
+                    "conversion"
+                ],
+                "summary": "Get conversion history",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved conversion history",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Conversion"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "No acceptable response format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/conversions/report": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Aggregates conversion history for a period: total count and volume in rubles (at the rate of each conversion), volume, converted amount and average amount per currency pair, top pairs by number of conversions and number of conversions per day. Conversions recorded before timestamps were stored are only counted when no period is given. Use format=csv to download one table (pairs, top or daily) or format=xlsx for a workbook with all of them",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "conversion"
+                ],
+                "summary": "Get conversion report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start of the period, date (2006-01-02) or RFC 3339 (inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the period, date (2006-01-02) or RFC 3339 (exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of top pairs (default 5)",
+                        "name": "top",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "Response format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pairs",
+                            "top",
+                            "daily"
+                        ],
+                        "type": "string",
+                        "description": "Table to export as CSV (default pairs)",
+                        "name": "table",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ConversionReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid period or parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/currencies": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all currencies with current exchange rates from Central Bank of Russia. The response format is chosen by the Accept header",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/csv",
+                    "application/yaml",
+                    "application/x-protobuf"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "Get list of all available currencies",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved currencies map (a list sorted by code in XML, CSV and protobuf)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/model.Currency"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "No acceptable response format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/currency": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a new currency to the storage. Existing currencies are never overwritten, use /currency/upsert for that",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "Create currency",
+                "parameters": [
+                    {
+                        "description": "Currency data",
+                        "name": "currency",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Currency"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Currency"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid currency data or unknown ISO 4217 code, listed by field",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Currency already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request body is too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to persist currency",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Write queue is full or service is shutting down",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Timed out waiting for persistence",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/currency/upsert": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a currency or overwrites an existing one with the same code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "Create or replace currency",
+                "parameters": [
+                    {
+                        "description": "Currency data",
+                        "name": "currency",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Currency"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Currency"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid currency data or unknown ISO 4217 code, listed by field",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body is too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to persist currency",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Write queue is full or service is shutting down",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Timed out waiting for persistence",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/currency/{code}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves detailed information about specific currency including exchange rate from Central Bank of Russia",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "Get currency details by code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "USD",
+                        "description": "Currency code (ISO 4217 format)",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved currency details",
+                        "schema": {
+                            "$ref": "#/definitions/model.Currency"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid currency code format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Currency not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates a currency. A changed exchange rate is pinned as a manual override without expiry (like PUT /currency/{code}/override), so the Central Bank of Russia sync keeps it while the precedence policy is manual; DELETE /currency/{code}/override returns to the provider rate",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "Update currency exchange rate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "USD",
+                        "description": "Currency code to update (ISO 4217 format)",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Currency data with updated exchange rate",
+                        "name": "currency",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Currency"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully updated currency",
+                        "schema": {
+                            "$ref": "#/definitions/model.Currency"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data or currency code mismatch, listed by field",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Currency not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request body is too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a currency and its manual rate override. Note: currencies provided by Central Bank of Russia reappear after the next sync",
+                "tags": [
+                    "currency"
+                ],
+                "summary": "Delete currency",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "USD",
+                        "description": "Currency code (ISO 4217 format)",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Currency deleted"
+                    },
+                    "404": {
+                        "description": "Currency not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/currency/{code}/candles": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Aggregates daily rates into open/high/low/close candles of any length: Nd (days), Nw (weeks starting on Monday), NM (calendar months) or Ny (years), e.g. 1w or 3M. Candles are aligned to the calendar in UTC. For a quote other than RUB the cross rate is computed for each day on which both rates are known; since intraday rates of the two currencies are observed at different times, cross candles are built from daily closing cross rates only",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "Get OHLC candles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "USD",
+                        "description": "Base currency code (ISO 4217 format)",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "EUR",
+                        "description": "Quote currency (default RUB)",
+                        "name": "quote",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "1w",
+                        "description": "Candle length (default 1d)",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the period, date (2006-01-02) or RFC 3339 (inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the period, date (2006-01-02) or RFC 3339 (exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/history.Candles"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid period or interval",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No rate history for the period",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/currency/{code}/override": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets a manual rate that survives Central Bank of Russia sync until it expires or is cleared (depending on the configured precedence policy)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "override"
+                ],
+                "summary": "Pin a manual exchange rate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "USD",
+                        "description": "Currency code (ISO 4217 format)",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Manual rate, reason and optional expiry",
+                        "name": "override",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.OverrideRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Override applied",
+                        "schema": {
+                            "$ref": "#/definitions/model.RateOverride"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid override data, listed by field",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Currency not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request body is too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the manual rate and restores the last known Central Bank of Russia rate",
+                "tags": [
+                    "override"
+                ],
+                "summary": "Clear a manual exchange rate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "USD",
+                        "description": "Currency code (ISO 4217 format)",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Override cleared"
+                    },
+                    "404": {
+                        "description": "Override not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/currency/{code}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Computes statistics over daily closing rates to the ruble: min, max, mean, median, standard deviation, volatility of daily log returns (in percent, daily and annualized over 252 trading days) and simple/exponential moving averages at the end of the period. Moving averages also use rates before the period; windows longer than the whole history are omitted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "Get rate statistics for a period",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "USD",
+                        "description": "Currency code (ISO 4217 format)",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the period, date (2006-01-02) or RFC 3339 (inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the period, date (2006-01-02) or RFC 3339 (exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "7,30",
+                        "description": "Comma-separated SMA windows in days (default 7,30)",
+                        "name": "sma",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "12,26",
+                        "description": "Comma-separated EMA windows in days (default 12,26)",
+                        "name": "ema",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/history.Stats"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid period or window",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No rate history for the period",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Returns 200 while the process is able to serve HTTP requests",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/overrides": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all active manual rate overrides",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "override"
+                ],
+                "summary": "List manual exchange rates",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved overrides",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.RateOverride"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/rates/movers": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists currencies whose rate to the ruble rose or fell the most since the previous Central Bank of Russia rate, ordered by change in percent. Currencies without a known previous rate are skipped",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "Get biggest daily gainers and losers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of currencies in each list (default 5)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Movers"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Returns 200 once currencies are loaded and at least cached rates are available (status \"degraded\" when rates are stale), 503 otherwise",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all subscriptions in creation order, without secrets",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "List event subscriptions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.WebhookSubscription"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Registers a URL that receives events as signed JSON POST requests. Events: rates.updated, currency.created, currency.updated, currency.deleted, conversion.created; an empty list subscribes to all of them. Every request carries X-Webhook-ID, X-Webhook-Event, X-Webhook-Timestamp and X-Webhook-Signature = \"sha256=\" + hex HMAC-SHA256 of \"\u003ctimestamp\u003e.\u003cbody\u003e\" keyed with the secret. Failed deliveries are retried with exponential backoff and end up in the dead-letter list. The secret is returned only in this response",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Subscribe to events",
+                "parameters": [
+                    {
+                        "description": "Subscription",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookSubscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid subscription, listed by field",
+                        "schema": {
+                            "$ref": "#/definitions/httputil.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body is too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to persist subscription",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/dead-letters": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves deliveries that failed after all retry attempts, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Get dead-letter deliveries",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/deliveries/{id}/retry": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sends the same payload again with a fresh set of retry attempts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Retry dead-letter delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookDelivery"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Delivery is not dead",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Get event subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookSubscription"
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Pending deliveries of the subscription are not sent anymore",
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Delete event subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Subscription deleted"
+                    },
+                    "404": {
+                        "description": "Subscription not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves deliveries of the subscription, newest first, with the number of attempts, the last response code and error",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Get delivery history of a subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "audit.Action": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "delete"
+            ],
+            "x-enum-varnames": [
+                "ActionCreate",
+                "ActionUpdate",
+                "ActionDelete"
+            ]
+        },
+        "audit.Entry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/audit.Action"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "new_rate": {
+                    "type": "number"
+                },
+                "old_rate": {
+                    "type": "number"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "seq": {
+                    "type": "integer"
+                },
+                "source": {
+                    "$ref": "#/definitions/audit.Source"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "audit.Source": {
+            "type": "string",
+            "enum": [
+                "api",
+                "cbr_sync",
+                "system",
+                "import"
+            ],
+            "x-enum-varnames": [
+                "SourceAPI",
+                "SourceCBRSync",
+                "SourceSystem",
+                "SourceImport"
+            ]
+        },
+        "health.Report": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "last_sync": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/health.Status"
+                }
+            }
+        },
+        "health.Status": {
+            "type": "string",
+            "enum": [
+                "ok",
+                "degraded",
+                "not_ready"
+            ],
+            "x-enum-varnames": [
+                "StatusOK",
+                "StatusDegraded",
+                "StatusNotReady"
+            ]
+        },
+        "history.Candle": {
+            "type": "object",
+            "properties": {
+                "close": {
+                    "type": "number"
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "high": {
+                    "type": "number"
+                },
+                "low": {
+                    "type": "number"
+                },
+                "open": {
+                    "type": "number"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "history.Candles": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "type": "string"
+                },
+                "candles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/history.Candle"
+                    }
+                },
+                "interval": {
+                    "type": "string"
+                },
+                "quote": {
+                    "type": "string"
+                }
+            }
+        },
+        "history.MovingAverage": {
+            "type": "object",
+            "properties": {
+                "value": {
+                    "type": "number"
+                },
+                "window": {
+                    "type": "integer"
+                }
+            }
+        },
+        "history.Stats": {
+            "type": "object",
+            "properties": {
+                "annualized_volatility": {
+                    "type": "number"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "ema": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/history.MovingAverage"
+                    }
+                },
+                "from": {
+                    "description": "дата первого бара периода",
+                    "type": "string"
+                },
+                "max": {
+                    "type": "number"
+                },
+                "mean": {
+                    "type": "number"
+                },
+                "median": {
+                    "type": "number"
+                },
+                "min": {
+                    "type": "number"
+                },
+                "sma": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/history.MovingAverage"
+                    }
+                },
+                "std_dev": {
+                    "type": "number"
+                },
+                "to": {
+                    "description": "дата последнего бара периода",
+                    "type": "string"
+                },
+                "volatility": {
+                    "description": "Стандартное отклонение дневных лог-доходностей в процентах и оно же в пересчёте на год.",
+                    "type": "number"
+                }
+            }
+        },
+        "httputil.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error:": {
+                    "type": "string"
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/validation.FieldError"
+                    }
+                }
+            }
+        },
+        "model.AlertCondition": {
+            "type": "string",
+            "enum": [
+                "above",
+                "below",
+                "change_pct"
+            ],
+            "x-enum-varnames": [
+                "AlertAbove",
+                "AlertBelow",
+                "AlertChangePct"
+            ]
+        },
+        "model.AlertRequest": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "type": "string"
+                },
+                "condition": {
+                    "$ref": "#/definitions/model.AlertCondition"
+                },
+                "cooldown_seconds": {
+                    "type": "integer"
+                },
+                "quote": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "number"
+                },
+                "webhook_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AlertRule": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "type": "string"
+                },
+                "condition": {
+                    "$ref": "#/definitions/model.AlertCondition"
+                },
+                "cooldown_seconds": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_fired_at": {
+                    "type": "string"
+                },
+                "last_rate": {
+                    "description": "курс пары при последней проверке",
+                    "type": "number"
+                },
+                "quote": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "number"
+                },
+                "webhook_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ConflictPolicy": {
+            "type": "string",
+            "enum": [
+                "fail",
+                "skip",
+                "overwrite"
+            ],
+            "x-enum-comments": {
+                "ConflictFail": "не импортировать ничего",
+                "ConflictSkip": "оставить существующую"
+            },
+            "x-enum-varnames": [
+                "ConflictFail",
+                "ConflictSkip",
+                "ConflictOverwrite"
+            ]
+        },
+        "model.Conversion": {
+            "type": "object",
             "properties": {
                 "amount": {
                     "type": "number"
                 },
+                "created_at": {
+                    "description": "Время конвертации; у записей, сделанных до его появления, не заполнено.",
+                    "type": "string"
+                },
                 "from": {
                     "$ref": "#/definitions/model.Currency"
                 },
@@ -344,6 +2448,51 @@ const docTemplate = `{
                 }
             }
         },
+        "model.ConversionReport": {
+            "type": "object",
+            "properties": {
+                "average_amount_rub": {
+                    "type": "number"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "daily": {
+                    "description": "каждый день от первой до последней конвертации",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DailyConversions"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "pairs": {
+                    "description": "по алфавиту",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PairStats"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                },
+                "top_pairs": {
+                    "description": "по числу конвертаций",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PairStats"
+                    }
+                },
+                "undated": {
+                    "description": "Конвертации без даты (записанные до её появления); учитываются только в отчёте без периода.",
+                    "type": "integer"
+                },
+                "volume_rub": {
+                    "type": "number"
+                }
+            }
+        },
         "model.ConversionRequest": {
             "type": "object",
             "properties": {
@@ -361,12 +2510,22 @@ const docTemplate = `{
         "model.Currency": {
             "type": "object",
             "properties": {
+                "change": {
+                    "type": "number"
+                },
+                "change_percent": {
+                    "type": "number"
+                },
                 "code": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
                 },
+                "previous_rate": {
+                    "description": "Изменение за день относительно предыдущего курса ЦБ РФ; заполняется сервисом.\nНулевое изменение выводится всегда, а без previous_rate предыдущий курс неизвестен.",
+                    "type": "number"
+                },
                 "rate": {
                     "type": "number"
                 },
@@ -374,6 +2533,318 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "model.DailyConversions": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "volume_rub": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.DeliveryStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "delivered",
+                "dead"
+            ],
+            "x-enum-varnames": [
+                "DeliveryPending",
+                "DeliveryDelivered",
+                "DeliveryDead"
+            ]
+        },
+        "model.ImportCounts": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "unchanged": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ImportIssue": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "record": {
+                    "type": "integer"
+                },
+                "section": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ImportReport": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "type": "boolean"
+                },
+                "conflicts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ImportIssue"
+                    }
+                },
+                "conversions": {
+                    "$ref": "#/definitions/model.ImportCounts"
+                },
+                "currencies": {
+                    "$ref": "#/definitions/model.ImportCounts"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ImportIssue"
+                    }
+                },
+                "format": {
+                    "type": "string"
+                },
+                "on_conflict": {
+                    "$ref": "#/definitions/model.ConflictPolicy"
+                },
+                "rate_history": {
+                    "$ref": "#/definitions/model.ImportCounts"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Movers": {
+            "type": "object",
+            "properties": {
+                "gainers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Currency"
+                    }
+                },
+                "losers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Currency"
+                    }
+                }
+            }
+        },
+        "model.OverrideRequest": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PairStats": {
+            "type": "object",
+            "properties": {
+                "average_amount": {
+                    "type": "number"
+                },
+                "converted": {
+                    "type": "number"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "volume": {
+                    "description": "Сумма Amount в валюте From, сумма Result в валюте To и оборот в рублях по курсу на момент конвертации.",
+                    "type": "number"
+                },
+                "volume_rub": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.RateOverride": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "provider_rate": {
+                    "description": "последний курс поставщика, восстанавливается после истечения",
+                    "type": "number"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "event": {
+                    "$ref": "#/definitions/model.WebhookEvent"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_attempt_at": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "response_code": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.DeliveryStatus"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.WebhookEvent": {
+            "type": "string",
+            "enum": [
+                "rates.updated",
+                "currency.created",
+                "currency.updated",
+                "currency.deleted",
+                "conversion.created"
+            ],
+            "x-enum-varnames": [
+                "EventRatesUpdated",
+                "EventCurrencyCreated",
+                "EventCurrencyUpdated",
+                "EventCurrencyDeleted",
+                "EventConversionCreated"
+            ]
+        },
+        "model.WebhookSubscription": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.WebhookEvent"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.WebhookSubscriptionRequest": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.WebhookEvent"
+                    }
+                },
+                "secret": {
+                    "description": "Если секрет не задан, он генерируется.",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "validation.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
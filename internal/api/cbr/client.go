@@ -2,23 +2,37 @@ package cbr
 
 import (
 	"context"
+	"currency-converter/internal/metrics"
+	"currency-converter/internal/requestid"
+	"currency-converter/internal/tracing"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+var tracer = otel.Tracer("currency-converter/internal/api/cbr")
+
 type CBRClient struct {
 	baseURL    string
 	httpClient *http.Client
 }
 
 func NewCBRClient() *CBRClient {
+	return NewCBRClientWithURL("https://www.cbr-xml-daily.ru")
+}
+
+// NewCBRClientWithURL - клиент к другому серверу с тем же API, например к зеркалу или тестовому.
+func NewCBRClientWithURL(baseURL string) *CBRClient {
 	return &CBRClient{
-		baseURL: "https://www.cbr-xml-daily.ru",
+		baseURL: baseURL,
 		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout:   10 * time.Second,
+			Transport: tracing.Transport(http.DefaultTransport),
 		},
 	}
 }
@@ -41,11 +55,20 @@ type CurrencyRespose struct {
 	Previous float64 `json:"Previous"`
 }
 
-func (c *CBRClient) GetDailyRates(ctx context.Context) (*CBRResponse, error) {
+func (c *CBRClient) GetDailyRates(ctx context.Context) (rates *CBRResponse, err error) {
+	ctx, span := tracer.Start(ctx, "cbr.GetDailyRates")
+	defer func() {
+		metrics.CBRFetches.WithLabelValues(metrics.Result(err)).Inc()
+		tracing.End(span, err)
+	}()
+
 	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/daily_json.js", nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	if id := requestid.FromContext(ctx); id != "" {
+		req.Header.Set(requestid.Header, id)
+	}
 
 	res, err := c.httpClient.Do(req)
 	if err != nil {
@@ -62,6 +85,7 @@ func (c *CBRClient) GetDailyRates(ctx context.Context) (*CBRResponse, error) {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	log.Println("The current course has been received")
+	span.SetAttributes(attribute.Int("cbr.currencies", len(cbrResponse.Valute)))
+	slog.InfoContext(ctx, "CBR daily rates received", "currencies", len(cbrResponse.Valute))
 	return &cbrResponse, nil
 }
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"currency-converter/internal/api/cbr"
+	"currency-converter/internal/handler"
+	"currency-converter/internal/repository"
+	"currency-converter/internal/service"
+	"currency-converter/internal/transport"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"currency-converter/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// doneContexts - запрос, от которого клиент отказался, и запрос с истёкшим дедлайном.
+var doneContexts = []struct {
+	name     string
+	ctx      func() (context.Context, context.CancelFunc)
+	wantHTTP int
+	wantGRPC codes.Code
+}{
+	{
+		name: "cancelled",
+		ctx: func() (context.Context, context.CancelFunc) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			return ctx, cancel
+		},
+		wantHTTP: transport.StatusClientClosedRequest,
+		wantGRPC: codes.Canceled,
+	},
+	{
+		name: "deadline exceeded",
+		ctx: func() (context.Context, context.CancelFunc) {
+			return context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		},
+		wantHTTP: http.StatusGatewayTimeout,
+		wantGRPC: codes.DeadlineExceeded,
+	},
+}
+
+func newCancelTestService(t *testing.T) service.Service {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	return service.NewService(repository.NewRepository(nil, nil), cbr.NewCBRClientWithURL("http://127.0.0.1:1"), service.Options{})
+}
+
+func TestContextErrorsOverREST(t *testing.T) {
+	h := handler.NewCurrencyHandler(newCancelTestService(t))
+	for _, tt := range doneContexts {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			get := httptest.NewRequestWithContext(ctx, http.MethodGet, "/currency/USD", nil)
+			get.SetPathValue("code", "USD")
+			res := httptest.NewRecorder()
+			h.GetCurrency(res, get)
+			if res.Code != tt.wantHTTP {
+				t.Errorf("GET status = %d, want %d", res.Code, tt.wantHTTP)
+			}
+
+			post := httptest.NewRequestWithContext(ctx, http.MethodPost, "/currency",
+				strings.NewReader(`{"code":"USD","rate":90,"name":"US dollar","symbol":"$"}`))
+			post.Header.Set("Content-Type", "application/json")
+			res = httptest.NewRecorder()
+			h.CreateCurrency(res, post)
+			if res.Code != tt.wantHTTP {
+				t.Errorf("POST status = %d, want %d", res.Code, tt.wantHTTP)
+			}
+		})
+	}
+}
+
+func TestContextErrorsOverGRPC(t *testing.T) {
+	srv := NewCurrencyServer(newCancelTestService(t))
+	for _, tt := range doneContexts {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			_, err := srv.GetCurrency(ctx, &proto.Currency{Code: "USD"})
+			if status.Code(err) != tt.wantGRPC {
+				t.Errorf("GetCurrency code = %v, want %v", status.Code(err), tt.wantGRPC)
+			}
+			_, err = srv.CreateCurrency(ctx, &proto.CreateCurrencyRequest{Currency: &proto.Currency{Code: "USD", Rate: 90, Name: "US dollar", Symbol: "$"}})
+			if status.Code(err) != tt.wantGRPC {
+				t.Errorf("CreateCurrency code = %v, want %v", status.Code(err), tt.wantGRPC)
+			}
+		})
+	}
+}
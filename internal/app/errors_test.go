@@ -0,0 +1,290 @@
+package app
+
+import (
+	"context"
+	"currency-converter/internal/api/cbr"
+	"currency-converter/internal/handler"
+	"currency-converter/internal/model"
+	"currency-converter/internal/repository"
+	"currency-converter/internal/service"
+	"currency-converter/internal/transport"
+	"currency-converter/internal/validation"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"currency-converter/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// failingService отвечает на CreateCurrency заданной ошибкой; остальные методы не нужны.
+type failingService struct {
+	service.Service
+	err error
+}
+
+func (s failingService) CreateCurrency(context.Context, *model.Currency) (*model.Currency, error) {
+	return nil, s.err
+}
+
+// storeFailed - ошибка в том виде, в каком её возвращает синхронная запись.
+func storeFailed(err error) error {
+	return fmt.Errorf("%w: %w", service.ErrStoreFailed, err)
+}
+
+func TestServiceErrorMapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantHTTP int
+		wantGRPC codes.Code
+	}{
+		{
+			name:     "duplicate",
+			err:      fmt.Errorf("%w: USD", service.ErrCurrencyExists),
+			wantHTTP: http.StatusConflict,
+			wantGRPC: codes.AlreadyExists,
+		},
+		{
+			name:     "concurrent duplicate in sync mode",
+			err:      storeFailed(fmt.Errorf("%w: USD", repository.ErrCurrencyExists)),
+			wantHTTP: http.StatusConflict,
+			wantGRPC: codes.AlreadyExists,
+		},
+		{
+			name:     "not found behind store failure",
+			err:      storeFailed(fmt.Errorf("%w: USD", service.ErrCurrencyNotFound)),
+			wantHTTP: http.StatusNotFound,
+			wantGRPC: codes.NotFound,
+		},
+		{
+			name: "invalid argument behind store failure",
+			err: storeFailed(&validation.Error{
+				Kind:   service.ErrInvalidCurrency,
+				Fields: []validation.FieldError{{Field: "rate", Message: "must be positive"}},
+			}),
+			wantHTTP: http.StatusBadRequest,
+			wantGRPC: codes.InvalidArgument,
+		},
+		{
+			name:     "unprocessable",
+			err:      service.ErrInvalidConversion,
+			wantHTTP: http.StatusUnprocessableEntity,
+			wantGRPC: codes.InvalidArgument,
+		},
+		{
+			name:     "disk failure",
+			err:      storeFailed(errors.New("no space left on device")),
+			wantHTTP: http.StatusInternalServerError,
+			wantGRPC: codes.Internal,
+		},
+		{
+			name:     "queue full",
+			err:      service.ErrQueueFull,
+			wantHTTP: http.StatusServiceUnavailable,
+			wantGRPC: codes.Unavailable,
+		},
+		{
+			name:     "store timeout",
+			err:      service.ErrStoreTimeout,
+			wantHTTP: http.StatusGatewayTimeout,
+			wantGRPC: codes.DeadlineExceeded,
+		},
+		{
+			name:     "cancelled behind store failure",
+			err:      storeFailed(context.Canceled),
+			wantHTTP: transport.StatusClientClosedRequest,
+			wantGRPC: codes.Canceled,
+		},
+		{
+			name:     "unknown",
+			err:      errors.New("boom"),
+			wantHTTP: http.StatusInternalServerError,
+			wantGRPC: codes.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := transport.HTTPStatus(tt.err); got != tt.wantHTTP {
+				t.Errorf("HTTPStatus() = %d, want %d", got, tt.wantHTTP)
+			}
+			if got := transport.GRPCCode(tt.err); got != tt.wantGRPC {
+				t.Errorf("GRPCCode() = %v, want %v", got, tt.wantGRPC)
+			}
+
+			svc := failingService{err: tt.err}
+
+			req := httptest.NewRequest(http.MethodPost, "/currency",
+				strings.NewReader(`{"code":"USD","rate":90,"name":"US dollar","symbol":"$"}`))
+			req.Header.Set("Content-Type", "application/json")
+			res := httptest.NewRecorder()
+			handler.NewCurrencyHandler(svc).CreateCurrency(res, req)
+			if res.Code != tt.wantHTTP {
+				t.Errorf("REST status = %d, want %d", res.Code, tt.wantHTTP)
+			}
+
+			_, err := NewCurrencyServer(svc).CreateCurrency(context.Background(),
+				&proto.CreateCurrencyRequest{Currency: &proto.Currency{Code: "USD", Rate: 90, Name: "US dollar", Symbol: "$"}})
+			if got := status.Code(err); got != tt.wantGRPC {
+				t.Errorf("gRPC code = %v, want %v", got, tt.wantGRPC)
+			}
+		})
+	}
+}
+
+// newErrorTestService - сервис с USD и EUR в хранилище; воркер записи запускается, только если start.
+func newErrorTestService(t *testing.T, opts service.Options, start bool) service.Service {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("data", 0755); err != nil {
+		t.Fatal(err)
+	}
+	repo := repository.NewRepository(nil, nil)
+	for _, cur := range []*model.Currency{model.NewCurrency("USD", 90, "US dollar", "$"), model.NewCurrency("EUR", 100, "Euro", "€")} {
+		if err := repo.Store(context.Background(), cur); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	svc := service.NewService(repo, cbr.NewCBRClientWithURL("http://127.0.0.1:1"), opts)
+	if start {
+		worker := svc.PersistenceWorker()
+		worker.Start(context.Background())
+		t.Cleanup(func() { worker.Stop(context.Background()) })
+	}
+	return svc
+}
+
+// restCall выполняет запрос к обработчику с JSON-телом и значениями пути path.
+func restCall(h http.HandlerFunc, method, body string, path map[string]string) int {
+	req := httptest.NewRequest(method, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range path {
+		req.SetPathValue(k, v)
+	}
+	res := httptest.NewRecorder()
+	h(res, req)
+	return res.Code
+}
+
+func TestEndpointErrors(t *testing.T) {
+	svc := newErrorTestService(t, service.Options{WriteMode: service.WriteSync, StoreTimeout: 5 * time.Second}, true)
+	curHandler, convHandler := handler.NewCurrencyHandler(svc), handler.NewConversionHandler(svc)
+	curServer, convServer := NewCurrencyServer(svc), NewConversionServer(svc)
+
+	tests := []struct {
+		name     string
+		rest     func() int
+		grpc     func() error // nil - в gRPC такой ошибки нет
+		wantHTTP int
+		wantGRPC codes.Code
+	}{
+		{
+			name: "update of an unknown currency",
+			rest: func() int {
+				return restCall(curHandler.UpdateCurrency, http.MethodPut, `{"rate":1,"name":"Yen","symbol":"¥"}`, map[string]string{"code": "JPY"})
+			},
+			grpc: func() error {
+				_, err := curServer.UpdateCurrency(context.Background(), &proto.Currency{Code: "JPY", Rate: 1, Name: "Yen", Symbol: "¥"})
+				return err
+			},
+			wantHTTP: http.StatusNotFound,
+			wantGRPC: codes.NotFound,
+		},
+		{
+			name: "update with a code other than in the path",
+			rest: func() int {
+				return restCall(curHandler.UpdateCurrency, http.MethodPut, `{"code":"EUR","rate":91,"name":"US dollar","symbol":"$"}`, map[string]string{"code": "USD"})
+			},
+			wantHTTP: http.StatusBadRequest,
+		},
+		{
+			name: "duplicate create",
+			rest: func() int {
+				return restCall(curHandler.CreateCurrency, http.MethodPost, `{"code":"USD","rate":90,"name":"US dollar","symbol":"$"}`, nil)
+			},
+			grpc: func() error {
+				_, err := curServer.CreateCurrency(context.Background(),
+					&proto.CreateCurrencyRequest{Currency: &proto.Currency{Code: "USD", Rate: 90, Name: "US dollar", Symbol: "$"}})
+				return err
+			},
+			wantHTTP: http.StatusConflict,
+			wantGRPC: codes.AlreadyExists,
+		},
+		{
+			name: "conversion of a non-positive amount",
+			rest: func() int {
+				return restCall(convHandler.CreateConversion, http.MethodPost, `{"amount":0,"from":"USD","to":"EUR"}`, nil)
+			},
+			grpc: func() error {
+				_, err := convServer.CreateConversion(context.Background(), &proto.CreateConversionRequest{Amount: 0, From: "USD", To: "EUR"})
+				return err
+			},
+			wantHTTP: http.StatusUnprocessableEntity,
+			wantGRPC: codes.InvalidArgument,
+		},
+		{
+			name: "conversion from an unknown currency",
+			rest: func() int {
+				return restCall(convHandler.CreateConversion, http.MethodPost, `{"amount":1,"from":"JPY","to":"EUR"}`, nil)
+			},
+			grpc: func() error {
+				_, err := convServer.CreateConversion(context.Background(), &proto.CreateConversionRequest{Amount: 1, From: "JPY", To: "EUR"})
+				return err
+			},
+			wantHTTP: http.StatusNotFound,
+			wantGRPC: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rest(); got != tt.wantHTTP {
+				t.Errorf("REST status = %d, want %d", got, tt.wantHTTP)
+			}
+			if tt.grpc == nil {
+				return
+			}
+			if got := status.Code(tt.grpc()); got != tt.wantGRPC {
+				t.Errorf("gRPC code = %v, want %v", got, tt.wantGRPC)
+			}
+		})
+	}
+}
+
+func TestQueueErrors(t *testing.T) {
+	// Воркер не запущен: первая задача занимает единственное место в очереди и
+	// не дожидается записи, следующей места уже нет.
+	opts := service.Options{WriteMode: service.WriteSync, QueueSize: 1, StoreTimeout: 20 * time.Millisecond}
+
+	t.Run("REST", func(t *testing.T) {
+		h := handler.NewConversionHandler(newErrorTestService(t, opts, false))
+		const body = `{"amount":1,"from":"USD","to":"EUR"}`
+		if got := restCall(h.CreateConversion, http.MethodPost, body, nil); got != http.StatusGatewayTimeout {
+			t.Errorf("store timeout: status = %d, want %d", got, http.StatusGatewayTimeout)
+		}
+		if got := restCall(h.CreateConversion, http.MethodPost, body, nil); got != http.StatusServiceUnavailable {
+			t.Errorf("queue full: status = %d, want %d", got, http.StatusServiceUnavailable)
+		}
+	})
+
+	t.Run("gRPC", func(t *testing.T) {
+		srv := NewConversionServer(newErrorTestService(t, opts, false))
+		convert := func() codes.Code {
+			_, err := srv.CreateConversion(context.Background(), &proto.CreateConversionRequest{Amount: 1, From: "USD", To: "EUR"})
+			return status.Code(err)
+		}
+		if got := convert(); got != codes.DeadlineExceeded {
+			t.Errorf("store timeout: code = %v, want %v", got, codes.DeadlineExceeded)
+		}
+		if got := convert(); got != codes.Unavailable {
+			t.Errorf("queue full: code = %v, want %v", got, codes.Unavailable)
+		}
+	})
+}
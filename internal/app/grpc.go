@@ -2,16 +2,56 @@ package app
 
 import (
 	"context"
+	"currency-converter/internal/history"
 	"currency-converter/internal/model"
 	"currency-converter/internal/service"
+	"currency-converter/internal/transport"
+	"currency-converter/internal/validation"
+	"errors"
+	"time"
 
 	"currency-converter/proto"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// Проверка входных данных живёт в сервисе, а коды ошибок - в пакете transport,
+// поэтому gRPC и REST отвечают на одни и те же запросы одинаково.
+// Здесь остаётся только перевод между proto и model; общий с REST - в transport.
+
+// statusError оборачивает ошибку сервиса в gRPC-статус с кодом из transport.
+// Ошибки проверки полей дополнительно передаются деталью BadRequest.
+func statusError(msg string, err error) error {
+	st := status.Newf(transport.GRPCCode(err), "%s: %v", msg, err)
+	var verr *validation.Error
+	if errors.As(err, &verr) {
+		details := &errdetails.BadRequest{}
+		for _, f := range verr.Fields {
+			details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       f.Field,
+				Description: f.Message,
+			})
+		}
+		if withDetails, derr := st.WithDetails(details); derr == nil {
+			st = withDetails
+		}
+	}
+	return st.Err()
+}
+
+func fromProtoCurrency(cur *proto.Currency) *model.Currency {
+	return &model.Currency{
+		Code:   cur.GetCode(),
+		Rate:   cur.GetRate(),
+		Name:   cur.GetName(),
+		Symbol: cur.GetSymbol(),
+	}
+}
+
 type CurrencyServer struct {
 	proto.UnimplementedCurrencyServiceServer
 	svc service.Service
@@ -25,106 +65,77 @@ func (s *CurrencyServer) CreateCurrency(ctx context.Context, req *proto.CreateCu
 	if req.Currency == nil {
 		return nil, status.Errorf(codes.InvalidArgument, "Currency object is required")
 	}
-	
-	switch {
-	case req.Currency.Code == "":
-		return nil, status.Errorf(codes.InvalidArgument, "Currency code cannot be empty")
-	case req.Currency.Rate <= 0:
-		return nil, status.Errorf(codes.InvalidArgument, "Exchange rate must be greater than zero")
-	case req.Currency.Name == "":
-		return nil, status.Errorf(codes.InvalidArgument, "Currency name cannot be empty")
-	case req.Currency.Symbol == "":
-		return nil, status.Errorf(codes.InvalidArgument, "Currency symbol cannot be empty")
+
+	created, err := s.svc.CreateCurrency(ctx, fromProtoCurrency(req.Currency))
+	if err != nil {
+		return nil, statusError("Failed to create currency", err)
 	}
+	return transport.CurrencyToProto(created), nil
+}
 
-	cur := &model.Currency{
-		Code:   req.Currency.Code,
-		Rate:   req.Currency.Rate,
-		Name:   req.Currency.Name,
-		Symbol: req.Currency.Symbol,
+func (s *CurrencyServer) UpsertCurrency(ctx context.Context, req *proto.CreateCurrencyRequest) (*proto.Currency, error) {
+	if req.Currency == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Currency object is required")
 	}
 
-	created, err := s.svc.CreateCurrency(cur)
+	upserted, err := s.svc.UpsertCurrency(ctx, fromProtoCurrency(req.Currency))
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed to create currency: %v", err)
+		return nil, statusError("Failed to upsert currency", err)
 	}
-
-	return &proto.Currency{
-		Code:   created.Code,
-		Rate:   created.Rate,
-		Name:   created.Name,
-		Symbol: created.Symbol,
-	}, nil
+	return transport.CurrencyToProto(upserted), nil
 }
 
 func (s *CurrencyServer) ListCurrencies(ctx context.Context, _ *emptypb.Empty) (*proto.ListCurrenciesResponse, error) {
-	data, err := s.svc.ListCurrencies()
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed to retrieve currency list: %v", err)
-	}
-	
-	result := make([]*proto.Currency, 0, len(data))
-	for _, v := range data {
-		result = append(result, &proto.Currency{
-			Code:   v.Code,
-			Rate:   v.Rate,
-			Name:   v.Name,
-			Symbol: v.Symbol,
-		})
+	data, err := s.svc.ListCurrencies(ctx)
+	if err != nil {
+		return nil, statusError("Failed to retrieve currency list", err)
 	}
-	return &proto.ListCurrenciesResponse{Currencies: result}, nil
+	return transport.CurrenciesToProto(data), nil
 }
 
-func (s *CurrencyServer) GetCurrency(ctx context.Context, req *proto.Currency) (*proto.Currency, error) {
-	if req.Code == "" {
-		return nil, status.Errorf(codes.InvalidArgument, "Currency code is required")
+func (s *CurrencyServer) ListMovers(ctx context.Context, req *proto.ListMoversRequest) (*proto.ListMoversResponse, error) {
+	if req.GetLimit() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Limit must be a positive integer")
 	}
-	
-	data, err := s.svc.GetCurrency(req.Code)
+	movers, err := s.svc.ListMovers(ctx, int(req.GetLimit()))
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "Currency '%s' not found in the system", req.Code)
+		return nil, statusError("Failed to retrieve rate movers", err)
+	}
+
+	res := &proto.ListMoversResponse{
+		Gainers: make([]*proto.Currency, 0, len(movers.Gainers)),
+		Losers:  make([]*proto.Currency, 0, len(movers.Losers)),
 	}
-	
-	return &proto.Currency{
-		Code:   data.Code,
-		Rate:   data.Rate,
-		Name:   data.Name,
-		Symbol: data.Symbol,
-	}, nil
+	for _, v := range movers.Gainers {
+		res.Gainers = append(res.Gainers, transport.CurrencyToProto(v))
+	}
+	for _, v := range movers.Losers {
+		res.Losers = append(res.Losers, transport.CurrencyToProto(v))
+	}
+	return res, nil
+}
+
+func (s *CurrencyServer) GetCurrency(ctx context.Context, req *proto.Currency) (*proto.Currency, error) {
+	data, err := s.svc.GetCurrency(ctx, req.GetCode())
+	if err != nil {
+		return nil, statusError("Failed to get currency", err)
+	}
+	return transport.CurrencyToProto(data), nil
 }
 
 func (s *CurrencyServer) UpdateCurrency(ctx context.Context, req *proto.Currency) (*proto.Currency, error) {
-	switch {
-	case req == nil:
-		return nil, status.Errorf(codes.InvalidArgument, "Request body cannot be empty")
-	case req.Code == "":
-		return nil, status.Errorf(codes.InvalidArgument, "Currency code is required for update")
-	case req.Rate <= 0:
-		return nil, status.Errorf(codes.InvalidArgument, "Exchange rate must be a positive value")
-	case req.Name == "":
-		return nil, status.Errorf(codes.InvalidArgument, "Currency name cannot be empty")
-	case req.Symbol == "":
-		return nil, status.Errorf(codes.InvalidArgument, "Currency symbol cannot be empty")
-	}
-	
-	cur := &model.Currency{
-		Code:   req.Code,
-		Rate:   req.Rate,
-		Name:   req.Name,
-		Symbol: req.Symbol,
-	}
-	
-	updated, err := s.svc.UpdateCurrency(cur)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed to update currency: %v", err)
-	}
-	
-	return &proto.Currency{
-		Code:   updated.Code,
-		Rate:   updated.Rate,
-		Name:   updated.Name,
-		Symbol: updated.Symbol,
-	}, nil
+	updated, err := s.svc.UpdateCurrency(ctx, fromProtoCurrency(req))
+	if err != nil {
+		return nil, statusError("Failed to update currency", err)
+	}
+	return transport.CurrencyToProto(updated), nil
+}
+
+func (s *CurrencyServer) DeleteCurrency(ctx context.Context, req *proto.Currency) (*emptypb.Empty, error) {
+	if err := s.svc.DeleteCurrency(ctx, req.GetCode()); err != nil {
+		return nil, statusError("Failed to delete currency", err)
+	}
+	return &emptypb.Empty{}, nil
 }
 
 // *********************************Conversions*****************************************
@@ -139,63 +150,284 @@ func NewConversionServer(svc service.Service) *ConversionServer {
 }
 
 func (s *ConversionServer) ListConversions(ctx context.Context, _ *emptypb.Empty) (*proto.ListConversionsResponse, error) {
-	data, err := s.svc.ListConversions()
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed to retrieve conversion history: %v", err)
-	}
-
-	result := make([]*proto.Conversion, 0, len(data))
-	for _, v := range data {
-		result = append(result, &proto.Conversion{
-			Amount: v.Amount,
-			From: &proto.Currency{
-				Code:   v.From.Code,
-				Rate:   v.From.Rate,
-				Name:   v.From.Name,
-				Symbol: v.From.Symbol,
-			},
-			To: &proto.Currency{
-				Code:   v.To.Code,
-				Rate:   v.To.Rate,
-				Name:   v.To.Name,
-				Symbol: v.To.Symbol,
-			},
-			Result: v.Result,
-		})
+	data, err := s.svc.ListConversions(ctx)
+	if err != nil {
+		return nil, statusError("Failed to retrieve conversion history", err)
 	}
-	return &proto.ListConversionsResponse{Conversions: result}, nil
+	return transport.ConversionsToProto(data), nil
 }
 
 func (s *ConversionServer) CreateConversion(ctx context.Context, req *proto.CreateConversionRequest) (*proto.Conversion, error) {
-	if req.Amount <= 0 {
-		return nil, status.Errorf(codes.InvalidArgument, "Conversion amount must be greater than zero")
-	}
-	if req.From == "" {
-		return nil, status.Errorf(codes.InvalidArgument, "Source currency code is required")
-	}
-	if req.To == "" {
-		return nil, status.Errorf(codes.InvalidArgument, "Target currency code is required")
-	}
-
-	conv, err := s.svc.CreateConversion(req.Amount, req.From, req.To)
-	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "Conversion failed: %v", err)
-	}
-
-	return &proto.Conversion{
-		Amount: req.Amount,
-		From: &proto.Currency{
-			Code:   conv.From.Code,
-			Rate:   conv.From.Rate,
-			Name:   conv.From.Name,
-			Symbol: conv.From.Symbol,
-		},
-		To: &proto.Currency{
-			Code:   conv.To.Code,
-			Rate:   conv.To.Rate,
-			Name:   conv.To.Name,
-			Symbol: conv.To.Symbol,
-		},
-		Result: conv.Result,
-	}, nil
-}
\ No newline at end of file
+	conv, err := s.svc.CreateConversion(ctx, req.GetAmount(), req.GetFrom(), req.GetTo())
+	if err != nil {
+		return nil, statusError("Conversion failed", err)
+	}
+	return transport.ConversionToProto(conv), nil
+}
+
+// *********************************Alerts*****************************************
+
+type AlertServer struct {
+	proto.UnimplementedAlertServiceServer
+	svc service.Service
+}
+
+func NewAlertServer(svc service.Service) *AlertServer {
+	return &AlertServer{svc: svc}
+}
+
+func toProtoAlert(rule *model.AlertRule) *proto.AlertRule {
+	res := &proto.AlertRule{
+		Id:              rule.ID,
+		Base:            rule.Base,
+		Quote:           rule.Quote,
+		Condition:       string(rule.Condition),
+		Threshold:       rule.Threshold,
+		CooldownSeconds: rule.CooldownSeconds,
+		WebhookUrl:      rule.WebhookURL,
+		CreatedAt:       timestamppb.New(rule.CreatedAt),
+		LastRate:        rule.LastRate,
+	}
+	if rule.LastFiredAt != nil {
+		res.LastFiredAt = timestamppb.New(*rule.LastFiredAt)
+	}
+	return res
+}
+
+func fromProtoAlertRequest(req *proto.AlertRuleRequest) *model.AlertRequest {
+	return &model.AlertRequest{
+		Base:            req.GetBase(),
+		Quote:           req.GetQuote(),
+		Condition:       model.AlertCondition(req.GetCondition()),
+		Threshold:       req.GetThreshold(),
+		CooldownSeconds: req.GetCooldownSeconds(),
+		WebhookURL:      req.GetWebhookUrl(),
+	}
+}
+
+func (s *AlertServer) CreateAlert(ctx context.Context, req *proto.AlertRuleRequest) (*proto.AlertRule, error) {
+	rule, err := s.svc.CreateAlert(ctx, fromProtoAlertRequest(req))
+	if err != nil {
+		return nil, statusError("Failed to create alert", err)
+	}
+	return toProtoAlert(rule), nil
+}
+
+func (s *AlertServer) GetAlert(ctx context.Context, req *proto.AlertID) (*proto.AlertRule, error) {
+	rule, err := s.svc.GetAlert(ctx, req.GetId())
+	if err != nil {
+		return nil, statusError("Failed to get alert", err)
+	}
+	return toProtoAlert(rule), nil
+}
+
+func (s *AlertServer) ListAlerts(ctx context.Context, _ *emptypb.Empty) (*proto.ListAlertsResponse, error) {
+	rules, err := s.svc.ListAlerts(ctx)
+	if err != nil {
+		return nil, statusError("Failed to retrieve alerts", err)
+	}
+
+	result := make([]*proto.AlertRule, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, toProtoAlert(rule))
+	}
+	return &proto.ListAlertsResponse{Alerts: result}, nil
+}
+
+func (s *AlertServer) UpdateAlert(ctx context.Context, req *proto.UpdateAlertRequest) (*proto.AlertRule, error) {
+	if req.Rule == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Alert rule is required")
+	}
+	rule, err := s.svc.UpdateAlert(ctx, req.GetId(), fromProtoAlertRequest(req.Rule))
+	if err != nil {
+		return nil, statusError("Failed to update alert", err)
+	}
+	return toProtoAlert(rule), nil
+}
+
+func (s *AlertServer) DeleteAlert(ctx context.Context, req *proto.AlertID) (*emptypb.Empty, error) {
+	if err := s.svc.DeleteAlert(ctx, req.GetId()); err != nil {
+		return nil, statusError("Failed to delete alert", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// *********************************Webhooks*****************************************
+
+type WebhookServer struct {
+	proto.UnimplementedWebhookServiceServer
+	svc service.Service
+}
+
+func NewWebhookServer(svc service.Service) *WebhookServer {
+	return &WebhookServer{svc: svc}
+}
+
+func toProtoWebhook(sub *model.WebhookSubscription) *proto.WebhookSubscription {
+	events := make([]string, 0, len(sub.Events))
+	for _, e := range sub.Events {
+		events = append(events, string(e))
+	}
+	return &proto.WebhookSubscription{
+		Id:        sub.ID,
+		Url:       sub.URL,
+		Events:    events,
+		Secret:    sub.Secret,
+		CreatedAt: timestamppb.New(sub.CreatedAt),
+	}
+}
+
+func toProtoDelivery(d *model.WebhookDelivery) *proto.WebhookDelivery {
+	res := &proto.WebhookDelivery{
+		Id:             d.ID,
+		EventId:        d.EventID,
+		SubscriptionId: d.SubscriptionID,
+		Url:            d.URL,
+		Event:          string(d.Event),
+		Payload:        string(d.Payload),
+		Status:         string(d.Status),
+		Attempts:       int32(d.Attempts),
+		ResponseCode:   int32(d.ResponseCode),
+		LastError:      d.LastError,
+		CreatedAt:      timestamppb.New(d.CreatedAt),
+	}
+	if d.LastAttemptAt != nil {
+		res.LastAttemptAt = timestamppb.New(*d.LastAttemptAt)
+	}
+	if d.NextAttemptAt != nil {
+		res.NextAttemptAt = timestamppb.New(*d.NextAttemptAt)
+	}
+	return res
+}
+
+func toProtoDeliveries(deliveries []*model.WebhookDelivery) *proto.ListDeliveriesResponse {
+	result := make([]*proto.WebhookDelivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		result = append(result, toProtoDelivery(d))
+	}
+	return &proto.ListDeliveriesResponse{Deliveries: result}
+}
+
+func (s *WebhookServer) CreateWebhook(ctx context.Context, req *proto.WebhookSubscriptionRequest) (*proto.WebhookSubscription, error) {
+	events := make([]model.WebhookEvent, 0, len(req.GetEvents()))
+	for _, e := range req.GetEvents() {
+		events = append(events, model.WebhookEvent(e))
+	}
+	sub, err := s.svc.CreateWebhook(ctx, &model.WebhookSubscriptionRequest{
+		URL:    req.GetUrl(),
+		Events: events,
+		Secret: req.GetSecret(),
+	})
+	if err != nil {
+		return nil, statusError("Failed to create webhook", err)
+	}
+	return toProtoWebhook(sub), nil
+}
+
+func (s *WebhookServer) GetWebhook(ctx context.Context, req *proto.WebhookID) (*proto.WebhookSubscription, error) {
+	sub, err := s.svc.GetWebhook(ctx, req.GetId())
+	if err != nil {
+		return nil, statusError("Failed to get webhook", err)
+	}
+	return toProtoWebhook(sub), nil
+}
+
+func (s *WebhookServer) ListWebhooks(ctx context.Context, _ *emptypb.Empty) (*proto.ListWebhooksResponse, error) {
+	subs, err := s.svc.ListWebhooks(ctx)
+	if err != nil {
+		return nil, statusError("Failed to retrieve webhooks", err)
+	}
+
+	result := make([]*proto.WebhookSubscription, 0, len(subs))
+	for _, sub := range subs {
+		result = append(result, toProtoWebhook(sub))
+	}
+	return &proto.ListWebhooksResponse{Webhooks: result}, nil
+}
+
+func (s *WebhookServer) DeleteWebhook(ctx context.Context, req *proto.WebhookID) (*emptypb.Empty, error) {
+	if err := s.svc.DeleteWebhook(ctx, req.GetId()); err != nil {
+		return nil, statusError("Failed to delete webhook", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *WebhookServer) ListDeliveries(ctx context.Context, req *proto.WebhookID) (*proto.ListDeliveriesResponse, error) {
+	deliveries, err := s.svc.ListWebhookDeliveries(ctx, req.GetId())
+	if err != nil {
+		return nil, statusError("Failed to retrieve webhook deliveries", err)
+	}
+	return toProtoDeliveries(deliveries), nil
+}
+
+func (s *WebhookServer) ListDeadLetters(ctx context.Context, _ *emptypb.Empty) (*proto.ListDeliveriesResponse, error) {
+	deliveries, err := s.svc.ListDeadLetters(ctx)
+	if err != nil {
+		return nil, statusError("Failed to retrieve dead letters", err)
+	}
+	return toProtoDeliveries(deliveries), nil
+}
+
+func (s *WebhookServer) RetryDelivery(ctx context.Context, req *proto.DeliveryID) (*proto.WebhookDelivery, error) {
+	d, err := s.svc.RetryDelivery(ctx, req.GetId())
+	if err != nil {
+		return nil, statusError("Failed to retry webhook delivery", err)
+	}
+	return toProtoDelivery(d), nil
+}
+
+// *********************************History*****************************************
+
+type HistoryServer struct {
+	proto.UnimplementedHistoryServiceServer
+	store *history.Store
+}
+
+func NewHistoryServer(store *history.Store) *HistoryServer {
+	return &HistoryServer{store: store}
+}
+
+func (s *HistoryServer) GetCandles(ctx context.Context, req *proto.CandlesRequest) (*proto.CandlesResponse, error) {
+	quote := req.GetQuote()
+	if quote == "" {
+		quote = "RUB"
+	}
+	interval := req.GetInterval()
+	if interval == "" {
+		interval = "1d"
+	}
+	iv, err := history.ParseInterval(interval)
+	if err != nil {
+		return nil, statusError("Invalid interval", err)
+	}
+
+	var from, to time.Time
+	if req.GetFrom() != nil {
+		from = req.GetFrom().AsTime()
+	}
+	if req.GetTo() != nil {
+		to = req.GetTo().AsTime()
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return nil, status.Error(codes.InvalidArgument, "'from' must be before 'to'")
+	}
+
+	res, err := s.store.Candles(req.GetBase(), quote, iv, from, to)
+	if err != nil {
+		return nil, statusError("Failed to build candles", err)
+	}
+
+	candles := make([]*proto.Candle, 0, len(res.Candles))
+	for _, c := range res.Candles {
+		candles = append(candles, &proto.Candle{
+			Start: timestamppb.New(c.Start),
+			End:   timestamppb.New(c.End),
+			Open:  c.Open,
+			High:  c.High,
+			Low:   c.Low,
+			Close: c.Close,
+			Days:  int32(c.Days),
+		})
+	}
+	return &proto.CandlesResponse{Base: res.Base, Quote: res.Quote, Interval: res.Interval, Candles: candles}, nil
+}
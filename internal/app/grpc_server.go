@@ -0,0 +1,196 @@
+package app
+
+import (
+	"context"
+	"currency-converter/internal/auth"
+	"currency-converter/internal/history"
+	"currency-converter/internal/logging"
+	"currency-converter/internal/metrics"
+	"currency-converter/internal/ratelimit"
+	"currency-converter/internal/recovery"
+	"currency-converter/internal/requestid"
+	"currency-converter/internal/service"
+	"currency-converter/internal/tlsconfig"
+	"currency-converter/internal/tracing"
+	"currency-converter/proto"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+type GRPCServer struct {
+	addr       string
+	grpcServer *grpc.Server
+	tls        bool
+	failed     chan error
+}
+
+// readerMethods - методы, доступные клиентам только для чтения; остальные требуют роли admin.
+var readerMethods = auth.MethodRoles{
+	healthpb.Health_Check_FullMethodName: auth.Public,
+	healthpb.Health_Watch_FullMethodName: auth.Public,
+	healthpb.Health_List_FullMethodName:  auth.Public,
+
+	reflectionpb.ServerReflection_ServerReflectionInfo_FullMethodName:        auth.Public,
+	reflectionv1alphapb.ServerReflection_ServerReflectionInfo_FullMethodName: auth.Public,
+
+	proto.CurrencyService_GetCurrency_FullMethodName:        auth.RoleReader,
+	proto.CurrencyService_ListCurrencies_FullMethodName:     auth.RoleReader,
+	proto.CurrencyService_ListMovers_FullMethodName:         auth.RoleReader,
+	proto.ConversionService_CreateConversion_FullMethodName: auth.RoleReader,
+	proto.ConversionService_ListConversions_FullMethodName:  auth.RoleReader,
+	proto.HistoryService_GetCandles_FullMethodName:          auth.RoleReader,
+}
+
+// GRPCOptions - транспортные настройки gRPC-сервера. Нулевые значения оставляют умолчания grpc-go.
+type GRPCOptions struct {
+	// TLS включается, если заданы сертификат и ключ; с TLSClientCAFile - mTLS
+	TLSCertFile     string
+	TLSKeyFile      string
+	TLSClientCAFile string
+
+	// Reflection регистрирует grpc.reflection для grpcurl и подобных инструментов
+	Reflection bool
+
+	MaxRecvMsgSize int
+	MaxSendMsgSize int
+
+	// KeepaliveTime и KeepaliveTimeout - пинги сервера простаивающему клиенту;
+	// KeepaliveMinTime - минимальный интервал пингов клиента, чаще соединение закрывается
+	KeepaliveTime     time.Duration
+	KeepaliveTimeout  time.Duration
+	KeepaliveMinTime  time.Duration
+	MaxConnectionIdle time.Duration
+}
+
+func (o GRPCOptions) serverOptions() ([]grpc.ServerOption, error) {
+	var opts []grpc.ServerOption
+	if o.TLSCertFile != "" || o.TLSKeyFile != "" {
+		tlsCfg, err := tlsconfig.Server(o.TLSCertFile, o.TLSKeyFile, o.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("grpc tls: %w", err)
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+	} else if o.TLSClientCAFile != "" {
+		return nil, errors.New("grpc tls: client CA requires server certificate and key")
+	}
+
+	if o.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(o.MaxRecvMsgSize))
+	}
+	if o.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(o.MaxSendMsgSize))
+	}
+
+	opts = append(opts,
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:              o.KeepaliveTime,
+			Timeout:           o.KeepaliveTimeout,
+			MaxConnectionIdle: o.MaxConnectionIdle,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             o.KeepaliveMinTime,
+			PermitWithoutStream: true,
+		}),
+	)
+	return opts, nil
+}
+
+func NewGRPCServer(addr string, svc service.Service, rateHistory *history.Store, authn *auth.Authenticator, limiter *ratelimit.Limiter, options GRPCOptions) (*GRPCServer, error) {
+	opts, err := options.serverOptions()
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts,
+		grpc.StatsHandler(tracing.GRPCServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			requestid.UnaryServerInterceptor(),
+			logging.UnaryServerInterceptor(),
+			metrics.UnaryServerInterceptor(),
+			recovery.UnaryServerInterceptor(),
+			limiter.AuthUnaryServerInterceptor(),
+			authn.UnaryServerInterceptor(readerMethods),
+			limiter.UnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			requestid.StreamServerInterceptor(),
+			logging.StreamServerInterceptor(),
+			metrics.StreamServerInterceptor(),
+			recovery.StreamServerInterceptor(),
+			limiter.AuthStreamServerInterceptor(),
+			authn.StreamServerInterceptor(readerMethods),
+			limiter.StreamServerInterceptor(),
+		),
+	)
+	grpcServer := grpc.NewServer(opts...)
+
+	proto.RegisterCurrencyServiceServer(grpcServer, NewCurrencyServer(svc))
+	proto.RegisterConversionServiceServer(grpcServer, NewConversionServer(svc))
+	proto.RegisterAlertServiceServer(grpcServer, NewAlertServer(svc))
+	proto.RegisterWebhookServiceServer(grpcServer, NewWebhookServer(svc))
+	proto.RegisterHistoryServiceServer(grpcServer, NewHistoryServer(rateHistory))
+	if options.Reflection {
+		reflection.Register(grpcServer)
+	}
+
+	return &GRPCServer{
+		addr:       addr,
+		grpcServer: grpcServer,
+		tls:        options.TLSCertFile != "",
+		failed:     make(chan error, 1),
+	}, nil
+}
+
+// Register добавляет дополнительный сервис (например, grpc.health.v1); вызывать до Start.
+func (s *GRPCServer) Register(desc *grpc.ServiceDesc, impl any) {
+	s.grpcServer.RegisterService(desc, impl)
+}
+
+func (s *GRPCServer) Name() string { return "gRPC server" }
+
+func (s *GRPCServer) Start(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "gRPC server listening", "addr", s.addr, "tls", s.tls)
+	go func() {
+		if err := s.grpcServer.Serve(lis); err != nil {
+			s.failed <- err
+		}
+	}()
+	return nil
+}
+
+func (s *GRPCServer) Failed() <-chan error {
+	return s.failed
+}
+
+// Stop дожидается завершения активных вызовов; если дедлайн истёк,
+// оставшиеся соединения закрываются принудительно.
+func (s *GRPCServer) Stop(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		return ctx.Err()
+	}
+}
@@ -2,8 +2,17 @@ package app
 
 import (
 	"context"
+	"currency-converter/internal/auth"
 	"currency-converter/internal/handler"
-	"log"
+	"currency-converter/internal/health"
+	"currency-converter/internal/logging"
+	"currency-converter/internal/metrics"
+	"currency-converter/internal/ratelimit"
+	"currency-converter/internal/requestid"
+	"currency-converter/internal/tracing"
+	"errors"
+	"log/slog"
+	"net"
 	"net/http"
 
 	httpSwagger "github.com/swaggo/http-swagger"
@@ -13,37 +22,97 @@ type Server struct {
 	httpServer  *http.Server
 	curHandler  *handler.CurrencyHandler
 	convHandler *handler.ConversionHandler
+	failed      chan error
 }
 
-func New(addr string, curHand *handler.CurrencyHandler, convHand *handler.ConversionHandler) *Server {
+func New(addr string, curHand *handler.CurrencyHandler, convHand *handler.ConversionHandler, auditHand *handler.AuditHandler, alertHand *handler.AlertHandler, webhookHand *handler.WebhookHandler, historyHand *handler.HistoryHandler, transferHand *handler.TransferHandler, checker *health.Checker, authn *auth.Authenticator, limiter *ratelimit.Limiter) *Server {
 	mux := http.NewServeMux()
 
-	mux.HandleFunc("POST /currency", curHand.CreateCurrency)
-	mux.HandleFunc("GET /currency/{code}", curHand.GetCurrency)
-	mux.HandleFunc("GET /currencies", curHand.ListCurrencies)
-	mux.HandleFunc("PUT /currency/{code}", curHand.UpdateCurrency)
+	// Неудачные попытки аутентификации ограничиваются по адресу до неё, а лимит маршрута
+	// проверяется после, чтобы клиента можно было учитывать по имени.
+	reader := func(h http.HandlerFunc) http.Handler {
+		return limiter.AuthMiddleware(authn.Require(auth.RoleReader, limiter.Middleware(h).ServeHTTP))
+	}
+	admin := func(h http.HandlerFunc) http.Handler {
+		return limiter.AuthMiddleware(authn.Require(auth.RoleAdmin, limiter.Middleware(h).ServeHTTP))
+	}
+
+	mux.Handle("POST /currency", admin(curHand.CreateCurrency))
+	mux.Handle("POST /currency/upsert", admin(curHand.UpsertCurrency))
+	mux.Handle("GET /currency/{code}", reader(curHand.GetCurrency))
+	mux.Handle("GET /currencies", reader(curHand.ListCurrencies))
+	mux.Handle("GET /currency/{code}/stats", reader(historyHand.RateStats))
+	mux.Handle("GET /currency/{code}/candles", reader(historyHand.Candles))
+	mux.Handle("GET /rates/movers", reader(curHand.ListMovers))
+	mux.Handle("PUT /currency/{code}", admin(curHand.UpdateCurrency))
+	mux.Handle("DELETE /currency/{code}", admin(curHand.DeleteCurrency))
+
+	mux.Handle("PUT /currency/{code}/override", admin(curHand.SetOverride))
+	mux.Handle("DELETE /currency/{code}/override", admin(curHand.ClearOverride))
+	mux.Handle("GET /overrides", reader(curHand.ListOverrides))
+
+	mux.Handle("POST /conversion", reader(convHand.CreateConversion))
+	mux.Handle("GET /conversions", reader(convHand.ListConversions))
+	mux.Handle("GET /conversions/report", reader(convHand.ConversionReport))
+
+	mux.Handle("GET /admin/audit", admin(auditHand.ListAudit))
+	mux.Handle("GET /admin/export", admin(transferHand.Export))
+	mux.Handle("POST /admin/import", admin(transferHand.Import))
 
-	mux.HandleFunc("POST /conversion", convHand.CreateConversion)
-	mux.HandleFunc("GET /conversions", convHand.ListConversions)
+	mux.Handle("POST /alerts", admin(alertHand.CreateAlert))
+	mux.Handle("GET /alerts", admin(alertHand.ListAlerts))
+	mux.Handle("GET /alerts/{id}", admin(alertHand.GetAlert))
+	mux.Handle("PUT /alerts/{id}", admin(alertHand.UpdateAlert))
+	mux.Handle("DELETE /alerts/{id}", admin(alertHand.DeleteAlert))
+
+	mux.Handle("POST /webhooks", admin(webhookHand.CreateWebhook))
+	mux.Handle("GET /webhooks", admin(webhookHand.ListWebhooks))
+	mux.Handle("GET /webhooks/dead-letters", admin(webhookHand.ListDeadLetters))
+	mux.Handle("GET /webhooks/{id}", admin(webhookHand.GetWebhook))
+	mux.Handle("DELETE /webhooks/{id}", admin(webhookHand.DeleteWebhook))
+	mux.Handle("GET /webhooks/{id}/deliveries", admin(webhookHand.ListDeliveries))
+	mux.Handle("POST /webhooks/deliveries/{id}/retry", admin(webhookHand.RetryDelivery))
 
 	mux.Handle("/swagger/", httpSwagger.WrapHandler)
+	mux.Handle("GET /metrics", metrics.Handler())
+	mux.HandleFunc("GET /healthz", checker.Liveness)
+	mux.HandleFunc("GET /readyz", checker.Readiness)
 
 	return &Server{
 		httpServer: &http.Server{
 			Addr:    addr,
-			Handler: mux,
+			Handler: requestid.Middleware(tracing.HTTPMiddleware(logging.HTTPMiddleware(metrics.HTTPMiddleware(mux)))),
 		},
 		curHandler:  curHand,
 		convHandler: convHand,
+		failed:      make(chan error, 1),
+	}
+}
+
+func (s *Server) Name() string { return "REST server" }
+
+// Start открывает порт синхронно, чтобы ошибка занятого адреса вернулась сразу,
+// а запросы обслуживает в отдельной горутине.
+func (s *Server) Start(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return err
 	}
+
+	slog.InfoContext(ctx, "REST server listening", "addr", s.httpServer.Addr)
+	go func() {
+		if err := s.httpServer.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.failed <- err
+		}
+	}()
+	return nil
 }
 
-func (s *Server) Start() error {
-	log.Println("REST server starting on port", s.httpServer.Addr)
-	return s.httpServer.ListenAndServe()
+func (s *Server) Failed() <-chan error {
+	return s.failed
 }
 
 func (s *Server) Stop(ctx context.Context) error {
-	log.Println("Initiating server shutdown...")
+	slog.InfoContext(ctx, "REST server shutting down")
 	return s.httpServer.Shutdown(ctx)
 }
@@ -0,0 +1,153 @@
+package app
+
+import (
+	"context"
+	"currency-converter/internal/handler"
+	"currency-converter/internal/tracing"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"currency-converter/proto"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// Родительский спан "клиента" в формате W3C traceparent.
+const (
+	remoteTraceID   = "4bf92f3577b34da6a3ce929d0e0e4736"
+	remoteSpanID    = "00f067aa0ba902b7"
+	remoteParent    = "00-" + remoteTraceID + "-" + remoteSpanID + "-01"
+	grpcGetCurrency = "CurrencyConverter.CurrencyService/GetCurrency"
+)
+
+// spanExporter регистрирует провайдер один раз: глобальные трейсеры пакетов
+// привязываются к первому установленному провайдеру.
+var spanExporter = sync.OnceValue(func() *tracetest.InMemoryExporter {
+	exp := tracetest.NewInMemoryExporter()
+	tracing.NewProvider(sdktrace.WithSyncer(exp))
+	return exp
+})
+
+// findSpan находит спан с именем name, чьим родителем является parent. Серверный
+// gRPC-спан закрывается уже после ответа клиенту, поэтому спан ждём недолго.
+func findSpan(t *testing.T, exp *tracetest.InMemoryExporter, parent trace.SpanContext, name string) tracetest.SpanStub {
+	t.Helper()
+	var names []string
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		names = names[:0]
+		for _, s := range exp.GetSpans() {
+			if s.Name == name && s.Parent.SpanID() == parent.SpanID() && s.SpanContext.TraceID() == parent.TraceID() {
+				return s
+			}
+			names = append(names, s.Name)
+		}
+	}
+	t.Fatalf("no span %q under %s; recorded: %s", name, parent.SpanID(), strings.Join(names, ", "))
+	return tracetest.SpanStub{}
+}
+
+func remoteSpanContext(t *testing.T) trace.SpanContext {
+	t.Helper()
+	traceID, err := trace.TraceIDFromHex(remoteTraceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spanID, err := trace.SpanIDFromHex(remoteSpanID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, Remote: true})
+}
+
+func TestRESTSpans(t *testing.T) {
+	exp := spanExporter()
+	svc := newCancelTestService(t)
+	exp.Reset()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /currency/{code}", handler.NewCurrencyHandler(svc).GetCurrency)
+	srv := httptest.NewServer(tracing.HTTPMiddleware(mux))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/currency/USD", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("traceparent", remoteParent)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	srv.Close() // дожидаемся завершения серверного спана
+
+	route := findSpan(t, exp, remoteSpanContext(t), "GET /currency/{code}")
+	if route.SpanKind != trace.SpanKindServer {
+		t.Errorf("route span kind = %v, want server", route.SpanKind)
+	}
+	findSpan(t, exp, route.SpanContext, "service.GetCurrency")
+}
+
+func TestGRPCSpans(t *testing.T) {
+	exp := spanExporter()
+	svc := newCancelTestService(t)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer(grpc.StatsHandler(tracing.GRPCServerHandler()))
+	proto.RegisterCurrencyServiceServer(server, NewCurrencyServer(svc))
+	go server.Serve(lis)
+	defer server.Stop()
+
+	dial := func(t *testing.T, opts ...grpc.DialOption) proto.CurrencyServiceClient {
+		conn, err := grpc.NewClient(lis.Addr().String(),
+			append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return proto.NewCurrencyServiceClient(conn)
+	}
+
+	t.Run("client span is propagated", func(t *testing.T) {
+		client := dial(t, grpc.WithStatsHandler(tracing.GRPCClientHandler()))
+		exp.Reset()
+		ctx, root := otel.Tracer("test").Start(context.Background(), "test")
+		client.GetCurrency(ctx, &proto.Currency{Code: "USD"})
+		root.End()
+
+		call := findSpan(t, exp, root.SpanContext(), grpcGetCurrency)
+		if call.SpanKind != trace.SpanKindClient {
+			t.Fatalf("span kind = %v, want client", call.SpanKind)
+		}
+		method := findSpan(t, exp, call.SpanContext, grpcGetCurrency)
+		if method.SpanKind != trace.SpanKindServer {
+			t.Errorf("span kind = %v, want server", method.SpanKind)
+		}
+		findSpan(t, exp, method.SpanContext, "service.GetCurrency")
+	})
+
+	t.Run("traceparent in metadata", func(t *testing.T) {
+		// Клиент без инструментирования: заголовок передаётся как есть.
+		client := dial(t)
+		exp.Reset()
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "traceparent", remoteParent)
+		client.GetCurrency(ctx, &proto.Currency{Code: "USD"})
+
+		method := findSpan(t, exp, remoteSpanContext(t), grpcGetCurrency)
+		findSpan(t, exp, method.SpanContext, "service.GetCurrency")
+	})
+}
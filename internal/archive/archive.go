@@ -0,0 +1,413 @@
+// Package archive описывает версионированный архив для переноса данных между
+// окружениями: zip с manifest.json и разделами currencies, rate_history и
+// conversions в формате JSON Lines или CSV.
+package archive
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"currency-converter/internal/history"
+	"currency-converter/internal/model"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Version - версия формата, которую пишет Write. Read принимает её и более ранние.
+const Version = 1
+
+type Format string
+
+const (
+	FormatJSONL Format = "jsonl"
+	FormatCSV   Format = "csv"
+)
+
+var ErrInvalid = errors.New("invalid archive")
+
+// MaxUnpackedSize ограничивает суммарный размер распакованных файлов архива
+// в байтах, чтобы маленький zip не развернулся в гигабайты в памяти.
+const MaxUnpackedSize = 512 << 20
+
+var errTooLarge = errors.New("unpacked data is too large")
+
+const manifestFile = "manifest.json"
+
+// Manifest - описание архива; число записей сверяется при чтении, чтобы заметить обрезанный файл.
+type Manifest struct {
+	Version     int       `json:"version"`
+	Format      Format    `json:"format"`
+	CreatedAt   time.Time `json:"created_at"`
+	Currencies  int       `json:"currencies"`
+	RateHistory int       `json:"rate_history"`
+	Conversions int       `json:"conversions"`
+}
+
+type Archive struct {
+	Manifest    Manifest
+	Currencies  []*model.Currency
+	RateHistory []history.Observation
+	Conversions []*model.Conversion
+}
+
+// section - раздел архива: имя файла без расширения, заголовок CSV и перевод записей в строки CSV и обратно.
+type section struct {
+	name   string
+	header []string
+	count  func(a *Archive) int
+	rows   func(a *Archive) [][]string
+	encode func(a *Archive, encoder *json.Encoder) error
+	// jsonRecord и csvRecord разбирают одну запись и добавляют её в архив.
+	jsonRecord func(a *Archive, data []byte) error
+	csvRecord  func(a *Archive, row []string) error
+}
+
+var sections = []section{
+	{
+		name:   "currencies",
+		header: []string{"code", "rate", "name", "symbol", "previous_rate"},
+		count:  func(a *Archive) int { return len(a.Currencies) },
+		encode: func(a *Archive, encoder *json.Encoder) error { return encodeAll(encoder, a.Currencies) },
+		rows: func(a *Archive) [][]string {
+			rows := make([][]string, 0, len(a.Currencies))
+			for _, c := range a.Currencies {
+				rows = append(rows, []string{c.Code, formatFloat(c.Rate), c.Name, c.Symbol, formatFloat(c.PreviousRate)})
+			}
+			return rows
+		},
+		jsonRecord: func(a *Archive, data []byte) error {
+			var c model.Currency
+			if err := json.Unmarshal(data, &c); err != nil {
+				return err
+			}
+			a.Currencies = append(a.Currencies, &c)
+			return nil
+		},
+		csvRecord: func(a *Archive, row []string) error {
+			rate, err := parseFloat(row[1])
+			if err != nil {
+				return fmt.Errorf("rate: %w", err)
+			}
+			previous, err := parseFloat(row[4])
+			if err != nil {
+				return fmt.Errorf("previous_rate: %w", err)
+			}
+			c := model.NewCurrency(row[0], rate, row[2], row[3])
+			c.SetPrevious(previous)
+			a.Currencies = append(a.Currencies, c)
+			return nil
+		},
+	},
+	{
+		name:   "rate_history",
+		header: []string{"time", "code", "rate"},
+		count:  func(a *Archive) int { return len(a.RateHistory) },
+		encode: func(a *Archive, encoder *json.Encoder) error { return encodeAll(encoder, a.RateHistory) },
+		rows: func(a *Archive) [][]string {
+			rows := make([][]string, 0, len(a.RateHistory))
+			for _, o := range a.RateHistory {
+				rows = append(rows, []string{formatTime(o.Time), o.Code, formatFloat(o.Rate)})
+			}
+			return rows
+		},
+		jsonRecord: func(a *Archive, data []byte) error {
+			var o history.Observation
+			if err := json.Unmarshal(data, &o); err != nil {
+				return err
+			}
+			a.RateHistory = append(a.RateHistory, o)
+			return nil
+		},
+		csvRecord: func(a *Archive, row []string) error {
+			t, err := parseTime(row[0])
+			if err != nil {
+				return fmt.Errorf("time: %w", err)
+			}
+			rate, err := parseFloat(row[2])
+			if err != nil {
+				return fmt.Errorf("rate: %w", err)
+			}
+			a.RateHistory = append(a.RateHistory, history.Observation{Time: t, Code: row[1], Rate: rate})
+			return nil
+		},
+	},
+	{
+		name: "conversions",
+		header: []string{"created_at", "amount", "from_code", "from_rate", "from_name", "from_symbol",
+			"to_code", "to_rate", "to_name", "to_symbol", "result"},
+		count:  func(a *Archive) int { return len(a.Conversions) },
+		encode: func(a *Archive, encoder *json.Encoder) error { return encodeAll(encoder, a.Conversions) },
+		rows: func(a *Archive) [][]string {
+			rows := make([][]string, 0, len(a.Conversions))
+			for _, c := range a.Conversions {
+				row := []string{formatTime(c.CreatedAt), formatFloat(c.Amount)}
+				row = append(row, currencyColumns(c.From)...)
+				row = append(row, currencyColumns(c.To)...)
+				rows = append(rows, append(row, formatFloat(c.Result)))
+			}
+			return rows
+		},
+		jsonRecord: func(a *Archive, data []byte) error {
+			var c model.Conversion
+			if err := json.Unmarshal(data, &c); err != nil {
+				return err
+			}
+			a.Conversions = append(a.Conversions, &c)
+			return nil
+		},
+		csvRecord: func(a *Archive, row []string) error {
+			t, err := parseTime(row[0])
+			if err != nil {
+				return fmt.Errorf("created_at: %w", err)
+			}
+			amount, err := parseFloat(row[1])
+			if err != nil {
+				return fmt.Errorf("amount: %w", err)
+			}
+			from, err := parseCurrencyColumns(row[2:6])
+			if err != nil {
+				return fmt.Errorf("from_rate: %w", err)
+			}
+			to, err := parseCurrencyColumns(row[6:10])
+			if err != nil {
+				return fmt.Errorf("to_rate: %w", err)
+			}
+			result, err := parseFloat(row[10])
+			if err != nil {
+				return fmt.Errorf("result: %w", err)
+			}
+			a.Conversions = append(a.Conversions, &model.Conversion{Amount: amount, From: from, To: to, Result: result, CreatedAt: t})
+			return nil
+		},
+	},
+}
+
+// Write записывает архив версии Version в формате format; Manifest заполняется здесь.
+func Write(w io.Writer, a *Archive, format Format) error {
+	if format != FormatJSONL && format != FormatCSV {
+		return fmt.Errorf("%w: unsupported format %q", ErrInvalid, format)
+	}
+	a.Manifest = Manifest{
+		Version:     Version,
+		Format:      format,
+		CreatedAt:   time.Now().UTC(),
+		Currencies:  len(a.Currencies),
+		RateHistory: len(a.RateHistory),
+		Conversions: len(a.Conversions),
+	}
+
+	zw := zip.NewWriter(w)
+	f, err := zw.Create(manifestFile)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(a.Manifest); err != nil {
+		return err
+	}
+
+	for _, sec := range sections {
+		f, err := zw.Create(sec.name + "." + string(format))
+		if err != nil {
+			return err
+		}
+		if format == FormatCSV {
+			cw := csv.NewWriter(f)
+			cw.Write(sec.header)
+			cw.WriteAll(sec.rows(a))
+			if err := cw.Error(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := sec.encode(a, json.NewEncoder(f)); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func encodeAll[T any](encoder *json.Encoder, records []T) error {
+	for _, r := range records {
+		if err := encoder.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Read разбирает архив. Отсутствующий раздел считается пустым, но число записей
+// каждого раздела должно совпасть с манифестом.
+func Read(data []byte) (*Archive, error) {
+	return read(data, MaxUnpackedSize)
+}
+
+// read - Read с заданным пределом распакованных данных.
+func read(data []byte, maxUnpacked int64) (*Archive, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	a := &Archive{}
+	left := maxUnpacked
+	mf, ok := files[manifestFile]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is missing", ErrInvalid, manifestFile)
+	}
+	if err := readFile(mf, &left, func(r io.Reader) error { return json.NewDecoder(r).Decode(&a.Manifest) }); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalid, manifestFile, err)
+	}
+	m := a.Manifest
+	if m.Version < 1 || m.Version > Version {
+		return nil, fmt.Errorf("%w: unsupported version %d, expected 1..%d", ErrInvalid, m.Version, Version)
+	}
+	if m.Format != FormatJSONL && m.Format != FormatCSV {
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrInvalid, m.Format)
+	}
+
+	expected := map[string]int{"currencies": m.Currencies, "rate_history": m.RateHistory, "conversions": m.Conversions}
+	for _, sec := range sections {
+		name := sec.name + "." + string(m.Format)
+		if f, ok := files[name]; ok {
+			limit := expected[sec.name]
+			read := func(r io.Reader) error { return readJSONL(r, a, sec, limit) }
+			if m.Format == FormatCSV {
+				read = func(r io.Reader) error { return readCSV(r, a, sec, limit) }
+			}
+			if err := readFile(f, &left, read); err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", ErrInvalid, name, err)
+			}
+		}
+		if got := sec.count(a); got != expected[sec.name] {
+			return nil, fmt.Errorf("%w: %s has %d records, manifest says %d", ErrInvalid, name, got, expected[sec.name])
+		}
+	}
+	return a, nil
+}
+
+// readFile распаковывает файл, расходуя общий бюджет left. Размер из заголовка zip
+// проверяется заранее, но ему нельзя верить, поэтому считаются и прочитанные байты.
+func readFile(f *zip.File, left *int64, fn func(io.Reader) error) error {
+	if f.UncompressedSize64 > uint64(*left) {
+		return errTooLarge
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	// Лишний байт отличает файл ровно в бюджет от файла больше него.
+	lr := &io.LimitedReader{R: rc, N: *left + 1}
+	err = fn(lr)
+	if lr.N == 0 {
+		return errTooLarge
+	}
+	*left = lr.N - 1
+	return err
+}
+
+// readJSONL и readCSV прекращают чтение, как только записей больше, чем limit из манифеста.
+func readJSONL(r io.Reader, a *Archive, sec section, limit int) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		if err := sec.jsonRecord(a, scanner.Bytes()); err != nil {
+			return fmt.Errorf("line %d: %v", line, err)
+		}
+		if sec.count(a) > limit {
+			return fmt.Errorf("line %d: more records than the manifest says (%d)", line, limit)
+		}
+	}
+	return scanner.Err()
+}
+
+func readCSV(r io.Reader, a *Archive, sec section, limit int) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(sec.header)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for i, col := range sec.header {
+		if header[i] != col {
+			return fmt.Errorf("unexpected header, expected %v", sec.header)
+		}
+	}
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := sec.csvRecord(a, row); err != nil {
+			return fmt.Errorf("line %d: %v", line, err)
+		}
+		if sec.count(a) > limit {
+			return fmt.Errorf("line %d: more records than the manifest says (%d)", line, limit)
+		}
+	}
+}
+
+func currencyColumns(c *model.Currency) []string {
+	if c == nil {
+		return []string{"", "", "", ""}
+	}
+	return []string{c.Code, formatFloat(c.Rate), c.Name, c.Symbol}
+}
+
+func parseCurrencyColumns(cols []string) (*model.Currency, error) {
+	if cols[0] == "" {
+		return nil, nil
+	}
+	rate, err := parseFloat(cols[1])
+	if err != nil {
+		return nil, err
+	}
+	return model.NewCurrency(cols[0], rate, cols[2], cols[3]), nil
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func parseFloat(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// formatTime и parseTime - RFC 3339 с наносекундами; пустая строка - нулевое время.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
@@ -0,0 +1,102 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"currency-converter/internal/model"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// zipFiles собирает zip из файлов name -> содержимое.
+func zipFiles(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func manifest(currencies int) string {
+	return fmt.Sprintf(`{"version": 1, "format": "jsonl", "currencies": %d}`, currencies)
+}
+
+func TestReadRoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatJSONL, FormatCSV} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			in := &Archive{Currencies: []*model.Currency{model.NewCurrency("USD", 90, "US dollar", "$")}}
+			if err := Write(&buf, in, format); err != nil {
+				t.Fatal(err)
+			}
+			out, err := Read(buf.Bytes())
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if len(out.Currencies) != 1 || out.Currencies[0].Code != "USD" || out.Currencies[0].Rate != 90 {
+				t.Errorf("currencies = %+v, want USD at 90", out.Currencies)
+			}
+		})
+	}
+}
+
+func TestReadRejectsOversizedArchives(t *testing.T) {
+	line := `{"code":"USD","rate":90,"name":"US dollar","symbol":"$"}` + "\n"
+	// Сжимается в сотни раз: так и выглядит zip-бомба.
+	padding := strings.Repeat("\n", 1<<20)
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		limit   int64
+		wantErr string
+	}{
+		{
+			name:    "entry larger than the budget",
+			files:   map[string]string{manifestFile: manifest(1), "currencies.jsonl": line + padding},
+			limit:   64 << 10,
+			wantErr: errTooLarge.Error(),
+		},
+		{
+			name: "entries larger than the budget together",
+			files: map[string]string{
+				manifestFile:         manifest(1),
+				"currencies.jsonl":   line + padding[:40<<10],
+				"rate_history.jsonl": padding[:40<<10],
+			},
+			limit:   64 << 10,
+			wantErr: errTooLarge.Error(),
+		},
+		{
+			name:    "more records than the manifest says",
+			files:   map[string]string{manifestFile: manifest(1), "currencies.jsonl": line + line + padding},
+			limit:   MaxUnpackedSize,
+			wantErr: "line 2: more records than the manifest says (1)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := read(zipFiles(t, tt.files), tt.limit)
+			if !errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("read() error = %v, want %v containing %q", err, ErrInvalid, tt.wantErr)
+			}
+		})
+	}
+
+	// Тот же архив в пределах бюджета читается.
+	a, err := read(zipFiles(t, map[string]string{manifestFile: manifest(1), "currencies.jsonl": line + padding}), 2<<20)
+	if err != nil || len(a.Currencies) != 1 {
+		t.Fatalf("read() = %+v, %v; want one currency", a, err)
+	}
+}
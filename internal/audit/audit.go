@@ -0,0 +1,271 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"currency-converter/internal/auth"
+	"currency-converter/internal/requestid"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Source - откуда пришло изменение курса.
+type Source string
+
+const (
+	SourceAPI     Source = "api"
+	SourceCBRSync Source = "cbr_sync"
+	SourceSystem  Source = "system"
+	SourceImport  Source = "import"
+)
+
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Entry - запись журнала изменений курса. OldRate пуст для создания, NewRate - для удаления.
+type Entry struct {
+	Seq       uint64    `json:"seq"`
+	Timestamp time.Time `json:"timestamp"`
+	Actor     string    `json:"actor"`
+	Source    Source    `json:"source"`
+	Action    Action    `json:"action"`
+	Code      string    `json:"code"`
+	OldRate   *float64  `json:"old_rate,omitempty"`
+	NewRate   *float64  `json:"new_rate,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+}
+
+// Meta - кто и почему меняет данные; передаётся через контекст.
+type Meta struct {
+	Actor  string
+	Source Source
+	Reason string
+}
+
+type metaKey struct{}
+
+func WithMeta(ctx context.Context, m Meta) context.Context {
+	return context.WithValue(ctx, metaKey{}, m)
+}
+
+// WithReason добавляет причину изменения к уже заданным в контексте данным.
+func WithReason(ctx context.Context, reason string) context.Context {
+	m, _ := ctx.Value(metaKey{}).(Meta)
+	m.Reason = reason
+	return WithMeta(ctx, m)
+}
+
+// metaFromContext дополняет явно заданные данные аутентифицированным клиентом;
+// изменения без явного источника считаются пришедшими через API.
+func metaFromContext(ctx context.Context) Meta {
+	m, _ := ctx.Value(metaKey{}).(Meta)
+	if m.Source == "" {
+		m.Source = SourceAPI
+	}
+	if m.Actor == "" {
+		if p, ok := auth.FromContext(ctx); ok {
+			m.Actor = p.Subject
+		} else {
+			m.Actor = "anonymous"
+		}
+	}
+	return m
+}
+
+type Filter struct {
+	Code      string
+	Actor     string
+	Source    Source
+	RequestID string
+	From      time.Time
+	To        time.Time
+	Limit     int
+}
+
+func (f Filter) match(e *Entry) bool {
+	switch {
+	case f.Code != "" && e.Code != f.Code:
+		return false
+	case f.Actor != "" && e.Actor != f.Actor:
+		return false
+	case f.Source != "" && e.Source != f.Source:
+		return false
+	case f.RequestID != "" && e.RequestID != f.RequestID:
+		return false
+	case !f.From.IsZero() && e.Timestamp.Before(f.From):
+		return false
+	case !f.To.IsZero() && !e.Timestamp.Before(f.To):
+		return false
+	}
+	return true
+}
+
+// Log - журнал только на дозапись: каждая запись сразу сбрасывается на диск в формате JSON Lines.
+// Записи в памяти не хранятся, Query читает их из файла.
+type Log struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	size int64 // длина полностью записанной части файла
+	seq  uint64
+	now  func() time.Time
+}
+
+func Open(path string) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create audit directory: %w", err)
+	}
+
+	l := &Log{path: path, now: time.Now}
+	if err := l.load(path); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	l.file, l.size = file, info.Size()
+	return l, nil
+}
+
+func (l *Log) load(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+	defer file.Close()
+
+	return scan(file, func(e Entry) {
+		l.seq = max(l.seq, e.Seq)
+	})
+}
+
+// scan разбирает записи JSON Lines по порядку, пропуская пустые строки.
+func scan(r io.Reader, fn func(e Entry)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return fmt.Errorf("failed to parse audit log line %d: %w", line, err)
+		}
+		fn(e)
+	}
+	return scanner.Err()
+}
+
+// RecordChange записывает изменение курса валюты. Записи без фактического
+// изменения курса (повторная синхронизация с тем же значением) пропускаются.
+func (l *Log) RecordChange(ctx context.Context, action Action, code string, oldRate, newRate *float64) error {
+	if l == nil {
+		return nil
+	}
+	if action == ActionUpdate && oldRate != nil && newRate != nil && *oldRate == *newRate {
+		return nil
+	}
+
+	m := metaFromContext(ctx)
+	return l.append(Entry{
+		Actor:     m.Actor,
+		Source:    m.Source,
+		Action:    action,
+		Code:      code,
+		OldRate:   oldRate,
+		NewRate:   newRate,
+		Reason:    m.Reason,
+		RequestID: requestid.FromContext(ctx),
+	})
+}
+
+func (l *Log) append(e Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e.Seq = l.seq + 1
+	e.Timestamp = l.now().UTC()
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	line = append(line, '\n')
+	if _, err := l.file.Write(line); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync audit log: %w", err)
+	}
+
+	l.seq = e.Seq
+	l.size += int64(len(line))
+	return nil
+}
+
+// Query читает записи из файла в порядке их появления; при Limit > 0 - только последние Limit.
+// Запись в журнал при этом не блокируется: читается только часть файла, записанная до вызова.
+func (l *Log) Query(f Filter) ([]Entry, error) {
+	l.mu.Lock()
+	size := l.size
+	l.mu.Unlock()
+
+	file, err := os.Open(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	defer file.Close()
+
+	result := make([]Entry, 0)
+	err = scan(io.LimitReader(file, size), func(e Entry) {
+		if !f.match(&e) {
+			return
+		}
+		result = append(result, e)
+		// С лимитом хватает последних Limit записей, остальные отбрасываются по ходу чтения.
+		if f.Limit > 0 && len(result) >= 2*f.Limit {
+			result = append(result[:0], result[len(result)-f.Limit:]...)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if f.Limit > 0 && len(result) > f.Limit {
+		result = result[len(result)-f.Limit:]
+	}
+	return result, nil
+}
+
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+func RatePtr(rate float64) *float64 {
+	return &rate
+}
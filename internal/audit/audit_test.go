@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func openLog(t *testing.T, path string) *Log {
+	t.Helper()
+	l, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	return l
+}
+
+func seqs(entries []Entry) []uint64 {
+	result := make([]uint64, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, e.Seq)
+	}
+	return result
+}
+
+func TestQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l := openLog(t, path)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := start
+	l.now = func() time.Time { clock = clock.Add(time.Minute); return clock }
+
+	ctx := WithMeta(context.Background(), Meta{Actor: "cbr", Source: SourceCBRSync})
+	for k := range 10 {
+		code := "USD"
+		if k%2 == 1 {
+			code = "EUR"
+		}
+		if err := l.RecordChange(ctx, ActionUpdate, code, RatePtr(float64(k)), RatePtr(float64(k+1))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Курс не изменился - записи нет.
+	if err := l.RecordChange(ctx, ActionUpdate, "USD", RatePtr(1), RatePtr(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []uint64
+	}{
+		{name: "all", want: []uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+		{name: "code", filter: Filter{Code: "EUR"}, want: []uint64{2, 4, 6, 8, 10}},
+		{name: "limit keeps the last entries", filter: Filter{Code: "USD", Limit: 2}, want: []uint64{7, 9}},
+		{name: "limit of one", filter: Filter{Limit: 1}, want: []uint64{10}},
+		{name: "period", filter: Filter{From: start.Add(3 * time.Minute), To: start.Add(6 * time.Minute)}, want: []uint64{3, 4, 5}},
+		{name: "source", filter: Filter{Source: SourceAPI}, want: []uint64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := l.Query(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(seqs(got), tt.want) {
+				t.Errorf("seqs = %v, want %v", seqs(got), tt.want)
+			}
+		})
+	}
+
+	// После перезапуска записи читаются из того же файла, а нумерация продолжается.
+	l.Close()
+	reopened := openLog(t, path)
+	if err := reopened.RecordChange(ctx, ActionDelete, "USD", RatePtr(10), nil); err != nil {
+		t.Fatal(err)
+	}
+	got, err := reopened.Query(Filter{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(seqs(got), []uint64{10, 11}) || got[1].Action != ActionDelete || got[1].Actor != "cbr" {
+		t.Errorf("entries after reopen = %+v, want seqs 10 and 11 with the delete", got)
+	}
+}
@@ -0,0 +1,337 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Role - уровень доступа клиента.
+type Role string
+
+const (
+	// RoleReader может конвертировать и читать курсы.
+	RoleReader Role = "reader"
+	// RoleAdmin дополнительно может создавать, изменять и удалять валюты.
+	RoleAdmin Role = "admin"
+)
+
+var (
+	ErrUnauthenticated  = errors.New("missing or invalid credentials")
+	ErrPermissionDenied = errors.New("insufficient role for this operation")
+	// ErrNotConfigured - не задан ни один способ аутентификации и она не отключена явно.
+	ErrNotConfigured = errors.New("no authentication configured: set API_KEYS or JWT_* (or AUTH_DISABLED=true to run without access control)")
+)
+
+// Allows сообщает, покрывает ли роль требуемую.
+func (r Role) Allows(required Role) bool {
+	switch r {
+	case RoleAdmin:
+		return true
+	case RoleReader:
+		return required == RoleReader
+	}
+	return false
+}
+
+func ParseRole(s string) (Role, error) {
+	switch Role(s) {
+	case RoleReader, RoleAdmin:
+		return Role(s), nil
+	}
+	return "", fmt.Errorf("unknown role %q", s)
+}
+
+// Principal - аутентифицированный клиент.
+type Principal struct {
+	Subject string
+	Role    Role
+	Method  string // apikey или jwt
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
+
+type Options struct {
+	// APIKeys - список в формате "key:role[:name]" через запятую.
+	APIKeys          string
+	HS256Secret      string
+	HS256SecretFile  string
+	RS256PublicKey   string // путь к PEM-файлу
+	Issuer           string
+	AllowedClockSkew time.Duration
+	// Disabled явно отключает проверку доступа; вместе с ключами или JWT не допускается.
+	Disabled bool
+}
+
+type apiKey struct {
+	key       []byte
+	principal Principal
+}
+
+type Authenticator struct {
+	apiKeys    []apiKey
+	hmacSecret []byte
+	rsaKey     *rsa.PublicKey
+	issuer     string
+	skew       time.Duration
+	now        func() time.Time
+	disabled   bool
+}
+
+func New(opts Options) (*Authenticator, error) {
+	a := &Authenticator{
+		issuer: opts.Issuer,
+		skew:   opts.AllowedClockSkew,
+		now:    time.Now,
+	}
+
+	for _, entry := range strings.Split(opts.APIKeys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) < 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid API key entry, expected key:role[:name]")
+		}
+		role, err := ParseRole(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid API key entry: %w", err)
+		}
+		name := "apikey-" + fmt.Sprint(len(a.apiKeys)+1)
+		if len(parts) == 3 && parts[2] != "" {
+			name = parts[2]
+		}
+		a.apiKeys = append(a.apiKeys, apiKey{
+			key:       []byte(parts[0]),
+			principal: Principal{Subject: name, Role: role, Method: "apikey"},
+		})
+	}
+
+	switch {
+	case opts.HS256Secret != "":
+		a.hmacSecret = []byte(opts.HS256Secret)
+	case opts.HS256SecretFile != "":
+		secret, err := os.ReadFile(opts.HS256SecretFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read HS256 secret: %w", err)
+		}
+		a.hmacSecret = []byte(strings.TrimSpace(string(secret)))
+	}
+
+	if opts.RS256PublicKey != "" {
+		key, err := loadRSAPublicKey(opts.RS256PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		a.rsaKey = key
+	}
+
+	configured := len(a.apiKeys) > 0 || a.hmacSecret != nil || a.rsaKey != nil
+	switch {
+	case opts.Disabled && configured:
+		return nil, fmt.Errorf("authentication is disabled but API keys or JWT keys are configured")
+	case opts.Disabled:
+		a.disabled = true
+	case !configured:
+		return nil, ErrNotConfigured
+	}
+	return a, nil
+}
+
+func loadRSAPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read RS256 public key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("RS256 public key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		if cert, certErr := x509.ParseCertificate(block.Bytes); certErr == nil {
+			parsed = cert.PublicKey
+		} else {
+			return nil, fmt.Errorf("failed to parse RS256 public key: %w", err)
+		}
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("RS256 public key is not an RSA key")
+	}
+	return key, nil
+}
+
+// Enabled сообщает, проверяется ли доступ. Без проверки сервер работает, только
+// если она отключена явно (Options.Disabled); nil тоже считается отключённой.
+func (a *Authenticator) Enabled() bool {
+	return a != nil && !a.disabled
+}
+
+// AuthenticateAPIKey ищет ключ сравнением за постоянное время.
+func (a *Authenticator) AuthenticateAPIKey(key string) (*Principal, error) {
+	var found *Principal
+	for i := range a.apiKeys {
+		if subtle.ConstantTimeCompare(a.apiKeys[i].key, []byte(key)) == 1 {
+			p := a.apiKeys[i].principal
+			found = &p
+		}
+	}
+	if found == nil {
+		return nil, ErrUnauthenticated
+	}
+	return found, nil
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+type jwtClaims struct {
+	Subject   string   `json:"sub"`
+	Role      string   `json:"role"`
+	Issuer    string   `json:"iss"`
+	ExpiresAt *float64 `json:"exp"`
+	NotBefore *float64 `json:"nbf"`
+}
+
+// AuthenticateJWT проверяет подпись (HS256 или RS256), срок действия и роль токена.
+// Токен без exp не принимается: утёкший бессрочный токен нельзя было бы отозвать.
+func (a *Authenticator) AuthenticateJWT(token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrUnauthenticated)
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: malformed token header", ErrUnauthenticated)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed token signature", ErrUnauthenticated)
+	}
+	if err := a.verifySignature(header.Alg, parts[0]+"."+parts[1], sig); err != nil {
+		return nil, err
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed token claims", ErrUnauthenticated)
+	}
+
+	now := a.now()
+	if claims.ExpiresAt == nil {
+		return nil, fmt.Errorf("%w: token has no expiry", ErrUnauthenticated)
+	}
+	if now.After(unixTime(*claims.ExpiresAt).Add(a.skew)) {
+		return nil, fmt.Errorf("%w: token expired", ErrUnauthenticated)
+	}
+	if claims.NotBefore != nil && now.Before(unixTime(*claims.NotBefore).Add(-a.skew)) {
+		return nil, fmt.Errorf("%w: token not valid yet", ErrUnauthenticated)
+	}
+	if a.issuer != "" && claims.Issuer != a.issuer {
+		return nil, fmt.Errorf("%w: unexpected token issuer", ErrUnauthenticated)
+	}
+	role, err := ParseRole(claims.Role)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+
+	return &Principal{Subject: claims.Subject, Role: role, Method: "jwt"}, nil
+}
+
+func (a *Authenticator) verifySignature(alg, signingInput string, sig []byte) error {
+	switch alg {
+	case "HS256":
+		if a.hmacSecret == nil {
+			return fmt.Errorf("%w: HS256 tokens are not accepted", ErrUnauthenticated)
+		}
+		mac := hmac.New(sha256.New, a.hmacSecret)
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(mac.Sum(nil), sig) {
+			return fmt.Errorf("%w: invalid token signature", ErrUnauthenticated)
+		}
+	case "RS256":
+		if a.rsaKey == nil {
+			return fmt.Errorf("%w: RS256 tokens are not accepted", ErrUnauthenticated)
+		}
+		digest := sha256.Sum256([]byte(signingInput))
+		if err := rsa.VerifyPKCS1v15(a.rsaKey, crypto.SHA256, digest[:], sig); err != nil {
+			return fmt.Errorf("%w: invalid token signature", ErrUnauthenticated)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported token algorithm %q", ErrUnauthenticated, alg)
+	}
+	return nil
+}
+
+// Authenticate разбирает учётные данные из заголовков:
+// "Authorization: Bearer <jwt>" или API-ключ.
+func (a *Authenticator) Authenticate(authorization, apiKey string) (*Principal, error) {
+	if apiKey != "" {
+		return a.AuthenticateAPIKey(apiKey)
+	}
+	scheme, credentials, ok := strings.Cut(authorization, " ")
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	switch strings.ToLower(scheme) {
+	case "bearer":
+		return a.AuthenticateJWT(strings.TrimSpace(credentials))
+	case "apikey":
+		return a.AuthenticateAPIKey(strings.TrimSpace(credentials))
+	}
+	return nil, ErrUnauthenticated
+}
+
+// Authorize аутентифицирует запрос и проверяет, что роли хватает для операции.
+func (a *Authenticator) Authorize(authorization, apiKey string, required Role) (*Principal, error) {
+	p, err := a.Authenticate(authorization, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	if !p.Role.Allows(required) {
+		return p, ErrPermissionDenied
+	}
+	return p, nil
+}
+
+func decodeSegment(seg string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func unixTime(sec float64) time.Time {
+	return time.Unix(int64(sec), 0)
+}
@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func signHS256(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestNewRequiresExplicitOptOut(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        Options
+		wantErr     error
+		anyErr      bool
+		wantEnabled bool
+	}{
+		{name: "nothing configured", opts: Options{}, wantErr: ErrNotConfigured},
+		{name: "explicitly disabled", opts: Options{Disabled: true}},
+		{name: "disabled with keys", opts: Options{Disabled: true, APIKeys: "k:admin"}, anyErr: true},
+		{name: "API keys", opts: Options{APIKeys: "k:admin"}, wantEnabled: true},
+		{name: "HS256", opts: Options{HS256Secret: testSecret}, wantEnabled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := New(tt.opts)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("New() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			case tt.anyErr:
+				if err == nil {
+					t.Fatal("New() error = nil, want an error")
+				}
+				return
+			case err != nil:
+				t.Fatalf("New() error = %v", err)
+			}
+			if a.Enabled() != tt.wantEnabled {
+				t.Errorf("Enabled() = %v, want %v", a.Enabled(), tt.wantEnabled)
+			}
+		})
+	}
+}
+
+func TestAuthenticateJWT(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	a, err := New(Options{HS256Secret: testSecret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.now = func() time.Time { return now }
+
+	tests := []struct {
+		name   string
+		claims map[string]any
+		ok     bool
+	}{
+		{name: "valid", claims: map[string]any{"sub": "ci", "role": "admin", "exp": now.Add(time.Hour).Unix()}, ok: true},
+		{name: "no exp", claims: map[string]any{"sub": "ci", "role": "admin"}},
+		{name: "expired", claims: map[string]any{"sub": "ci", "role": "admin", "exp": now.Add(-time.Hour).Unix()}},
+		{name: "not valid yet", claims: map[string]any{"role": "reader", "exp": now.Add(2 * time.Hour).Unix(), "nbf": now.Add(time.Hour).Unix()}},
+		{name: "unknown role", claims: map[string]any{"role": "root", "exp": now.Add(time.Hour).Unix()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := a.AuthenticateJWT(signHS256(t, tt.claims))
+			if tt.ok {
+				if err != nil || p.Subject != "ci" || p.Role != RoleAdmin {
+					t.Fatalf("AuthenticateJWT() = %+v, %v; want admin ci", p, err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrUnauthenticated) {
+				t.Fatalf("AuthenticateJWT() error = %v, want ErrUnauthenticated", err)
+			}
+		})
+	}
+}
+
+func TestRequire(t *testing.T) {
+	enabled, err := New(Options{APIKeys: "reader-key:reader,admin-key:admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	disabled, err := New(Options{Disabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok := func(res http.ResponseWriter, _ *http.Request) { res.WriteHeader(http.StatusNoContent) }
+
+	tests := []struct {
+		name string
+		a    *Authenticator
+		key  string
+		want int
+	}{
+		{name: "no credentials", a: enabled, want: http.StatusUnauthorized},
+		{name: "wrong key", a: enabled, key: "nope", want: http.StatusUnauthorized},
+		{name: "reader on admin route", a: enabled, key: "reader-key", want: http.StatusForbidden},
+		{name: "admin", a: enabled, key: "admin-key", want: http.StatusNoContent},
+		{name: "explicitly disabled", a: disabled, want: http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/currency/USD", nil)
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+			res := httptest.NewRecorder()
+			tt.a.Require(RoleAdmin, ok).ServeHTTP(res, req)
+			if res.Code != tt.want {
+				t.Errorf("status = %d, want %d", res.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptorRejectsMissingCredentials(t *testing.T) {
+	a, err := New(Options{HS256Secret: testSecret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	interceptor := a.UnaryServerInterceptor(MethodRoles{})
+	info := &grpc.UnaryServerInfo{FullMethod: "/CurrencyConverter.CurrencyService/UpdateCurrency"}
+	called := false
+	_, err = interceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		called = true
+		return nil, nil
+	})
+	if status.Code(err) != codes.Unauthenticated || called {
+		t.Fatalf("interceptor error = %v, handler called = %v; want Unauthenticated and no call", err, called)
+	}
+}
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MethodRoles сопоставляет полное имя gRPC-метода с требуемой ролью.
+// Методы, которых нет в списке, доступны только администраторам.
+type MethodRoles map[string]Role
+
+// Public отмечает в MethodRoles методы, доступные без аутентификации (например, health-check).
+const Public Role = "public"
+
+func (m MethodRoles) required(method string) Role {
+	if role, ok := m[method]; ok {
+		return role
+	}
+	return RoleAdmin
+}
+
+func (a *Authenticator) authorizeContext(ctx context.Context, required Role) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	p, err := a.Authorize(firstValue(md, "authorization"), firstValue(md, "x-api-key"), required)
+	if err != nil {
+		if errors.Is(err, ErrPermissionDenied) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return WithPrincipal(ctx, p), nil
+}
+
+func (a *Authenticator) UnaryServerInterceptor(roles MethodRoles) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		required := roles.required(info.FullMethod)
+		if !a.Enabled() || required == Public {
+			return handler(ctx, req)
+		}
+		ctx, err := a.authorizeContext(ctx, required)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (a *Authenticator) StreamServerInterceptor(roles MethodRoles) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		required := roles.required(info.FullMethod)
+		if !a.Enabled() || required == Public {
+			return handler(srv, ss)
+		}
+		ctx, err := a.authorizeContext(ss.Context(), required)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,28 @@
+package auth
+
+import (
+	"currency-converter/internal/httputil"
+	"errors"
+	"net/http"
+)
+
+// Require пропускает запрос к next, только если клиент аутентифицирован и его роли хватает.
+// Если проверка доступа явно отключена, обработчик возвращается без изменений.
+func (a *Authenticator) Require(role Role, next http.HandlerFunc) http.Handler {
+	if !a.Enabled() {
+		return next
+	}
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		p, err := a.Authorize(req.Header.Get("Authorization"), req.Header.Get("X-API-Key"), role)
+		if err != nil {
+			if errors.Is(err, ErrPermissionDenied) {
+				httputil.WriteError(res, http.StatusForbidden, err.Error())
+				return
+			}
+			res.Header().Set("WWW-Authenticate", `Bearer realm="currency-converter"`)
+			httputil.WriteError(res, http.StatusUnauthorized, err.Error())
+			return
+		}
+		next.ServeHTTP(res, req.WithContext(WithPrincipal(req.Context(), p)))
+	})
+}
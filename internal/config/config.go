@@ -0,0 +1,182 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Config - настройки приложения, читаются из переменных окружения.
+type Config struct {
+	RESTAddr        string
+	GRPCAddr        string
+	ShutdownTimeout time.Duration
+	AuditLogPath    string
+	HistoryPath     string
+	RatesStaleAfter time.Duration
+
+	// gRPC: TLS (с клиентским CA - mTLS), reflection, размеры сообщений и keepalive
+	GRPCTLSCertFile       string
+	GRPCTLSKeyFile        string
+	GRPCTLSClientCAFile   string
+	GRPCReflection        bool
+	GRPCMaxRecvMsgSize    int
+	GRPCMaxSendMsgSize    int
+	GRPCKeepaliveTime     time.Duration
+	GRPCKeepaliveTimeout  time.Duration
+	GRPCKeepaliveMinTime  time.Duration
+	GRPCMaxConnectionIdle time.Duration
+
+	// Логирование: формат json или text, уровень debug, info, warn или error
+	LogFormat string
+	LogLevel  string
+
+	// Трассировка OpenTelemetry; без адреса коллектора спаны не экспортируются
+	OTLPEndpoint     string
+	ServiceName      string
+	TraceSampleRatio float64
+
+	// Ограничение частоты запросов на клиента: "rate:burst" по умолчанию
+	// и "route=rate:burst,..." для отдельных маршрутов REST и gRPC-методов;
+	// неудачные попытки аутентификации ограничиваются по адресу клиента
+	RateLimit      string
+	RateLimitRules string
+	RateLimitAuth  string
+
+	// Запись сущностей в хранилище
+	WriteMode    string
+	QueueSize    int
+	StoreTimeout time.Duration
+
+	// Приоритет ручных курсов над курсами ЦБ РФ: manual или provider
+	RatePolicy string
+
+	// Доставка webhook: таймаут запроса, число воркеров, попыток и начальная пауза между ними
+	WebhookTimeout      time.Duration
+	WebhookWorkers      int
+	WebhookMaxAttempts  int
+	WebhookRetryBackoff time.Duration
+
+	// Аутентификация: ключи API и/или JWT; без них сервер не запустится, если не задан AUTH_DISABLED=true
+	APIKeys             string
+	JWTHS256Secret      string
+	JWTHS256SecretFile  string
+	JWTRS256PublicKey   string
+	JWTIssuer           string
+	JWTAllowedClockSkew time.Duration
+	// AuthDisabled разрешает запуск без проверки доступа; без него сервер требует ключи или JWT.
+	AuthDisabled bool
+}
+
+func Load() Config {
+	return Config{
+		RESTAddr:        getString("REST_ADDR", ":8080"),
+		GRPCAddr:        getString("GRPC_ADDR", ":9090"),
+		ShutdownTimeout: getDuration("SHUTDOWN_TIMEOUT", 10*time.Second),
+		AuditLogPath:    getString("AUDIT_LOG_PATH", "data/audit.jsonl"),
+		HistoryPath:     getString("RATE_HISTORY_PATH", "data/rate_history.jsonl"),
+		RatesStaleAfter: getDuration("RATES_STALE_AFTER", 3*time.Hour),
+
+		GRPCTLSCertFile:       getString("GRPC_TLS_CERT_FILE", ""),
+		GRPCTLSKeyFile:        getString("GRPC_TLS_KEY_FILE", ""),
+		GRPCTLSClientCAFile:   getString("GRPC_TLS_CLIENT_CA_FILE", ""),
+		GRPCReflection:        getBool("GRPC_REFLECTION", false),
+		GRPCMaxRecvMsgSize:    getInt("GRPC_MAX_RECV_MSG_SIZE", 4<<20),
+		GRPCMaxSendMsgSize:    getInt("GRPC_MAX_SEND_MSG_SIZE", 4<<20),
+		GRPCKeepaliveTime:     getDuration("GRPC_KEEPALIVE_TIME", 2*time.Minute),
+		GRPCKeepaliveTimeout:  getDuration("GRPC_KEEPALIVE_TIMEOUT", 20*time.Second),
+		GRPCKeepaliveMinTime:  getDuration("GRPC_KEEPALIVE_MIN_TIME", 30*time.Second),
+		GRPCMaxConnectionIdle: getDuration("GRPC_MAX_CONNECTION_IDLE", 0),
+
+		LogFormat: getString("LOG_FORMAT", "json"),
+		LogLevel:  getString("LOG_LEVEL", "info"),
+
+		OTLPEndpoint:     getString("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+		ServiceName:      getString("OTEL_SERVICE_NAME", "currency-converter"),
+		TraceSampleRatio: getFloat("TRACE_SAMPLE_RATIO", 1.0),
+
+		RateLimit: getString("RATE_LIMIT", ""),
+		RateLimitRules: getString("RATE_LIMIT_RULES",
+			"POST /conversion=10:20,/CurrencyConverter.ConversionService/CreateConversion=10:20"),
+		RateLimitAuth: getString("RATE_LIMIT_AUTH", "1:10"),
+
+		WriteMode:    getString("WRITE_MODE", "sync"),
+		QueueSize:    getInt("WRITE_QUEUE_SIZE", 56),
+		StoreTimeout: getDuration("STORE_TIMEOUT", 5*time.Second),
+
+		RatePolicy: getString("RATE_POLICY", "manual"),
+
+		WebhookTimeout:      getDuration("WEBHOOK_TIMEOUT", 5*time.Second),
+		WebhookWorkers:      getInt("WEBHOOK_WORKERS", 4),
+		WebhookMaxAttempts:  getInt("WEBHOOK_MAX_ATTEMPTS", 6),
+		WebhookRetryBackoff: getDuration("WEBHOOK_RETRY_BACKOFF", 5*time.Second),
+
+		APIKeys:             getString("API_KEYS", ""),
+		JWTHS256Secret:      getString("JWT_HS256_SECRET", ""),
+		JWTHS256SecretFile:  getString("JWT_HS256_SECRET_FILE", ""),
+		JWTRS256PublicKey:   getString("JWT_RS256_PUBLIC_KEY_FILE", ""),
+		JWTIssuer:           getString("JWT_ISSUER", ""),
+		JWTAllowedClockSkew: getDuration("JWT_CLOCK_SKEW", 30*time.Second),
+		AuthDisabled:        getBool("AUTH_DISABLED", false),
+	}
+}
+
+func getString(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return def
+}
+
+func getInt(key string, def int) int {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		slog.Warn("invalid config value, using default", "key", key, "value", v, "default", def)
+		return def
+	}
+	return n
+}
+
+func getBool(key string, def bool) bool {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		slog.Warn("invalid config value, using default", "key", key, "value", v, "default", def)
+		return def
+	}
+	return b
+}
+
+func getFloat(key string, def float64) float64 {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		slog.Warn("invalid config value, using default", "key", key, "value", v, "default", def)
+		return def
+	}
+	return f
+}
+
+func getDuration(key string, def time.Duration) time.Duration {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		slog.Warn("invalid config value, using default", "key", key, "value", v, "default", def.String())
+		return def
+	}
+	return d
+}
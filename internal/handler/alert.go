@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"currency-converter/internal/httputil"
+	"currency-converter/internal/model"
+	"currency-converter/internal/service"
+	"net/http"
+)
+
+type AlertHandler struct {
+	svc service.Service
+}
+
+func NewAlertHandler(svc service.Service) *AlertHandler {
+	return &AlertHandler{svc: svc}
+}
+
+// CreateAlert godoc
+// @Summary Create rate alert
+// @Description Adds a rule that is evaluated after every Central Bank of Russia sync and POSTs an event to webhook_url when it fires. Conditions: above, below (rate of base in quote crosses threshold since the previous sync) and change_pct (day-over-day change in percent exceeds threshold). A rule fires once per crossing; last_rate is the rate it was last checked against
+// @Tags alert
+// @Accept json
+// @Produce json
+// @Param alert body model.AlertRequest true "Alert rule" Example({"base": "USD", "quote": "RUB", "condition": "above", "threshold": 95, "cooldown_seconds": 86400, "webhook_url": "https://example.com/hook"})
+// @Success 201 {object} model.AlertRule
+// @Failure 400 {object} httputil.ErrorResponse "Invalid alert rule, listed by field"
+// @Failure 413 {object} map[string]string "Request body is too large"
+// @Failure 415 {object} map[string]string "Content-Type is not application/json"
+// @Failure 500 {object} map[string]string "Failed to persist alert rule"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /alerts [post]
+func (h *AlertHandler) CreateAlert(res http.ResponseWriter, req *http.Request) {
+	var alertReq model.AlertRequest
+	if err := httputil.ReadJson(res, req, &alertReq); err != nil {
+		httputil.WriteRequestError(res, err)
+		return
+	}
+
+	rule, err := h.svc.CreateAlert(req.Context(), &alertReq)
+	if err != nil {
+		writeServiceError(res, "Failed to create alert", err)
+		return
+	}
+	httputil.WriteJson(res, http.StatusCreated, rule)
+}
+
+// ListAlerts godoc
+// @Summary List rate alerts
+// @Description Retrieves all alert rules in creation order
+// @Tags alert
+// @Produce json
+// @Success 200 {array} model.AlertRule
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /alerts [get]
+func (h *AlertHandler) ListAlerts(res http.ResponseWriter, req *http.Request) {
+	rules, err := h.svc.ListAlerts(req.Context())
+	if err != nil {
+		writeServiceError(res, "Failed to retrieve alerts", err)
+		return
+	}
+	httputil.WriteJson(res, http.StatusOK, rules)
+}
+
+// GetAlert godoc
+// @Summary Get rate alert
+// @Tags alert
+// @Produce json
+// @Param id path string true "Alert rule ID"
+// @Success 200 {object} model.AlertRule
+// @Failure 404 {object} map[string]string "Alert rule not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /alerts/{id} [get]
+func (h *AlertHandler) GetAlert(res http.ResponseWriter, req *http.Request) {
+	rule, err := h.svc.GetAlert(req.Context(), req.PathValue("id"))
+	if err != nil {
+		writeServiceError(res, "Failed to get alert", err)
+		return
+	}
+	httputil.WriteJson(res, http.StatusOK, rule)
+}
+
+// UpdateAlert godoc
+// @Summary Update rate alert
+// @Description Replaces the rule parameters; the time it last fired is kept, so the cooldown still applies
+// @Tags alert
+// @Accept json
+// @Produce json
+// @Param id path string true "Alert rule ID"
+// @Param alert body model.AlertRequest true "Alert rule"
+// @Success 200 {object} model.AlertRule
+// @Failure 400 {object} httputil.ErrorResponse "Invalid alert rule, listed by field"
+// @Failure 404 {object} map[string]string "Alert rule not found"
+// @Failure 413 {object} map[string]string "Request body is too large"
+// @Failure 415 {object} map[string]string "Content-Type is not application/json"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /alerts/{id} [put]
+func (h *AlertHandler) UpdateAlert(res http.ResponseWriter, req *http.Request) {
+	var alertReq model.AlertRequest
+	if err := httputil.ReadJson(res, req, &alertReq); err != nil {
+		httputil.WriteRequestError(res, err)
+		return
+	}
+
+	rule, err := h.svc.UpdateAlert(req.Context(), req.PathValue("id"), &alertReq)
+	if err != nil {
+		writeServiceError(res, "Failed to update alert", err)
+		return
+	}
+	httputil.WriteJson(res, http.StatusOK, rule)
+}
+
+// DeleteAlert godoc
+// @Summary Delete rate alert
+// @Tags alert
+// @Param id path string true "Alert rule ID"
+// @Success 204 "Alert rule deleted"
+// @Failure 404 {object} map[string]string "Alert rule not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /alerts/{id} [delete]
+func (h *AlertHandler) DeleteAlert(res http.ResponseWriter, req *http.Request) {
+	if err := h.svc.DeleteAlert(req.Context(), req.PathValue("id")); err != nil {
+		writeServiceError(res, "Failed to delete alert", err)
+		return
+	}
+	res.WriteHeader(http.StatusNoContent)
+}
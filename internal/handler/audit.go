@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"currency-converter/internal/audit"
+	"currency-converter/internal/httputil"
+	"encoding/csv"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type AuditHandler struct {
+	log *audit.Log
+}
+
+func NewAuditHandler(log *audit.Log) *AuditHandler {
+	return &AuditHandler{log: log}
+}
+
+// ListAudit godoc
+// @Summary Get rate change audit trail
+// @Description Returns every recorded change of an exchange rate (who, source, old and new rate, request ID). Use format=csv or format=jsonl to download an export
+// @Tags admin
+// @Produce json
+// @Produce text/csv
+// @Param code query string false "Currency code" Example(USD)
+// @Param actor query string false "Actor (API key name or JWT subject)"
+// @Param source query string false "Change source" Enums(api, cbr_sync, system, import)
+// @Param request_id query string false "Request ID"
+// @Param from query string false "Start of the period, RFC 3339 (inclusive)"
+// @Param to query string false "End of the period, RFC 3339 (exclusive)"
+// @Param limit query int false "Return only the last N matching entries"
+// @Param format query string false "Response format" Enums(json, csv, jsonl)
+// @Success 200 {array} audit.Entry "Matching audit entries in chronological order"
+// @Failure 400 {object} map[string]string "Invalid filter"
+// @Failure 500 {object} map[string]string "Audit log could not be read"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /admin/audit [get]
+func (h *AuditHandler) ListAudit(res http.ResponseWriter, req *http.Request) {
+	q := req.URL.Query()
+	filter := audit.Filter{
+		Code:      q.Get("code"),
+		Actor:     q.Get("actor"),
+		Source:    audit.Source(q.Get("source")),
+		RequestID: q.Get("request_id"),
+	}
+
+	var err error
+	if v := q.Get("from"); v != "" {
+		if filter.From, err = time.Parse(time.RFC3339, v); err != nil {
+			httputil.WriteError(res, http.StatusBadRequest, "Invalid 'from' timestamp, expected RFC 3339")
+			return
+		}
+	}
+	if v := q.Get("to"); v != "" {
+		if filter.To, err = time.Parse(time.RFC3339, v); err != nil {
+			httputil.WriteError(res, http.StatusBadRequest, "Invalid 'to' timestamp, expected RFC 3339")
+			return
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit < 0 {
+			httputil.WriteError(res, http.StatusBadRequest, "Limit must be a non-negative integer")
+			return
+		}
+	}
+
+	entries, err := h.log.Query(filter)
+	if err != nil {
+		slog.ErrorContext(req.Context(), "failed to read audit log", "error", err)
+		httputil.WriteError(res, http.StatusInternalServerError, "Failed to read audit log")
+		return
+	}
+
+	switch q.Get("format") {
+	case "", "json":
+		httputil.WriteJson(res, http.StatusOK, entries)
+	case "jsonl":
+		res.Header().Set("Content-Type", "application/x-ndjson")
+		res.Header().Set("Content-Disposition", `attachment; filename="audit.jsonl"`)
+		encoder := json.NewEncoder(res)
+		for _, e := range entries {
+			encoder.Encode(e)
+		}
+	case "csv":
+		res.Header().Set("Content-Type", "text/csv")
+		res.Header().Set("Content-Disposition", `attachment; filename="audit.csv"`)
+		writeAuditCSV(res, entries)
+	default:
+		httputil.WriteError(res, http.StatusBadRequest, "Unsupported format, expected json, csv or jsonl")
+	}
+}
+
+func writeAuditCSV(res http.ResponseWriter, entries []audit.Entry) {
+	w := csv.NewWriter(res)
+	w.Write([]string{"seq", "timestamp", "actor", "source", "action", "code", "old_rate", "new_rate", "reason", "request_id"})
+	for _, e := range entries {
+		w.Write([]string{
+			strconv.FormatUint(e.Seq, 10),
+			e.Timestamp.Format(time.RFC3339Nano),
+			e.Actor,
+			string(e.Source),
+			string(e.Action),
+			e.Code,
+			formatRate(e.OldRate),
+			formatRate(e.NewRate),
+			e.Reason,
+			e.RequestID,
+		})
+	}
+	w.Flush()
+}
+
+func formatRate(rate *float64) string {
+	if rate == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*rate, 'f', -1, 64)
+}
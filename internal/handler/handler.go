@@ -4,11 +4,25 @@ import (
 	"currency-converter/internal/httputil"
 	"currency-converter/internal/model"
 	"currency-converter/internal/service"
-	"strings"
+	"currency-converter/internal/transport"
+	"currency-converter/internal/validation"
+	"errors"
 
 	"net/http"
+	"strconv"
 )
 
+// writeServiceError отвечает статусом из transport, как и gRPC-сервер на ту же ошибку.
+// Ошибки проверки запроса перечисляются по полям.
+func writeServiceError(res http.ResponseWriter, msg string, err error) {
+	var verr *validation.Error
+	if errors.As(err, &verr) {
+		httputil.WriteFieldErrors(res, transport.HTTPStatus(err), msg+": "+err.Error(), verr.Fields)
+		return
+	}
+	httputil.WriteError(res, transport.HTTPStatus(err), msg+": "+err.Error())
+}
+
 type CurrencyHandler struct {
 	svc service.Service
 }
@@ -19,45 +33,123 @@ func NewCurrencyHandler(svc service.Service) *CurrencyHandler {
 
 // CreateCurrency godoc
 // @Summary Create currency
-// @Description Adds a new currency to the storage
+// @Description Adds a new currency to the storage. Existing currencies are never overwritten, use /currency/upsert for that
 // @Tags currency
 // @Accept json
 // @Produce json
 // @Param currency body model.Currency true "Currency data"
 // @Success 201 {object} model.Currency
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} httputil.ErrorResponse "Invalid currency data or unknown ISO 4217 code, listed by field"
+// @Failure 409 {object} map[string]string "Currency already exists"
+// @Failure 413 {object} map[string]string "Request body is too large"
+// @Failure 415 {object} map[string]string "Content-Type is not application/json"
+// @Failure 500 {object} map[string]string "Failed to persist currency"
+// @Failure 503 {object} map[string]string "Write queue is full or service is shutting down"
+// @Failure 504 {object} map[string]string "Timed out waiting for persistence"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /currency [post]
 func (h *CurrencyHandler) CreateCurrency(res http.ResponseWriter, req *http.Request) {
 	var cur model.Currency
-	if err := httputil.ReadJson(*req, &cur); err != nil {
-		httputil.WriteError(res, http.StatusBadRequest, "Invalid JSON format")
+	if err := httputil.ReadJson(res, req, &cur); err != nil {
+		httputil.WriteRequestError(res, err)
+		return
+	}
+
+	respCur, err := h.svc.CreateCurrency(req.Context(), &cur)
+	if err != nil {
+		writeServiceError(res, "Failed to create currency", err)
+		return
+	}
+
+	httputil.WriteJson(res, http.StatusCreated, respCur)
+}
+
+// UpsertCurrency godoc
+// @Summary Create or replace currency
+// @Description Creates a currency or overwrites an existing one with the same code
+// @Tags currency
+// @Accept json
+// @Produce json
+// @Param currency body model.Currency true "Currency data"
+// @Success 200 {object} model.Currency
+// @Failure 400 {object} httputil.ErrorResponse "Invalid currency data or unknown ISO 4217 code, listed by field"
+// @Failure 413 {object} map[string]string "Request body is too large"
+// @Failure 415 {object} map[string]string "Content-Type is not application/json"
+// @Failure 500 {object} map[string]string "Failed to persist currency"
+// @Failure 503 {object} map[string]string "Write queue is full or service is shutting down"
+// @Failure 504 {object} map[string]string "Timed out waiting for persistence"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /currency/upsert [post]
+func (h *CurrencyHandler) UpsertCurrency(res http.ResponseWriter, req *http.Request) {
+	var cur model.Currency
+	if err := httputil.ReadJson(res, req, &cur); err != nil {
+		httputil.WriteRequestError(res, err)
 		return
 	}
 
-	if respCur, err := h.svc.CreateCurrency(&cur); err == nil {
-		httputil.WriteJson(res, http.StatusCreated, &respCur)
+	respCur, err := h.svc.UpsertCurrency(req.Context(), &cur)
+	if err != nil {
+		writeServiceError(res, "Failed to upsert currency", err)
 		return
 	}
 
-	httputil.WriteError(res, http.StatusBadRequest, "Invalid currency data provided")
+	httputil.WriteJson(res, http.StatusOK, respCur)
 }
 
 // ListCurrencies godoc
 // @Summary Get list of all available currencies
-// @Description Retrieves all currencies with current exchange rates from Central Bank of Russia
+// @Description Retrieves all currencies with current exchange rates from Central Bank of Russia. The response format is chosen by the Accept header
 // @Tags currency
 // @Produce json
-// @Success 200 {object} map[string]model.Currency "Successfully retrieved currencies map"
+// @Produce xml
+// @Produce text/csv
+// @Produce application/yaml
+// @Produce application/x-protobuf
+// @Success 200 {object} map[string]model.Currency "Successfully retrieved currencies map (a list sorted by code in XML, CSV and protobuf)"
+// @Failure 406 {object} map[string]string "No acceptable response format"
 // @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /currencies [get]
 func (h *CurrencyHandler) ListCurrencies(res http.ResponseWriter, req *http.Request) {
-	data, err := h.svc.ListCurrencies()
+	data, err := h.svc.ListCurrencies(req.Context())
 	if err != nil {
-		httputil.WriteError(res, http.StatusInternalServerError, "Failed to retrieve currencies list")
+		writeServiceError(res, "Failed to retrieve currency list", err)
 		return
 	}
-	httputil.WriteJson(res, http.StatusOK, data)
+	httputil.Write(res, req, http.StatusOK, currencyList(data))
+}
+
+// ListMovers godoc
+// @Summary Get biggest daily gainers and losers
+// @Description Lists currencies whose rate to the ruble rose or fell the most since the previous Central Bank of Russia rate, ordered by change in percent. Currencies without a known previous rate are skipped
+// @Tags currency
+// @Produce json
+// @Param limit query int false "Number of currencies in each list (default 5)"
+// @Success 200 {object} model.Movers
+// @Failure 400 {object} map[string]string "Invalid limit"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /rates/movers [get]
+func (h *CurrencyHandler) ListMovers(res http.ResponseWriter, req *http.Request) {
+	var limit int
+	if v := req.URL.Query().Get("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil || limit <= 0 {
+			httputil.WriteError(res, http.StatusBadRequest, "Limit must be a positive integer")
+			return
+		}
+	}
+
+	movers, err := h.svc.ListMovers(req.Context(), limit)
+	if err != nil {
+		writeServiceError(res, "Failed to retrieve rate movers", err)
+		return
+	}
+	httputil.WriteJson(res, http.StatusOK, movers)
 }
 
 // GetCurrency godoc
@@ -70,17 +162,13 @@ func (h *CurrencyHandler) ListCurrencies(res http.ResponseWriter, req *http.Requ
 // @Failure 400 {object} map[string]string "Invalid currency code format"
 // @Failure 404 {object} map[string]string "Currency not found"
 // @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /currency/{code} [get]
 func (h *CurrencyHandler) GetCurrency(res http.ResponseWriter, req *http.Request) {
-	code := req.PathValue("code")
-	if code == "" {
-		httputil.WriteError(res, http.StatusBadRequest, "Currency code is required")
-		return
-	}
-
-	cur, err := h.svc.GetCurrency(code)
+	cur, err := h.svc.GetCurrency(req.Context(), req.PathValue("code"))
 	if err != nil {
-		httputil.WriteError(res, http.StatusNotFound, "Currency not found: "+code)
+		writeServiceError(res, "Failed to get currency", err)
 		return
 	}
 	httputil.WriteJson(res, http.StatusOK, cur)
@@ -88,40 +176,138 @@ func (h *CurrencyHandler) GetCurrency(res http.ResponseWriter, req *http.Request
 
 // UpdateCurrency godoc
 // @Summary Update currency exchange rate
-// @Description Updates the exchange rate for a specific currency. Note: Normally rates are updated automatically from Central Bank of Russia
+// @Description Updates a currency. A changed exchange rate is pinned as a manual override without expiry (like PUT /currency/{code}/override), so the Central Bank of Russia sync keeps it while the precedence policy is manual; DELETE /currency/{code}/override returns to the provider rate
 // @Tags currency
 // @Accept json
 // @Produce json
 // @Param code path string true "Currency code to update (ISO 4217 format)" Example(USD)
 // @Param currency body model.Currency true "Currency data with updated exchange rate"
 // @Success 200 {object} model.Currency "Successfully updated currency"
-// @Failure 400 {object} map[string]string "Invalid input data or currency code mismatch"
+// @Failure 400 {object} httputil.ErrorResponse "Invalid input data or currency code mismatch, listed by field"
 // @Failure 404 {object} map[string]string "Currency not found"
+// @Failure 413 {object} map[string]string "Request body is too large"
+// @Failure 415 {object} map[string]string "Content-Type is not application/json"
 // @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /currency/{code} [put]
 func (h *CurrencyHandler) UpdateCurrency(res http.ResponseWriter, req *http.Request) {
+	var cur model.Currency
+	if err := httputil.ReadJson(res, req, &cur); err != nil {
+		httputil.WriteRequestError(res, err)
+		return
+	}
+
 	code := req.PathValue("code")
-	if code == "" {
-		httputil.WriteError(res, http.StatusBadRequest, "Currency code is required")
+	if cur.Code != "" && cur.Code != code {
+		var errs validation.Errors
+		errs.Add("code", "must match the currency code in the path (%s)", code)
+		writeServiceError(res, "Failed to update currency", errs.Err(service.ErrInvalidCurrency))
 		return
 	}
+	cur.Code = code
+	update, err := h.svc.UpdateCurrency(req.Context(), &cur)
+	if err != nil {
+		writeServiceError(res, "Failed to update currency", err)
+		return
+	}
+	httputil.WriteJson(res, http.StatusOK, update)
+}
 
-	var cur model.Currency
-	if err := httputil.ReadJson(*req, &cur); err != nil {
-		httputil.WriteError(res, http.StatusBadRequest, "Invalid JSON format")
+// DeleteCurrency godoc
+// @Summary Delete currency
+// @Description Removes a currency and its manual rate override. Note: currencies provided by Central Bank of Russia reappear after the next sync
+// @Tags currency
+// @Param code path string true "Currency code (ISO 4217 format)" Example(USD)
+// @Success 204 "Currency deleted"
+// @Failure 404 {object} map[string]string "Currency not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /currency/{code} [delete]
+func (h *CurrencyHandler) DeleteCurrency(res http.ResponseWriter, req *http.Request) {
+	if err := h.svc.DeleteCurrency(req.Context(), req.PathValue("code")); err != nil {
+		writeServiceError(res, "Failed to delete currency", err)
+		return
+	}
+	res.WriteHeader(http.StatusNoContent)
+}
+
+// SetOverride godoc
+// @Summary Pin a manual exchange rate
+// @Description Sets a manual rate that survives Central Bank of Russia sync until it expires or is cleared (depending on the configured precedence policy)
+// @Tags override
+// @Accept json
+// @Produce json
+// @Param code path string true "Currency code (ISO 4217 format)" Example(USD)
+// @Param override body model.OverrideRequest true "Manual rate, reason and optional expiry"
+// @Success 200 {object} model.RateOverride "Override applied"
+// @Failure 400 {object} httputil.ErrorResponse "Invalid override data, listed by field"
+// @Failure 404 {object} map[string]string "Currency not found"
+// @Failure 413 {object} map[string]string "Request body is too large"
+// @Failure 415 {object} map[string]string "Content-Type is not application/json"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /currency/{code}/override [put]
+func (h *CurrencyHandler) SetOverride(res http.ResponseWriter, req *http.Request) {
+	code := req.PathValue("code")
+
+	var overReq model.OverrideRequest
+	if err := httputil.ReadJson(res, req, &overReq); err != nil {
+		httputil.WriteRequestError(res, err)
 		return
-	} else if cur.Rate <= 0 {
-		httputil.WriteError(res, http.StatusBadRequest, "Exchange rate must be greater than zero")
+	}
+
+	override, err := h.svc.SetOverride(req.Context(), &model.RateOverride{
+		Code:      code,
+		Rate:      overReq.Rate,
+		Reason:    overReq.Reason,
+		ExpiresAt: overReq.ExpiresAt,
+	})
+	if err != nil {
+		writeServiceError(res, "Failed to set override", err)
 		return
 	}
+	httputil.WriteJson(res, http.StatusOK, override)
+}
 
-	cur.Code = code
-	if update, err := h.svc.UpdateCurrency(&cur); err == nil {
-		httputil.WriteJson(res, http.StatusOK, update)
+// ClearOverride godoc
+// @Summary Clear a manual exchange rate
+// @Description Removes the manual rate and restores the last known Central Bank of Russia rate
+// @Tags override
+// @Param code path string true "Currency code (ISO 4217 format)" Example(USD)
+// @Success 204 "Override cleared"
+// @Failure 404 {object} map[string]string "Override not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /currency/{code}/override [delete]
+func (h *CurrencyHandler) ClearOverride(res http.ResponseWriter, req *http.Request) {
+	if err := h.svc.ClearOverride(req.Context(), req.PathValue("code")); err != nil {
+		writeServiceError(res, "Failed to clear override", err)
 		return
 	}
+	res.WriteHeader(http.StatusNoContent)
+}
 
-	httputil.WriteError(res, http.StatusNotFound, "Currency not found: "+code)
+// ListOverrides godoc
+// @Summary List manual exchange rates
+// @Description Retrieves all active manual rate overrides
+// @Tags override
+// @Produce json
+// @Success 200 {array} model.RateOverride "Successfully retrieved overrides"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /overrides [get]
+func (h *CurrencyHandler) ListOverrides(res http.ResponseWriter, req *http.Request) {
+	data, err := h.svc.ListOverrides(req.Context())
+	if err != nil {
+		writeServiceError(res, "Failed to retrieve overrides", err)
+		return
+	}
+	httputil.WriteJson(res, http.StatusOK, data)
 }
 
 type ConversionHandler struct {
@@ -140,28 +326,27 @@ func NewConversionHandler(svc service.Service) *ConversionHandler {
 // @Produce json
 // @Param request body model.ConversionRequest true "Conversion request parameters" Example({"amount": 100, "from": "USD", "to": "EUR"})
 // @Success 201 {object} model.Conversion "Successfully converted currency"
-// @Failure 400 {object} map[string]string "Invalid request parameters"
+// @Failure 400 {object} httputil.ErrorResponse "Invalid request parameters or unknown fields"
 // @Failure 404 {object} map[string]string "Currency not found"
-// @Failure 422 {object} map[string]string "Invalid conversion parameters"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 413 {object} map[string]string "Request body is too large"
+// @Failure 415 {object} map[string]string "Content-Type is not application/json"
+// @Failure 422 {object} httputil.ErrorResponse "Invalid conversion parameters, listed by field"
+// @Failure 429 {object} map[string]string "Rate limit exceeded, see Retry-After header"
+// @Failure 500 {object} map[string]string "Failed to persist conversion"
+// @Failure 503 {object} map[string]string "Write queue is full or service is shutting down"
+// @Failure 504 {object} map[string]string "Timed out waiting for persistence"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /conversion [post]
 func (h *ConversionHandler) CreateConversion(res http.ResponseWriter, req *http.Request) {
 	var convReq model.ConversionRequest
-	if err := httputil.ReadJson(*req, &convReq); err != nil {
-		httputil.WriteError(res, http.StatusBadRequest, "Invalid JSON format")
+	if err := httputil.ReadJson(res, req, &convReq); err != nil {
+		httputil.WriteRequestError(res, err)
 		return
 	}
-	conv, err := h.svc.CreateConversion(convReq.Amount, convReq.From, convReq.To)
+	conv, err := h.svc.CreateConversion(req.Context(), convReq.Amount, convReq.From, convReq.To)
 	if err != nil {
-		switch {
-		case strings.Contains(err.Error(), "not found"):
-			httputil.WriteError(res, http.StatusNotFound, err.Error())
-		case strings.Contains(err.Error(), "must be greater than zero"),
-			strings.Contains(err.Error(), "cannot convert"):
-			httputil.WriteError(res, http.StatusUnprocessableEntity, err.Error())
-		default:
-			httputil.WriteError(res, http.StatusBadRequest, err.Error())
-		}
+		writeServiceError(res, "Conversion failed", err)
 		return
 	}
 
@@ -170,17 +355,24 @@ func (h *ConversionHandler) CreateConversion(res http.ResponseWriter, req *http.
 
 // ListConversions godoc
 // @Summary Get conversion history
-// @Description Retrieves history of all currency conversions performed
+// @Description Retrieves history of all currency conversions performed. The response format is chosen by the Accept header
 // @Tags conversion
 // @Produce json
+// @Produce xml
+// @Produce text/csv
+// @Produce application/yaml
+// @Produce application/x-protobuf
 // @Success 200 {array} model.Conversion "Successfully retrieved conversion history"
+// @Failure 406 {object} map[string]string "No acceptable response format"
 // @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /conversions [get]
 func (h *ConversionHandler) ListConversions(res http.ResponseWriter, req *http.Request) {
-	data, err := h.svc.ListConversions()
+	data, err := h.svc.ListConversions(req.Context())
 	if err != nil {
-		httputil.WriteError(res, http.StatusInternalServerError, "Failed to retrieve conversion history")
+		writeServiceError(res, "Failed to retrieve conversion history", err)
 		return
 	}
-	httputil.WriteJson(res, http.StatusOK, data)
+	httputil.Write(res, req, http.StatusOK, conversionList(data))
 }
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"currency-converter/internal/history"
+	"currency-converter/internal/httputil"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxMovingAverageWindow  = 365
+	maxMovingAverageWindows = 5
+)
+
+var (
+	defaultSMAWindows = []int{7, 30}
+	defaultEMAWindows = []int{12, 26}
+)
+
+type HistoryHandler struct {
+	store *history.Store
+}
+
+func NewHistoryHandler(store *history.Store) *HistoryHandler {
+	return &HistoryHandler{store: store}
+}
+
+// RateStats godoc
+// @Summary Get rate statistics for a period
+// @Description Computes statistics over daily closing rates to the ruble: min, max, mean, median, standard deviation, volatility of daily log returns (in percent, daily and annualized over 252 trading days) and simple/exponential moving averages at the end of the period. Moving averages also use rates before the period; windows longer than the whole history are omitted
+// @Tags currency
+// @Produce json
+// @Param code path string true "Currency code (ISO 4217 format)" Example(USD)
+// @Param from query string false "Start of the period, date (2006-01-02) or RFC 3339 (inclusive)"
+// @Param to query string false "End of the period, date (2006-01-02) or RFC 3339 (exclusive)"
+// @Param sma query string false "Comma-separated SMA windows in days (default 7,30)" Example(7,30)
+// @Param ema query string false "Comma-separated EMA windows in days (default 12,26)" Example(12,26)
+// @Success 200 {object} history.Stats
+// @Failure 400 {object} map[string]string "Invalid period or window"
+// @Failure 404 {object} map[string]string "No rate history for the period"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /currency/{code}/stats [get]
+func (h *HistoryHandler) RateStats(res http.ResponseWriter, req *http.Request) {
+	q := req.URL.Query()
+	from, to, err := parsePeriod(q)
+	if err != nil {
+		httputil.WriteError(res, http.StatusBadRequest, err.Error())
+		return
+	}
+	sma, err := parseWindows(q.Get("sma"), defaultSMAWindows)
+	if err != nil {
+		httputil.WriteError(res, http.StatusBadRequest, "Invalid 'sma': "+err.Error())
+		return
+	}
+	ema, err := parseWindows(q.Get("ema"), defaultEMAWindows)
+	if err != nil {
+		httputil.WriteError(res, http.StatusBadRequest, "Invalid 'ema': "+err.Error())
+		return
+	}
+
+	stats, err := h.store.Stats(req.PathValue("code"), from, to, sma, ema)
+	if err != nil {
+		writeServiceError(res, "Failed to compute rate statistics", err)
+		return
+	}
+	httputil.WriteJson(res, http.StatusOK, stats)
+}
+
+// Candles godoc
+// @Summary Get OHLC candles
+// @Description Aggregates daily rates into open/high/low/close candles of any length: Nd (days), Nw (weeks starting on Monday), NM (calendar months) or Ny (years), e.g. 1w or 3M. Candles are aligned to the calendar in UTC. For a quote other than RUB the cross rate is computed for each day on which both rates are known; since intraday rates of the two currencies are observed at different times, cross candles are built from daily closing cross rates only
+// @Tags currency
+// @Produce json
+// @Param code path string true "Base currency code (ISO 4217 format)" Example(USD)
+// @Param quote query string false "Quote currency (default RUB)" Example(EUR)
+// @Param interval query string false "Candle length (default 1d)" Example(1w)
+// @Param from query string false "Start of the period, date (2006-01-02) or RFC 3339 (inclusive)"
+// @Param to query string false "End of the period, date (2006-01-02) or RFC 3339 (exclusive)"
+// @Success 200 {object} history.Candles
+// @Failure 400 {object} map[string]string "Invalid period or interval"
+// @Failure 404 {object} map[string]string "No rate history for the period"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /currency/{code}/candles [get]
+func (h *HistoryHandler) Candles(res http.ResponseWriter, req *http.Request) {
+	q := req.URL.Query()
+	from, to, err := parsePeriod(q)
+	if err != nil {
+		httputil.WriteError(res, http.StatusBadRequest, err.Error())
+		return
+	}
+	quote := q.Get("quote")
+	if quote == "" {
+		quote = "RUB"
+	}
+	interval := q.Get("interval")
+	if interval == "" {
+		interval = "1d"
+	}
+	iv, err := history.ParseInterval(interval)
+	if err != nil {
+		writeServiceError(res, "Invalid 'interval'", err)
+		return
+	}
+
+	candles, err := h.store.Candles(req.PathValue("code"), quote, iv, from, to)
+	if err != nil {
+		writeServiceError(res, "Failed to build candles", err)
+		return
+	}
+	httputil.WriteJson(res, http.StatusOK, candles)
+}
+
+// parsePeriod разбирает from и to: дату или момент RFC 3339.
+func parsePeriod(q url.Values) (from, to time.Time, err error) {
+	parse := func(name string) (time.Time, error) {
+		v := q.Get(name)
+		if v == "" {
+			return time.Time{}, nil
+		}
+		if t, err := time.Parse(time.DateOnly, v); err == nil {
+			return t, nil
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("Invalid '%s', expected date (2006-01-02) or RFC 3339", name)
+		}
+		return t, nil
+	}
+
+	if from, err = parse("from"); err != nil {
+		return
+	}
+	if to, err = parse("to"); err != nil {
+		return
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		err = errors.New("'from' must be before 'to'")
+	}
+	return
+}
+
+func parseWindows(v string, def []int) ([]int, error) {
+	if v == "" {
+		return def, nil
+	}
+	parts := strings.Split(v, ",")
+	if len(parts) > maxMovingAverageWindows {
+		return nil, fmt.Errorf("at most %d windows allowed", maxMovingAverageWindows)
+	}
+	windows := make([]int, 0, len(parts))
+	for _, p := range parts {
+		w, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil || w < 1 || w > maxMovingAverageWindow {
+			return nil, fmt.Errorf("window must be an integer from 1 to %d", maxMovingAverageWindow)
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"bytes"
+	"currency-converter/internal/httputil"
+	"currency-converter/internal/model"
+	"currency-converter/internal/xlsx"
+	"encoding/csv"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ConversionReport godoc
+// @Summary Get conversion report
+// @Description Aggregates conversion history for a period: total count and volume in rubles (at the rate of each conversion), volume, converted amount and average amount per currency pair, top pairs by number of conversions and number of conversions per day. Conversions recorded before timestamps were stored are only counted when no period is given. Use format=csv to download one table (pairs, top or daily) or format=xlsx for a workbook with all of them
+// @Tags conversion
+// @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param from query string false "Start of the period, date (2006-01-02) or RFC 3339 (inclusive)"
+// @Param to query string false "End of the period, date (2006-01-02) or RFC 3339 (exclusive)"
+// @Param top query int false "Number of top pairs (default 5)"
+// @Param format query string false "Response format" Enums(json, csv, xlsx)
+// @Param table query string false "Table to export as CSV (default pairs)" Enums(pairs, top, daily)
+// @Success 200 {object} model.ConversionReport
+// @Failure 400 {object} map[string]string "Invalid period or parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /conversions/report [get]
+func (h *ConversionHandler) ConversionReport(res http.ResponseWriter, req *http.Request) {
+	q := req.URL.Query()
+	from, to, err := parsePeriod(q)
+	if err != nil {
+		httputil.WriteError(res, http.StatusBadRequest, err.Error())
+		return
+	}
+	var top int
+	if v := q.Get("top"); v != "" {
+		if top, err = strconv.Atoi(v); err != nil || top < 1 {
+			httputil.WriteError(res, http.StatusBadRequest, "Top must be a positive integer")
+			return
+		}
+	}
+	format, table := q.Get("format"), q.Get("table")
+	switch format {
+	case "", "json", "xlsx":
+	case "csv":
+		if table == "" {
+			table = "pairs"
+		}
+		if table != "pairs" && table != "top" && table != "daily" {
+			httputil.WriteError(res, http.StatusBadRequest, "Unsupported table, expected pairs, top or daily")
+			return
+		}
+	default:
+		httputil.WriteError(res, http.StatusBadRequest, "Unsupported format, expected json, csv or xlsx")
+		return
+	}
+
+	report, err := h.svc.ConversionReport(req.Context(), from, to, top)
+	if err != nil {
+		writeServiceError(res, "Failed to build conversion report", err)
+		return
+	}
+
+	if format == "" || format == "json" {
+		httputil.WriteJson(res, http.StatusOK, report)
+		return
+	}
+
+	// Файл собирается в памяти, чтобы ошибка записи ещё могла стать ответом 500.
+	var buf bytes.Buffer
+	var contentType, filename string
+	switch format {
+	case "csv":
+		contentType, filename = "text/csv", "conversions-"+table+".csv"
+		err = writeCSV(&buf, reportTable(report, table))
+	case "xlsx":
+		contentType, filename = xlsx.ContentType, "conversions.xlsx"
+		err = xlsx.Write(&buf,
+			xlsx.Sheet{Name: "Summary", Rows: reportSummary(report)},
+			xlsx.Sheet{Name: "Pairs", Rows: reportTable(report, "pairs")},
+			xlsx.Sheet{Name: "Top pairs", Rows: reportTable(report, "top")},
+			xlsx.Sheet{Name: "Daily", Rows: reportTable(report, "daily")},
+		)
+	}
+	if err != nil {
+		slog.ErrorContext(req.Context(), "failed to write conversion report", "format", format, "error", err)
+		httputil.WriteError(res, http.StatusInternalServerError, "Failed to write conversion report")
+		return
+	}
+	res.Header().Set("Content-Type", contentType)
+	res.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	res.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	res.WriteHeader(http.StatusOK)
+	res.Write(buf.Bytes())
+}
+
+func writeCSV(out io.Writer, rows [][]any) error {
+	w := csv.NewWriter(out)
+	for _, row := range rows {
+		record := make([]string, len(row))
+		for i, v := range row {
+			record[i] = formatCell(v)
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func reportSummary(report *model.ConversionReport) [][]any {
+	period := func(t time.Time) any {
+		if t.IsZero() {
+			return nil
+		}
+		return t.Format(time.RFC3339)
+	}
+	return [][]any{
+		{"from", period(report.From)},
+		{"to", period(report.To)},
+		{"count", report.Count},
+		{"volume_rub", report.VolumeRUB},
+		{"average_amount_rub", report.AverageAmountRUB},
+		{"undated", report.Undated},
+	}
+}
+
+// reportTable - таблица отчёта с заголовком: pairs, top или daily.
+func reportTable(report *model.ConversionReport, table string) [][]any {
+	if table == "daily" {
+		rows := [][]any{{"date", "count", "volume_rub"}}
+		for _, d := range report.Daily {
+			rows = append(rows, []any{d.Date.Format(time.DateOnly), d.Count, d.VolumeRUB})
+		}
+		return rows
+	}
+
+	pairs := report.Pairs
+	if table == "top" {
+		pairs = report.TopPairs
+	}
+	rows := [][]any{{"from", "to", "count", "volume", "converted", "volume_rub", "average_amount"}}
+	for _, p := range pairs {
+		rows = append(rows, []any{p.From, p.To, p.Count, p.Volume, p.Converted, p.VolumeRUB, p.AverageAmount})
+	}
+	return rows
+}
+
+func formatCell(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"currency-converter/internal/model"
+	"currency-converter/internal/transport"
+	"encoding/xml"
+	"sort"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Списки для ответов с выбором формата по Accept (httputil.Write). В JSON и YAML
+// они выглядят как раньше, а XML, CSV и protobuf задаются здесь явно.
+
+// currencyList - ответ /currencies; в JSON это карта по коду, в остальных форматах - список по коду.
+type currencyList map[string]*model.Currency
+
+func (l currencyList) sorted() []*model.Currency {
+	result := make([]*model.Currency, 0, len(l))
+	for _, cur := range l {
+		result = append(result, cur)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Code < result[j].Code })
+	return result
+}
+
+type xmlCurrency struct {
+	Code          string  `xml:"code"`
+	Rate          float64 `xml:"rate"`
+	Name          string  `xml:"name"`
+	Symbol        string  `xml:"symbol"`
+	PreviousRate  float64 `xml:"previous_rate,omitempty"`
+	Change        float64 `xml:"change,omitempty"`
+	ChangePercent float64 `xml:"change_percent,omitempty"`
+}
+
+func toXMLCurrency(cur *model.Currency) *xmlCurrency {
+	if cur == nil {
+		return nil
+	}
+	return &xmlCurrency{
+		Code:          cur.Code,
+		Rate:          cur.Rate,
+		Name:          cur.Name,
+		Symbol:        cur.Symbol,
+		PreviousRate:  cur.PreviousRate,
+		Change:        cur.Change,
+		ChangePercent: cur.ChangePercent,
+	}
+}
+
+func (l currencyList) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	items := make([]*xmlCurrency, 0, len(l))
+	for _, cur := range l.sorted() {
+		items = append(items, toXMLCurrency(cur))
+	}
+	return e.Encode(struct {
+		XMLName xml.Name       `xml:"currencies"`
+		Items   []*xmlCurrency `xml:"currency"`
+	}{Items: items})
+}
+
+func (l currencyList) Header() []string {
+	return []string{"code", "rate", "name", "symbol", "previous_rate", "change", "change_percent"}
+}
+
+func (l currencyList) Rows() [][]string {
+	rows := make([][]string, 0, len(l))
+	for _, cur := range l.sorted() {
+		rows = append(rows, []string{
+			cur.Code, formatFloat(cur.Rate), cur.Name, cur.Symbol,
+			formatFloat(cur.PreviousRate), formatFloat(cur.Change), formatFloat(cur.ChangePercent),
+		})
+	}
+	return rows
+}
+
+func (l currencyList) ToProto() proto.Message {
+	return transport.CurrenciesToProto(l)
+}
+
+// conversionList - ответ /conversions в порядке записи.
+type conversionList []*model.Conversion
+
+type xmlConversion struct {
+	Amount    float64      `xml:"amount"`
+	From      *xmlCurrency `xml:"from"`
+	To        *xmlCurrency `xml:"to"`
+	Result    float64      `xml:"result"`
+	CreatedAt string       `xml:"created_at,omitempty"`
+}
+
+func (l conversionList) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	items := make([]*xmlConversion, 0, len(l))
+	for _, conv := range l {
+		items = append(items, &xmlConversion{
+			Amount:    conv.Amount,
+			From:      toXMLCurrency(conv.From),
+			To:        toXMLCurrency(conv.To),
+			Result:    conv.Result,
+			CreatedAt: formatTime(conv.CreatedAt),
+		})
+	}
+	return e.Encode(struct {
+		XMLName xml.Name         `xml:"conversions"`
+		Items   []*xmlConversion `xml:"conversion"`
+	}{Items: items})
+}
+
+func (l conversionList) Header() []string {
+	return []string{"created_at", "amount", "from", "from_rate", "to", "to_rate", "result"}
+}
+
+func (l conversionList) Rows() [][]string {
+	rows := make([][]string, 0, len(l))
+	for _, conv := range l {
+		var from, to string
+		var fromRate, toRate float64
+		if conv.From != nil {
+			from, fromRate = conv.From.Code, conv.From.Rate
+		}
+		if conv.To != nil {
+			to, toRate = conv.To.Code, conv.To.Rate
+		}
+		rows = append(rows, []string{
+			formatTime(conv.CreatedAt), formatFloat(conv.Amount),
+			from, formatFloat(fromRate), to, formatFloat(toRate), formatFloat(conv.Result),
+		})
+	}
+	return rows
+}
+
+func (l conversionList) ToProto() proto.Message {
+	return transport.ConversionsToProto(l)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"bytes"
+	"currency-converter/internal/archive"
+	"currency-converter/internal/httputil"
+	"currency-converter/internal/model"
+	"currency-converter/internal/service"
+	"currency-converter/internal/transport"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxImportSize ограничивает размер загружаемого архива: он целиком читается в память.
+const maxImportSize = 64 << 20
+
+type TransferHandler struct {
+	svc service.Service
+}
+
+func NewTransferHandler(svc service.Service) *TransferHandler {
+	return &TransferHandler{svc: svc}
+}
+
+// Export godoc
+// @Summary Export data
+// @Description Downloads a versioned zip archive with currencies, rate history and conversions for moving data between environments. The archive contains manifest.json (format version, record counts) and one file per section in JSON Lines or CSV
+// @Tags admin
+// @Produce application/zip
+// @Param format query string false "Format of the sections (default jsonl)" Enums(jsonl, csv)
+// @Success 200 {file} file "Zip archive"
+// @Failure 400 {object} map[string]string "Unsupported format"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /admin/export [get]
+func (h *TransferHandler) Export(res http.ResponseWriter, req *http.Request) {
+	format := archive.Format(req.URL.Query().Get("format"))
+	if format == "" {
+		format = archive.FormatJSONL
+	}
+	if format != archive.FormatJSONL && format != archive.FormatCSV {
+		httputil.WriteError(res, http.StatusBadRequest, "Unsupported format, expected jsonl or csv")
+		return
+	}
+
+	a, err := h.svc.Export(req.Context())
+	if err != nil {
+		writeServiceError(res, "Failed to export data", err)
+		return
+	}
+
+	// Архив собирается в памяти, чтобы ошибка записи ещё могла стать ответом 500.
+	var buf bytes.Buffer
+	if err := archive.Write(&buf, a, format); err != nil {
+		slog.ErrorContext(req.Context(), "failed to write export archive", "error", err)
+		httputil.WriteError(res, http.StatusInternalServerError, "Failed to write export archive")
+		return
+	}
+	filename := "currency-export-" + time.Now().UTC().Format("20060102-150405") + "-" + string(format) + ".zip"
+	res.Header().Set("Content-Type", "application/zip")
+	res.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	res.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	res.WriteHeader(http.StatusOK)
+	res.Write(buf.Bytes())
+}
+
+// Import godoc
+// @Summary Import data
+// @Description Imports an archive produced by GET /admin/export (any supported version and format). All records are validated first; invalid records (422) or, with on_conflict=fail, currencies that already exist with different data (409) abort the whole import and the report lists them. Rate history and conversions are merged: records that already exist are skipped. With dry_run=true nothing is changed and the report shows what would happen
+// @Tags admin
+// @Accept application/zip
+// @Produce json
+// @Param archive body string true "Zip archive from /admin/export"
+// @Param dry_run query bool false "Only validate and report"
+// @Param on_conflict query string false "What to do with currencies that differ (default fail)" Enums(fail, skip, overwrite)
+// @Success 200 {object} model.ImportReport "Import report"
+// @Failure 400 {object} map[string]string "Invalid archive or parameters"
+// @Failure 409 {object} model.ImportReport "Conflicting currencies, nothing imported"
+// @Failure 413 {object} map[string]string "Archive is too large"
+// @Failure 422 {object} model.ImportReport "Invalid records, nothing imported"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /admin/import [post]
+func (h *TransferHandler) Import(res http.ResponseWriter, req *http.Request) {
+	q := req.URL.Query()
+	opts := model.ImportOptions{OnConflict: model.ConflictPolicy(q.Get("on_conflict"))}
+	switch opts.OnConflict {
+	case "", model.ConflictFail, model.ConflictSkip, model.ConflictOverwrite:
+	default:
+		httputil.WriteError(res, http.StatusBadRequest, "Invalid 'on_conflict', expected fail, skip or overwrite")
+		return
+	}
+	if v := q.Get("dry_run"); v != "" {
+		var err error
+		if opts.DryRun, err = strconv.ParseBool(v); err != nil {
+			httputil.WriteError(res, http.StatusBadRequest, "Invalid 'dry_run', expected true or false")
+			return
+		}
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(res, req.Body, maxImportSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			httputil.WriteError(res, http.StatusRequestEntityTooLarge, "Archive is larger than "+strconv.Itoa(maxImportSize>>20)+" MiB")
+			return
+		}
+		httputil.WriteError(res, http.StatusBadRequest, "Failed to read archive")
+		return
+	}
+	a, err := archive.Read(data)
+	if err != nil {
+		writeServiceError(res, "Import failed", err)
+		return
+	}
+
+	report, err := h.svc.Import(req.Context(), a, opts)
+	if err != nil {
+		// Отказ из-за записей архива возвращает отчёт, по которому их можно исправить.
+		if report != nil {
+			httputil.WriteJson(res, transport.HTTPStatus(err), report)
+			return
+		}
+		writeServiceError(res, "Import failed", err)
+		return
+	}
+	httputil.WriteJson(res, http.StatusOK, report)
+}
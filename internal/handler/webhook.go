@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"currency-converter/internal/httputil"
+	"currency-converter/internal/model"
+	"currency-converter/internal/service"
+	"net/http"
+)
+
+type WebhookHandler struct {
+	svc service.Service
+}
+
+func NewWebhookHandler(svc service.Service) *WebhookHandler {
+	return &WebhookHandler{svc: svc}
+}
+
+// CreateWebhook godoc
+// @Summary Subscribe to events
+// @Description Registers a URL that receives events as signed JSON POST requests. Events: rates.updated, currency.created, currency.updated, currency.deleted, conversion.created; an empty list subscribes to all of them. Every request carries X-Webhook-ID, X-Webhook-Event, X-Webhook-Timestamp and X-Webhook-Signature = "sha256=" + hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the secret. Failed deliveries are retried with exponential backoff and end up in the dead-letter list. The secret is returned only in this response
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Param webhook body model.WebhookSubscriptionRequest true "Subscription" Example({"url": "https://example.com/hook", "events": ["rates.updated"]})
+// @Success 201 {object} model.WebhookSubscription
+// @Failure 400 {object} httputil.ErrorResponse "Invalid subscription, listed by field"
+// @Failure 413 {object} map[string]string "Request body is too large"
+// @Failure 415 {object} map[string]string "Content-Type is not application/json"
+// @Failure 500 {object} map[string]string "Failed to persist subscription"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /webhooks [post]
+func (h *WebhookHandler) CreateWebhook(res http.ResponseWriter, req *http.Request) {
+	var subReq model.WebhookSubscriptionRequest
+	if err := httputil.ReadJson(res, req, &subReq); err != nil {
+		httputil.WriteRequestError(res, err)
+		return
+	}
+
+	sub, err := h.svc.CreateWebhook(req.Context(), &subReq)
+	if err != nil {
+		writeServiceError(res, "Failed to create webhook", err)
+		return
+	}
+	httputil.WriteJson(res, http.StatusCreated, sub)
+}
+
+// ListWebhooks godoc
+// @Summary List event subscriptions
+// @Description Retrieves all subscriptions in creation order, without secrets
+// @Tags webhook
+// @Produce json
+// @Success 200 {array} model.WebhookSubscription
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /webhooks [get]
+func (h *WebhookHandler) ListWebhooks(res http.ResponseWriter, req *http.Request) {
+	subs, err := h.svc.ListWebhooks(req.Context())
+	if err != nil {
+		writeServiceError(res, "Failed to retrieve webhooks", err)
+		return
+	}
+	httputil.WriteJson(res, http.StatusOK, subs)
+}
+
+// GetWebhook godoc
+// @Summary Get event subscription
+// @Tags webhook
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} model.WebhookSubscription
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(res http.ResponseWriter, req *http.Request) {
+	sub, err := h.svc.GetWebhook(req.Context(), req.PathValue("id"))
+	if err != nil {
+		writeServiceError(res, "Failed to get webhook", err)
+		return
+	}
+	httputil.WriteJson(res, http.StatusOK, sub)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete event subscription
+// @Description Pending deliveries of the subscription are not sent anymore
+// @Tags webhook
+// @Param id path string true "Subscription ID"
+// @Success 204 "Subscription deleted"
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(res http.ResponseWriter, req *http.Request) {
+	if err := h.svc.DeleteWebhook(req.Context(), req.PathValue("id")); err != nil {
+		writeServiceError(res, "Failed to delete webhook", err)
+		return
+	}
+	res.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries godoc
+// @Summary Get delivery history of a subscription
+// @Description Retrieves deliveries of the subscription, newest first, with the number of attempts, the last response code and error
+// @Tags webhook
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {array} model.WebhookDelivery
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(res http.ResponseWriter, req *http.Request) {
+	deliveries, err := h.svc.ListWebhookDeliveries(req.Context(), req.PathValue("id"))
+	if err != nil {
+		writeServiceError(res, "Failed to retrieve webhook deliveries", err)
+		return
+	}
+	httputil.WriteJson(res, http.StatusOK, deliveries)
+}
+
+// ListDeadLetters godoc
+// @Summary Get dead-letter deliveries
+// @Description Retrieves deliveries that failed after all retry attempts, newest first
+// @Tags webhook
+// @Produce json
+// @Success 200 {array} model.WebhookDelivery
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /webhooks/dead-letters [get]
+func (h *WebhookHandler) ListDeadLetters(res http.ResponseWriter, req *http.Request) {
+	deliveries, err := h.svc.ListDeadLetters(req.Context())
+	if err != nil {
+		writeServiceError(res, "Failed to retrieve dead letters", err)
+		return
+	}
+	httputil.WriteJson(res, http.StatusOK, deliveries)
+}
+
+// RetryDelivery godoc
+// @Summary Retry dead-letter delivery
+// @Description Sends the same payload again with a fresh set of retry attempts
+// @Tags webhook
+// @Produce json
+// @Param id path string true "Delivery ID"
+// @Success 202 {object} model.WebhookDelivery
+// @Failure 404 {object} map[string]string "Delivery not found"
+// @Failure 422 {object} map[string]string "Delivery is not dead"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /webhooks/deliveries/{id}/retry [post]
+func (h *WebhookHandler) RetryDelivery(res http.ResponseWriter, req *http.Request) {
+	delivery, err := h.svc.RetryDelivery(req.Context(), req.PathValue("id"))
+	if err != nil {
+		writeServiceError(res, "Failed to retry webhook delivery", err)
+		return
+	}
+	httputil.WriteJson(res, http.StatusAccepted, delivery)
+}
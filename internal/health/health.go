@@ -0,0 +1,192 @@
+package health
+
+import (
+	"context"
+	"currency-converter/internal/httputil"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// RatesService - имя сервиса в grpc.health.v1, которое отражает свежесть курсов.
+// Общий статус ("") остаётся SERVING, пока сервис готов отдавать хотя бы кэшированные курсы.
+const RatesService = "currency-converter.rates"
+
+type Status string
+
+const (
+	StatusOK       Status = "ok"
+	StatusDegraded Status = "degraded"
+	StatusNotReady Status = "not_ready"
+)
+
+// RatesSource сообщает, сколько курсов загружено и когда они в последний раз синхронизировались с ЦБ РФ.
+type RatesSource interface {
+	RatesStatus() (count int, lastSync time.Time)
+}
+
+type Report struct {
+	Status   Status            `json:"status"`
+	Checks   map[string]string `json:"checks"`
+	LastSync *time.Time        `json:"last_sync,omitempty"`
+}
+
+type Checker struct {
+	mu               sync.RWMutex
+	currenciesLoaded bool
+	shuttingDown     bool
+
+	rates      RatesSource
+	staleAfter time.Duration
+	interval   time.Duration
+	grpc       *grpchealth.Server
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewChecker(rates RatesSource, staleAfter time.Duration) *Checker {
+	c := &Checker{
+		rates:      rates,
+		staleAfter: staleAfter,
+		interval:   5 * time.Second,
+		grpc:       grpchealth.NewServer(),
+	}
+	c.updateGRPC()
+	return c
+}
+
+// GRPCServer - реализация grpc.health.v1 для регистрации на gRPC-сервере.
+func (c *Checker) GRPCServer() healthpb.HealthServer {
+	return c.grpc
+}
+
+// MarkCurrenciesLoaded вызывается после того, как LoadCurrencies отработал без ошибок.
+func (c *Checker) MarkCurrenciesLoaded() {
+	c.mu.Lock()
+	c.currenciesLoaded = true
+	c.mu.Unlock()
+	c.updateGRPC()
+}
+
+// Check - готов ли сервис принимать трафик: валюты загружены и есть хотя бы кэшированные курсы.
+// Устаревшие курсы не снимают готовность, а переводят её в degraded.
+func (c *Checker) Check() Report {
+	c.mu.RLock()
+	loaded, shuttingDown := c.currenciesLoaded, c.shuttingDown
+	c.mu.RUnlock()
+
+	report := Report{Status: StatusOK, Checks: map[string]string{}}
+	count, lastSync := c.rates.RatesStatus()
+	if !lastSync.IsZero() {
+		report.LastSync = &lastSync
+	}
+
+	switch {
+	case shuttingDown:
+		report.Checks["lifecycle"] = "shutting down"
+		report.Status = StatusNotReady
+	case !loaded:
+		report.Checks["currencies"] = "not loaded"
+		report.Status = StatusNotReady
+	case count == 0:
+		report.Checks["rates"] = "no rates loaded"
+		report.Status = StatusNotReady
+	case lastSync.IsZero():
+		report.Checks["rates"] = "serving cached rates, no successful sync yet"
+		report.Status = StatusDegraded
+	case time.Since(lastSync) > c.staleAfter:
+		report.Checks["rates"] = "stale, last sync " + time.Since(lastSync).Round(time.Second).String() + " ago"
+		report.Status = StatusDegraded
+	default:
+		report.Checks["rates"] = "ok"
+	}
+	return report
+}
+
+func (c *Checker) updateGRPC() {
+	report := c.Check()
+
+	overall := healthpb.HealthCheckResponse_SERVING
+	rates := healthpb.HealthCheckResponse_SERVING
+	switch report.Status {
+	case StatusNotReady:
+		overall = healthpb.HealthCheckResponse_NOT_SERVING
+		rates = healthpb.HealthCheckResponse_NOT_SERVING
+	case StatusDegraded:
+		rates = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	c.grpc.SetServingStatus("", overall)
+	c.grpc.SetServingStatus(RatesService, rates)
+}
+
+// Liveness godoc
+// @Summary Liveness probe
+// @Description Returns 200 while the process is able to serve HTTP requests
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /healthz [get]
+func (c *Checker) Liveness(res http.ResponseWriter, req *http.Request) {
+	httputil.WriteJson(res, http.StatusOK, map[string]string{"status": string(StatusOK)})
+}
+
+// Readiness godoc
+// @Summary Readiness probe
+// @Description Returns 200 once currencies are loaded and at least cached rates are available (status "degraded" when rates are stale), 503 otherwise
+// @Tags health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /readyz [get]
+func (c *Checker) Readiness(res http.ResponseWriter, req *http.Request) {
+	report := c.Check()
+	status := http.StatusOK
+	if report.Status == StatusNotReady {
+		status = http.StatusServiceUnavailable
+	}
+	httputil.WriteJson(res, status, report)
+}
+
+func (c *Checker) Name() string { return "health monitor" }
+
+// Start периодически пересчитывает статус для grpc.health.v1, чтобы Watch-клиенты
+// узнавали об устаревании курсов без запросов.
+func (c *Checker) Start(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	c.cancel = cancel
+	c.done = make(chan struct{})
+
+	go func() {
+		defer close(c.done)
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			c.updateGRPC()
+			select {
+			case <-ticker.C:
+			case <-runCtx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop снимает готовность первой, чтобы балансировщик перестал слать трафик,
+// пока остальные компоненты завершают работу.
+func (c *Checker) Stop(ctx context.Context) error {
+	c.mu.Lock()
+	c.shuttingDown = true
+	c.mu.Unlock()
+
+	c.cancel()
+	<-c.done
+	c.grpc.Shutdown()
+	slog.InfoContext(ctx, "health status switched to NOT_SERVING")
+	return nil
+}
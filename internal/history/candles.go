@@ -0,0 +1,169 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var ErrInvalidCandles = errors.New("invalid candles request")
+
+// baseCurrency - валюта, к которой хранятся все курсы.
+const baseCurrency = "RUB"
+
+// Interval - длина свечи: N дней, недель (с понедельника), месяцев или лет.
+type Interval struct {
+	N    int
+	Unit byte // 'd', 'w', 'M' или 'y'
+}
+
+// ParseInterval разбирает интервал вида "1d", "1w", "3M", "1y"; число можно опустить.
+func ParseInterval(s string) (Interval, error) {
+	if s == "" {
+		return Interval{}, fmt.Errorf("%w: interval is required", ErrInvalidCandles)
+	}
+	iv := Interval{N: 1, Unit: s[len(s)-1]}
+	if num := s[:len(s)-1]; num != "" {
+		n, err := strconv.Atoi(num)
+		if err != nil || n < 1 || n > 366 {
+			return Interval{}, fmt.Errorf("%w: interval count must be from 1 to 366", ErrInvalidCandles)
+		}
+		iv.N = n
+	}
+	switch iv.Unit {
+	case 'd', 'w', 'M', 'y':
+		return iv, nil
+	}
+	return Interval{}, fmt.Errorf("%w: interval unit must be d, w, M or y", ErrInvalidCandles)
+}
+
+func (iv Interval) String() string {
+	return strconv.Itoa(iv.N) + string(iv.Unit)
+}
+
+// epochMonday - понедельник, от которого отсчитываются недельные свечи.
+var epochMonday = time.Date(1970, 1, 5, 0, 0, 0, 0, time.UTC)
+
+// bucket возвращает границы свечи [start, end), в которую попадает день d.
+func (iv Interval) bucket(d time.Time) (time.Time, time.Time) {
+	switch iv.Unit {
+	case 'w':
+		weeks := int(d.Sub(epochMonday).Hours()) / (24 * 7)
+		start := epochMonday.AddDate(0, 0, weeks/iv.N*iv.N*7)
+		return start, start.AddDate(0, 0, iv.N*7)
+	case 'M':
+		months := (d.Year()-1970)*12 + int(d.Month()) - 1
+		months -= months % iv.N
+		start := time.Date(1970+months/12, time.Month(months%12+1), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, iv.N, 0)
+	case 'y':
+		year := d.Year() - (d.Year()-1970)%iv.N
+		start := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(iv.N, 0, 0)
+	default:
+		days := int(d.Unix() / 86400)
+		start := time.Unix(int64(days/iv.N*iv.N)*86400, 0).UTC()
+		return start, start.AddDate(0, 0, iv.N)
+	}
+}
+
+// Candle - свеча OHLC за [Start, End); Days - сколько дней с курсами в неё вошло.
+type Candle struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Open  float64   `json:"open"`
+	High  float64   `json:"high"`
+	Low   float64   `json:"low"`
+	Close float64   `json:"close"`
+	Days  int       `json:"days"`
+}
+
+// Candles - ряд свечей курса Base, выраженного в Quote.
+type Candles struct {
+	Base     string   `json:"base"`
+	Quote    string   `json:"quote"`
+	Interval string   `json:"interval"`
+	Candles  []Candle `json:"candles"`
+}
+
+// Candles строит свечи пары base/quote за [from, to) из дневных баров. Для пары
+// с рублём используются бары самой валюты. Кросс-курс известен только по дням, в которые
+// есть курсы обеих валют, и только на закрытие: внутридневные значения двух валют
+// наблюдались в разное время, поэтому Open/High/Low кросс-свечи строятся по дневным закрытиям.
+func (s *Store) Candles(base, quote string, iv Interval, from, to time.Time) (*Candles, error) {
+	if base == quote {
+		return nil, fmt.Errorf("%w: base and quote must differ", ErrInvalidCandles)
+	}
+
+	s.mu.RLock()
+	daily := s.pairBars(base, quote, from, to)
+	s.mu.RUnlock()
+	if len(daily) == 0 {
+		return nil, ErrNoData
+	}
+
+	result := &Candles{Base: base, Quote: quote, Interval: iv.String(), Candles: []Candle{}}
+	var cur *Candle
+	for _, b := range daily {
+		if cur == nil || !b.Date.Before(cur.End) {
+			start, end := iv.bucket(b.Date)
+			result.Candles = append(result.Candles, Candle{Start: start, End: end, Open: b.Open, High: b.High, Low: b.Low})
+			cur = &result.Candles[len(result.Candles)-1]
+		}
+		cur.High = max(cur.High, b.High)
+		cur.Low = min(cur.Low, b.Low)
+		cur.Close = b.Close
+		cur.Days++
+	}
+	return result, nil
+}
+
+// pairBars - дневные бары курса base в quote; вызывается под блокировкой на чтение.
+func (s *Store) pairBars(base, quote string, from, to time.Time) []Bar {
+	barsOf := func(code string) []Bar {
+		ser, ok := s.series[code]
+		if !ok {
+			return nil
+		}
+		i, j := ser.window(from, to)
+		return ser.bars[i:j]
+	}
+
+	switch {
+	case quote == baseCurrency:
+		return barsOf(base)
+	case base == baseCurrency:
+		quoted := barsOf(quote)
+		result := make([]Bar, 0, len(quoted))
+		for _, q := range quoted {
+			result = append(result, Bar{Date: q.Date, Open: 1 / q.Open, High: 1 / q.Low, Low: 1 / q.High, Close: 1 / q.Close, Samples: q.Samples})
+		}
+		return result
+	}
+
+	based, quoted := barsOf(base), barsOf(quote)
+	result := make([]Bar, 0, min(len(based), len(quoted)))
+	for i, j := 0, 0; i < len(based) && j < len(quoted); {
+		b, q := based[i], quoted[j]
+		switch {
+		case b.Date.Before(q.Date):
+			i++
+		case q.Date.Before(b.Date):
+			j++
+		default:
+			rate := b.Close / q.Close
+			result = append(result, Bar{
+				Date:    b.Date,
+				Open:    rate,
+				High:    rate,
+				Low:     rate,
+				Close:   rate,
+				Samples: min(b.Samples, q.Samples),
+			})
+			i++
+			j++
+		}
+	}
+	return result
+}
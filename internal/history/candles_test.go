@@ -0,0 +1,192 @@
+package history
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// candleOf - ожидаемая свеча из курсов по одному на день.
+func candleOf(start, end time.Time, rates []float64) Candle {
+	return Candle{
+		Start: start,
+		End:   end,
+		Open:  rates[0],
+		High:  slices.Max(rates),
+		Low:   slices.Min(rates),
+		Close: rates[len(rates)-1],
+		Days:  len(rates),
+	}
+}
+
+func date(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Interval
+		wantErr bool
+	}{
+		{in: "d", want: Interval{N: 1, Unit: 'd'}},
+		{in: "2w", want: Interval{N: 2, Unit: 'w'}},
+		{in: "3M", want: Interval{N: 3, Unit: 'M'}},
+		{in: "", wantErr: true},
+		{in: "0d", wantErr: true},
+		{in: "1h", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseInterval(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidCandles) {
+				t.Errorf("ParseInterval(%q) error = %v, want ErrInvalidCandles", tt.in, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseInterval(%q) = %+v, %v; want %+v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestCandles(t *testing.T) {
+	// epoch - среда 1 января 2025.
+	rates := closes(70, 11)
+	s := storeOf("USD", rates)
+
+	tests := []struct {
+		name string
+		iv   Interval
+		days int // сколько дней истории запрашивать
+		want []Candle
+	}{
+		{
+			name: "weeks start on Monday",
+			iv:   Interval{N: 1, Unit: 'w'},
+			days: 14,
+			want: []Candle{
+				candleOf(date(2024, 12, 30), date(2025, 1, 6), rates[0:5]),
+				candleOf(date(2025, 1, 6), date(2025, 1, 13), rates[5:12]),
+				candleOf(date(2025, 1, 13), date(2025, 1, 20), rates[12:14]),
+			},
+		},
+		{
+			name: "months",
+			iv:   Interval{N: 1, Unit: 'M'},
+			days: 70,
+			want: []Candle{
+				candleOf(date(2025, 1, 1), date(2025, 2, 1), rates[0:31]),
+				candleOf(date(2025, 2, 1), date(2025, 3, 1), rates[31:59]),
+				candleOf(date(2025, 3, 1), date(2025, 4, 1), rates[59:70]),
+			},
+		},
+		{
+			name: "quarter",
+			iv:   Interval{N: 3, Unit: 'M'},
+			days: 70,
+			want: []Candle{candleOf(date(2025, 1, 1), date(2025, 4, 1), rates)},
+		},
+		{
+			name: "days",
+			iv:   Interval{N: 1, Unit: 'd'},
+			days: 2,
+			want: []Candle{
+				candleOf(date(2025, 1, 1), date(2025, 1, 2), rates[0:1]),
+				candleOf(date(2025, 1, 2), date(2025, 1, 3), rates[1:2]),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Candles("USD", baseCurrency, tt.iv, epoch, epoch.AddDate(0, 0, tt.days))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got.Candles, tt.want) {
+				t.Errorf("candles = %+v\nwant %+v", got.Candles, tt.want)
+			}
+		})
+	}
+}
+
+func TestTwoWeekCandlesAreAligned(t *testing.T) {
+	s := storeOf("USD", closes(40, 2))
+	got, err := s.Candles("USD", baseCurrency, Interval{N: 2, Unit: 'w'}, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	days := 0
+	for _, c := range got.Candles {
+		if c.Start.Weekday() != time.Monday || c.End.Sub(c.Start) != 14*24*time.Hour {
+			t.Errorf("candle %v - %v, want two weeks from a Monday", c.Start, c.End)
+		}
+		days += c.Days
+	}
+	if days != 40 {
+		t.Errorf("days in candles = %d, want 40", days)
+	}
+}
+
+func TestCrossCandles(t *testing.T) {
+	s := &Store{series: make(map[string]*series)}
+	add := func(code string, d int, hour int, rate float64) {
+		s.seriesFor(code).add(epoch.AddDate(0, 0, d).Add(time.Duration(hour)*time.Hour), rate)
+	}
+	// Внутри дня курсы валют расходятся в разное время, поэтому кросс-курс берётся по закрытию.
+	add("USD", 0, 9, 80)
+	add("USD", 0, 18, 90)
+	add("EUR", 0, 9, 100)
+	add("EUR", 0, 15, 120)
+	add("EUR", 0, 18, 100)
+	// Во второй день нет курса евро - он в свечу не попадает.
+	add("USD", 1, 12, 200)
+	add("USD", 2, 12, 95)
+	add("EUR", 2, 12, 100)
+	add("USD", 3, 12, 85)
+	add("EUR", 3, 12, 100)
+
+	tests := []struct {
+		name        string
+		base, quote string
+		want        Candle
+	}{
+		{
+			name: "cross pair uses closes",
+			base: "USD", quote: "EUR",
+			want: Candle{Start: date(2024, 12, 30), End: date(2025, 1, 6), Open: 0.9, High: 0.95, Low: 0.85, Close: 0.85, Days: 3},
+		},
+		{
+			name: "ruble base inverts the bars",
+			base: baseCurrency, quote: "EUR",
+			want: Candle{Start: date(2024, 12, 30), End: date(2025, 1, 6), Open: 0.01, High: 0.01, Low: 1.0 / 120, Close: 0.01, Days: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Candles(tt.base, tt.quote, Interval{N: 1, Unit: 'w'}, time.Time{}, time.Time{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Candles) != 1 {
+				t.Fatalf("candles = %+v, want one week", got.Candles)
+			}
+			c := got.Candles[0]
+			if c.Start != tt.want.Start || c.End != tt.want.End || c.Days != tt.want.Days ||
+				!near(c.Open, tt.want.Open) || !near(c.High, tt.want.High) || !near(c.Low, tt.want.Low) || !near(c.Close, tt.want.Close) {
+				t.Errorf("candle = %+v, want %+v", c, tt.want)
+			}
+		})
+	}
+}
+
+func TestCandlesErrors(t *testing.T) {
+	s := storeOf("USD", closes(3, 1))
+	if _, err := s.Candles("USD", "USD", Interval{N: 1, Unit: 'd'}, time.Time{}, time.Time{}); !errors.Is(err, ErrInvalidCandles) {
+		t.Errorf("same base and quote: error = %v, want ErrInvalidCandles", err)
+	}
+	if _, err := s.Candles("USD", "EUR", Interval{N: 1, Unit: 'd'}, time.Time{}, time.Time{}); !errors.Is(err, ErrNoData) {
+		t.Errorf("unknown quote: error = %v, want ErrNoData", err)
+	}
+}
@@ -0,0 +1,338 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+var ErrNoData = errors.New("no rate history for the period")
+
+// Observation - курс валюты к рублю в момент записи; строка журнала истории.
+type Observation struct {
+	Time time.Time `json:"time"`
+	Code string    `json:"code"`
+	Rate float64   `json:"rate"`
+}
+
+// Bar - курс валюты за сутки (UTC): первое, наибольшее, наименьшее и последнее значение.
+type Bar struct {
+	Date    time.Time `json:"date"`
+	Open    float64   `json:"open"`
+	High    float64   `json:"high"`
+	Low     float64   `json:"low"`
+	Close   float64   `json:"close"`
+	Samples int       `json:"samples"`
+}
+
+func day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// Store - история курсов только на дозапись в формате JSON Lines. В памяти каждая
+// валюта хранится дневными барами вместе с агрегатами, которые обновляются при
+// записи, поэтому статистика за период не перебирает всю историю.
+type Store struct {
+	mu     sync.RWMutex
+	path   string
+	file   *os.File
+	series map[string]*series
+}
+
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	s := &Store{path: path, series: make(map[string]*series)}
+	if err := readObservations(path, func(o Observation) { s.seriesFor(o.Code).add(o.Time, o.Rate) }); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rate history: %w", err)
+	}
+	s.file = file
+	return s, nil
+}
+
+// readObservations передаёт fn все наблюдения из журнала по порядку записи.
+func readObservations(path string, fn func(Observation)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read rate history: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var o Observation
+		if err := json.Unmarshal(scanner.Bytes(), &o); err != nil {
+			return fmt.Errorf("failed to parse rate history line %d: %w", line, err)
+		}
+		fn(o)
+	}
+	return scanner.Err()
+}
+
+func (s *Store) seriesFor(code string) *series {
+	ser, ok := s.series[code]
+	if !ok {
+		ser = &series{}
+		s.series[code] = ser
+	}
+	return ser
+}
+
+// Record запоминает курс валюты. Повтор того же курса в те же сутки не записывается,
+// поэтому ежечасная синхронизация добавляет в журнал одну строку на валюту в день.
+func (s *Store) Record(code string, rate float64, at time.Time) error {
+	if s == nil || rate <= 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ser := s.seriesFor(code)
+	if last := ser.last(); last != nil && last.Date.Equal(day(at)) && last.Close == rate {
+		return nil
+	}
+
+	line, err := json.Marshal(Observation{Time: at.UTC(), Code: code, Rate: rate})
+	if err != nil {
+		return fmt.Errorf("failed to marshal rate observation: %w", err)
+	}
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write rate observation: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync rate history: %w", err)
+	}
+
+	ser.add(at, rate)
+	return nil
+}
+
+// Bars возвращает дневные бары валюты за [from, to); нулевые границы не ограничивают период.
+func (s *Store) Bars(code string, from, to time.Time) []Bar {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ser, ok := s.series[code]
+	if !ok {
+		return []Bar{}
+	}
+	i, j := ser.window(from, to)
+	result := make([]Bar, j-i)
+	copy(result, ser.bars[i:j])
+	return result
+}
+
+// Observations возвращает весь журнал истории в порядке записи.
+func (s *Store) Observations() ([]Observation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := []Observation{}
+	if err := readObservations(s.path, func(o Observation) { result = append(result, o) }); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Import дописывает наблюдения в журнал одним сбросом на диск. В отличие от Record
+// повторы не отбрасываются: проверять их должен вызывающий. Возвращённая undo
+// отменяет импорт, если после него в журнал ещё ничего не записано.
+func (s *Store) Import(observations []Observation) (undo func() error, err error) {
+	if len(observations) == 0 {
+		return func() error { return nil }, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := s.file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat rate history: %w", err)
+	}
+	offset := info.Size()
+
+	// По времени бары достраиваются в конец ряда, а не вставляются с пересчётом агрегатов.
+	sorted := make([]Observation, len(observations))
+	copy(sorted, observations)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	var buf []byte
+	for _, o := range sorted {
+		line, err := json.Marshal(Observation{Time: o.Time.UTC(), Code: o.Code, Rate: o.Rate})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal rate observation: %w", err)
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+	if _, err := s.file.Write(buf); err != nil {
+		s.file.Truncate(offset)
+		return nil, fmt.Errorf("failed to write rate observations: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		s.file.Truncate(offset)
+		return nil, fmt.Errorf("failed to sync rate history: %w", err)
+	}
+
+	for _, o := range sorted {
+		s.seriesFor(o.Code).add(o.Time, o.Rate)
+	}
+	return func() error { return s.truncate(offset) }, nil
+}
+
+// truncate обрезает журнал до offset байт и заново строит ряды по оставшимся наблюдениям.
+func (s *Store) truncate(offset int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.file.Truncate(offset); err != nil {
+		return fmt.Errorf("failed to truncate rate history: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync rate history: %w", err)
+	}
+	s.series = make(map[string]*series)
+	return readObservations(s.path, func(o Observation) { s.seriesFor(o.Code).add(o.Time, o.Rate) })
+}
+
+func (s *Store) Close() error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// series - дневные бары одной валюты по возрастанию даты и агрегаты по ним:
+// префиксные суммы закрытий (для скользящих средних) и разреженные таблицы
+// минимумов и максимумов закрытий.
+type series struct {
+	bars []Bar
+
+	sum []float64 // sum[i] - сумма Close по bars[:i]
+
+	minTable, maxTable [][]float64 // table[l][i] - экстремум Close по bars[i : i+2^l]
+}
+
+func (ser *series) last() *Bar {
+	if len(ser.bars) == 0 {
+		return nil
+	}
+	return &ser.bars[len(ser.bars)-1]
+}
+
+func (ser *series) add(at time.Time, rate float64) {
+	d := day(at)
+	n := len(ser.bars)
+	switch {
+	case n > 0 && ser.bars[n-1].Date.Equal(d):
+		b := &ser.bars[n-1]
+		b.High = max(b.High, rate)
+		b.Low = min(b.Low, rate)
+		b.Close = rate
+		b.Samples++
+		ser.truncate(n - 1)
+		ser.extend()
+	case n == 0 || ser.bars[n-1].Date.Before(d):
+		ser.bars = append(ser.bars, Bar{Date: d, Open: rate, High: rate, Low: rate, Close: rate, Samples: 1})
+		ser.extend()
+	default:
+		// Запись задним числом: бар вставляется по месту, агрегаты пересчитываются целиком.
+		i := sort.Search(n, func(i int) bool { return !ser.bars[i].Date.Before(d) })
+		if ser.bars[i].Date.Equal(d) {
+			b := &ser.bars[i]
+			b.High = max(b.High, rate)
+			b.Low = min(b.Low, rate)
+			b.Samples++
+		} else {
+			ser.bars = append(ser.bars[:i], append([]Bar{{Date: d, Open: rate, High: rate, Low: rate, Close: rate, Samples: 1}}, ser.bars[i:]...)...)
+		}
+		ser.truncate(0)
+		ser.extend()
+	}
+}
+
+// truncate отбрасывает агрегаты начиная с бара n.
+func (ser *series) truncate(n int) {
+	if len(ser.sum) > n+1 {
+		ser.sum = ser.sum[:n+1]
+	}
+	for l := range ser.minTable {
+		size := max(n-(1<<l)+1, 0)
+		if len(ser.minTable[l]) > size {
+			ser.minTable[l], ser.maxTable[l] = ser.minTable[l][:size], ser.maxTable[l][:size]
+		}
+	}
+}
+
+// extend досчитывает агрегаты для баров, которые ещё не учтены.
+func (ser *series) extend() {
+	if len(ser.sum) == 0 {
+		ser.sum = []float64{0}
+	}
+	for k := len(ser.sum) - 1; k < len(ser.bars); k++ {
+		ser.sum = append(ser.sum, ser.sum[k]+ser.bars[k].Close)
+	}
+
+	n := len(ser.bars)
+	for l := 0; 1<<l <= n; l++ {
+		if l == len(ser.minTable) {
+			ser.minTable = append(ser.minTable, nil)
+			ser.maxTable = append(ser.maxTable, nil)
+		}
+		for i := len(ser.minTable[l]); i+(1<<l) <= n; i++ {
+			if l == 0 {
+				ser.minTable[0] = append(ser.minTable[0], ser.bars[i].Close)
+				ser.maxTable[0] = append(ser.maxTable[0], ser.bars[i].Close)
+				continue
+			}
+			half := 1 << (l - 1)
+			ser.minTable[l] = append(ser.minTable[l], min(ser.minTable[l-1][i], ser.minTable[l-1][i+half]))
+			ser.maxTable[l] = append(ser.maxTable[l], max(ser.maxTable[l-1][i], ser.maxTable[l-1][i+half]))
+		}
+	}
+}
+
+// window - индексы баров периода [from, to).
+func (ser *series) window(from, to time.Time) (int, int) {
+	n := len(ser.bars)
+	i, j := 0, n
+	if !from.IsZero() {
+		from = day(from)
+		i = sort.Search(n, func(k int) bool { return !ser.bars[k].Date.Before(from) })
+	}
+	if !to.IsZero() {
+		j = sort.Search(n, func(k int) bool { return !ser.bars[k].Date.Before(to) })
+	}
+	return i, max(i, j)
+}
+
+// rangeMinMax - наименьший и наибольший Close по bars[i:j] за O(1).
+func (ser *series) rangeMinMax(i, j int) (float64, float64) {
+	l := 0
+	for 1<<(l+1) <= j-i {
+		l++
+	}
+	k := j - 1<<l
+	return min(ser.minTable[l][i], ser.minTable[l][k]), max(ser.maxTable[l][i], ser.maxTable[l][k])
+}
@@ -0,0 +1,96 @@
+package history
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// closes - курсы около 90 с небольшим разбросом, по одному на день.
+func closes(n int, seed uint64) []float64 {
+	rnd := rand.New(rand.NewPCG(seed, seed))
+	result := make([]float64, n)
+	for k := range result {
+		result[k] = 90 + rnd.Float64()
+	}
+	return result
+}
+
+func seriesOf(rates []float64) *series {
+	ser := &series{}
+	for k, rate := range rates {
+		ser.add(epoch.AddDate(0, 0, k), rate)
+	}
+	return ser
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*max(1, math.Abs(a), math.Abs(b))
+}
+
+// checkAggregates сверяет префиксные суммы и разреженные таблицы с прямым перебором.
+func checkAggregates(t *testing.T, ser *series) {
+	t.Helper()
+	n := len(ser.bars)
+	if len(ser.sum) != n+1 {
+		t.Fatalf("len(sum) = %d, want %d", len(ser.sum), n+1)
+	}
+	for i := 0; i < n; i++ {
+		sum, lo, hi := 0.0, math.Inf(1), math.Inf(-1)
+		for j := i + 1; j <= n; j++ {
+			c := ser.bars[j-1].Close
+			sum += c
+			lo, hi = min(lo, c), max(hi, c)
+			if got := ser.sum[j] - ser.sum[i]; !near(got, sum) {
+				t.Fatalf("sum of bars[%d:%d] = %v, want %v", i, j, got, sum)
+			}
+			if gotLo, gotHi := ser.rangeMinMax(i, j); gotLo != lo || gotHi != hi {
+				t.Fatalf("rangeMinMax(%d, %d) = %v, %v; want %v, %v", i, j, gotLo, gotHi, lo, hi)
+			}
+		}
+	}
+}
+
+func TestSeriesAggregates(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 7, 8, 9, 33} {
+		checkAggregates(t, seriesOf(closes(n, uint64(n))))
+	}
+}
+
+func TestSeriesSameDayUpdate(t *testing.T) {
+	ser := seriesOf([]float64{90, 91})
+	ser.add(epoch.AddDate(0, 0, 1).Add(time.Hour), 95)
+	ser.add(epoch.AddDate(0, 0, 1).Add(2*time.Hour), 89)
+
+	want := Bar{Date: day(epoch.AddDate(0, 0, 1)), Open: 91, High: 95, Low: 89, Close: 89, Samples: 3}
+	if got := ser.bars[1]; got != want {
+		t.Fatalf("bar = %+v, want %+v", got, want)
+	}
+	checkAggregates(t, ser)
+}
+
+func TestSeriesBackfill(t *testing.T) {
+	rates := closes(20, 7)
+	inOrder := seriesOf(rates)
+
+	// Сначала каждый третий день, затем пропуски задним числом.
+	backfilled := &series{}
+	for _, step := range []int{0, 1, 2} {
+		for k := step; k < len(rates); k += 3 {
+			backfilled.add(epoch.AddDate(0, 0, k), rates[k])
+		}
+	}
+
+	if len(backfilled.bars) != len(inOrder.bars) {
+		t.Fatalf("len(bars) = %d, want %d", len(backfilled.bars), len(inOrder.bars))
+	}
+	for k := range inOrder.bars {
+		if backfilled.bars[k] != inOrder.bars[k] {
+			t.Fatalf("bars[%d] = %+v, want %+v", k, backfilled.bars[k], inOrder.bars[k])
+		}
+	}
+	checkAggregates(t, backfilled)
+}
@@ -0,0 +1,146 @@
+package history
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// tradingDays - число торговых дней в году для приведения волатильности к годовой.
+const tradingDays = 252
+
+// emaLookback - сколько окон истории используется для EMA: вклад более старых
+// курсов меньше (1-2/(w+1))^(5w) ≈ e^-10 и на результат не влияет.
+const emaLookback = 5
+
+// MovingAverage - значение скользящей средней по Window последним дневным курсам на конец периода.
+type MovingAverage struct {
+	Window int     `json:"window"`
+	Value  float64 `json:"value"`
+}
+
+// Stats - статистика по дневным курсам закрытия за период.
+type Stats struct {
+	Code   string    `json:"code"`
+	From   time.Time `json:"from"` // дата первого бара периода
+	To     time.Time `json:"to"`   // дата последнего бара периода
+	Count  int       `json:"count"`
+	Min    float64   `json:"min"`
+	Max    float64   `json:"max"`
+	Mean   float64   `json:"mean"`
+	Median float64   `json:"median"`
+	StdDev float64   `json:"std_dev"`
+	// Стандартное отклонение дневных лог-доходностей в процентах и оно же в пересчёте на год.
+	Volatility           float64         `json:"volatility"`
+	AnnualizedVolatility float64         `json:"annualized_volatility"`
+	SMA                  []MovingAverage `json:"sma"`
+	EMA                  []MovingAverage `json:"ema"`
+}
+
+// Stats считает статистику валюты за [from, to). Все показатели, включая Min и Max,
+// берутся по курсам закрытия. SMA считаются из префиксных сумм, минимум и максимум -
+// из разреженных таблиц; среднее, отклонения и медиана - по курсам периода, EMA - по
+// последним окнам. Скользящие средние учитывают и курсы до начала периода, окна
+// длиннее всей истории пропускаются.
+func (s *Store) Stats(code string, from, to time.Time, smaWindows, emaWindows []int) (*Stats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ser, ok := s.series[code]
+	if !ok {
+		return nil, ErrNoData
+	}
+	i, j := ser.window(from, to)
+	n := j - i
+	if n == 0 {
+		return nil, ErrNoData
+	}
+
+	st := &Stats{
+		Code:  code,
+		From:  ser.bars[i].Date,
+		To:    ser.bars[j-1].Date,
+		Count: n,
+		SMA:   []MovingAverage{},
+		EMA:   []MovingAverage{},
+	}
+	st.Min, st.Max = ser.rangeMinMax(i, j)
+	closes := make([]float64, 0, n)
+	for _, b := range ser.bars[i:j] {
+		closes = append(closes, b.Close)
+	}
+	st.Mean, st.StdDev = meanStdDev(closes)
+
+	// Доходность каждого дня считается к предыдущему, поэтому у периода их n-1.
+	if n > 1 {
+		returns := make([]float64, 0, n-1)
+		for k := 1; k < n; k++ {
+			returns = append(returns, logReturn(closes[k-1], closes[k]))
+		}
+		_, sd := meanStdDev(returns)
+		st.Volatility = sd * 100
+		st.AnnualizedVolatility = st.Volatility * math.Sqrt(tradingDays)
+	}
+	st.Median = median(closes)
+
+	for _, w := range smaWindows {
+		if w <= j {
+			st.SMA = append(st.SMA, MovingAverage{Window: w, Value: ser.sma(j, w)})
+		}
+	}
+	for _, w := range emaWindows {
+		if w <= j {
+			st.EMA = append(st.EMA, MovingAverage{Window: w, Value: ser.ema(j, w)})
+		}
+	}
+	return st, nil
+}
+
+// meanStdDev - среднее и стандартное отклонение по совокупности методом Уэлфорда:
+// в отличие от sumSq/n - mean² он не теряет точность, когда разброс курсов мал
+// по сравнению с самими курсами.
+func meanStdDev(values []float64) (float64, float64) {
+	var mean, m2 float64
+	for k, v := range values {
+		delta := v - mean
+		mean += delta / float64(k+1)
+		m2 += delta * (v - mean)
+	}
+	if len(values) == 0 {
+		return 0, 0
+	}
+	return mean, math.Sqrt(m2 / float64(len(values)))
+}
+
+func logReturn(prev, next float64) float64 {
+	if prev <= 0 || next <= 0 {
+		return 0
+	}
+	return math.Log(next / prev)
+}
+
+// median сортирует values на месте.
+func median(values []float64) float64 {
+	slices.Sort(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
+
+// sma - простая средняя по w курсам, заканчивающимся перед баром j.
+func (ser *series) sma(j, w int) float64 {
+	return (ser.sum[j] - ser.sum[j-w]) / float64(w)
+}
+
+// ema - экспоненциальная средняя на бар j-1, начатая с SMA первого окна.
+func (ser *series) ema(j, w int) float64 {
+	start := max(j-emaLookback*w, 0)
+	value := ser.sma(start+w, w)
+	alpha := 2 / float64(w+1)
+	for _, b := range ser.bars[start+w : j] {
+		value = alpha*b.Close + (1-alpha)*value
+	}
+	return value
+}
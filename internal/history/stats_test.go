@@ -0,0 +1,162 @@
+package history
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+// naiveStats считает показатели периода прямо по определению, в два прохода.
+func naiveStats(values []float64) (minimum, maximum, mean, med, sd float64) {
+	minimum, maximum = slices.Min(values), slices.Max(values)
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	for _, v := range values {
+		sd += (v - mean) * (v - mean)
+	}
+	sd = math.Sqrt(sd / float64(len(values)))
+
+	sorted := slices.Sorted(slices.Values(values))
+	if n := len(sorted); n%2 == 1 {
+		med = sorted[n/2]
+	} else {
+		med = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+	return minimum, maximum, mean, med, sd
+}
+
+// naiveEMA - EMA на конец values, начатая с SMA первого из последних emaLookback окон.
+func naiveEMA(values []float64, w int) float64 {
+	values = values[max(len(values)-emaLookback*w, 0):]
+	var value float64
+	for _, v := range values[:w] {
+		value += v / float64(w)
+	}
+	alpha := 2 / float64(w+1)
+	for _, v := range values[w:] {
+		value = alpha*v + (1-alpha)*value
+	}
+	return value
+}
+
+func storeOf(code string, rates []float64) *Store {
+	s := &Store{series: make(map[string]*series)}
+	for k, rate := range rates {
+		s.seriesFor(code).add(epoch.AddDate(0, 0, k), rate)
+	}
+	return s
+}
+
+func TestStats(t *testing.T) {
+	rates := closes(60, 3)
+	s := storeOf("USD", rates)
+
+	tests := []struct {
+		name string
+		i, j int // период - дни [i, j)
+	}{
+		{"single observation", 10, 11},
+		{"two observations", 10, 12},
+		{"odd count", 5, 26},
+		{"whole history", 0, 60},
+		{"tail", 41, 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, err := s.Stats("USD", epoch.AddDate(0, 0, tt.i), epoch.AddDate(0, 0, tt.j), []int{1, 5, 20, 61}, []int{3, 10})
+			if err != nil {
+				t.Fatal(err)
+			}
+			period := rates[tt.i:tt.j]
+			minimum, maximum, mean, med, sd := naiveStats(period)
+			if st.Count != len(period) || st.Min != minimum || st.Max != maximum || st.Median != med {
+				t.Errorf("count, min, max, median = %d, %v, %v, %v; want %d, %v, %v, %v",
+					st.Count, st.Min, st.Max, st.Median, len(period), minimum, maximum, med)
+			}
+			if !near(st.Mean, mean) || !near(st.StdDev, sd) {
+				t.Errorf("mean, std_dev = %v, %v; want %v, %v", st.Mean, st.StdDev, mean, sd)
+			}
+
+			var returns []float64
+			for k := 1; k < len(period); k++ {
+				returns = append(returns, math.Log(period[k]/period[k-1]))
+			}
+			var vol float64
+			if len(returns) > 0 {
+				_, _, _, _, vol = naiveStats(returns)
+				vol *= 100
+			}
+			if !near(st.Volatility, vol) || !near(st.AnnualizedVolatility, vol*math.Sqrt(tradingDays)) {
+				t.Errorf("volatility = %v, %v; want %v", st.Volatility, st.AnnualizedVolatility, vol)
+			}
+
+			// Окна заканчиваются на последнем дне периода и захватывают курсы до него.
+			history := rates[:tt.j]
+			var sma []MovingAverage
+			for _, w := range []int{1, 5, 20, 61} {
+				if w <= len(history) {
+					_, _, mean, _, _ := naiveStats(history[len(history)-w:])
+					sma = append(sma, MovingAverage{Window: w, Value: mean})
+				}
+			}
+			if !sameAverages(st.SMA, sma) {
+				t.Errorf("sma = %v, want %v", st.SMA, sma)
+			}
+			var ema []MovingAverage
+			for _, w := range []int{3, 10} {
+				if w <= len(history) {
+					ema = append(ema, MovingAverage{Window: w, Value: naiveEMA(history, w)})
+				}
+			}
+			if !sameAverages(st.EMA, ema) {
+				t.Errorf("ema = %v, want %v", st.EMA, ema)
+			}
+		})
+	}
+}
+
+func sameAverages(got, want []MovingAverage) bool {
+	return slices.EqualFunc(got, want, func(a, b MovingAverage) bool {
+		return a.Window == b.Window && near(a.Value, b.Value)
+	})
+}
+
+func TestStatsSingleObservationHasNoDeviation(t *testing.T) {
+	// sumSq/n - mean² даёт здесь около 1e-5 вместо нуля.
+	st, err := storeOf("USD", []float64{90.1234}).Stats("USD", epoch, epoch.AddDate(0, 0, 1), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.StdDev != 0 || st.Volatility != 0 {
+		t.Fatalf("std_dev, volatility = %v, %v; want 0", st.StdDev, st.Volatility)
+	}
+}
+
+func TestStatsMinMaxUseCloses(t *testing.T) {
+	s := storeOf("USD", []float64{90, 91})
+	// Внутри дня курс выходил за пределы закрытий.
+	s.seriesFor("USD").add(epoch.Add(time.Hour), 99)
+	s.seriesFor("USD").add(epoch.Add(2*time.Hour), 90)
+
+	st, err := s.Stats("USD", time.Time{}, time.Time{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Min != 90 || st.Max != 91 {
+		t.Fatalf("min, max = %v, %v; want closes 90, 91", st.Min, st.Max)
+	}
+}
+
+func TestStatsNoData(t *testing.T) {
+	s := storeOf("USD", []float64{90})
+	if _, err := s.Stats("EUR", time.Time{}, time.Time{}, nil, nil); !errors.Is(err, ErrNoData) {
+		t.Errorf("unknown code: err = %v, want %v", err, ErrNoData)
+	}
+	if _, err := s.Stats("USD", epoch.AddDate(0, 0, 1), time.Time{}, nil, nil); !errors.Is(err, ErrNoData) {
+		t.Errorf("empty period: err = %v, want %v", err, ErrNoData)
+	}
+}
@@ -0,0 +1,270 @@
+package httputil
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+// Encoder пишет ответ в одном формате. Supports отсеивает значения, которые
+// формат не умеет представить, и тогда при выборе по Accept он не рассматривается.
+type Encoder interface {
+	Supports(v any) bool
+	Encode(w io.Writer, v any) error
+}
+
+// Table - значение, которое можно отдать в text/csv: заголовок и строки.
+type Table interface {
+	Header() []string
+	Rows() [][]string
+}
+
+// ProtoConverter - значение, у которого есть proto-представление для application/x-protobuf.
+type ProtoConverter interface {
+	ToProto() proto.Message
+}
+
+type registeredEncoder struct {
+	mediaType string
+	encoder   Encoder
+}
+
+var (
+	encodersMu sync.RWMutex
+	// Порядок регистрации - порядок предпочтения при равном q; первым идёт JSON.
+	encoders []registeredEncoder
+)
+
+func init() {
+	RegisterEncoder("application/json", jsonEncoder{})
+	RegisterEncoder("application/xml", xmlEncoder{})
+	RegisterEncoder("text/csv", csvEncoder{})
+	RegisterEncoder("application/yaml", yamlEncoder{})
+	RegisterEncoder("application/x-protobuf", protoEncoder{})
+}
+
+// RegisterEncoder добавляет формат ответа или заменяет кодировщик уже известного.
+func RegisterEncoder(mediaType string, enc Encoder) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+
+	for i := range encoders {
+		if encoders[i].mediaType == mediaType {
+			encoders[i].encoder = enc
+			return
+		}
+	}
+	encoders = append(encoders, registeredEncoder{mediaType: mediaType, encoder: enc})
+}
+
+// Write отвечает в формате, который выбран по заголовку Accept среди поддерживающих
+// data. Без Accept ответ - JSON; если ни один формат не подходит - 406.
+func Write(res http.ResponseWriter, req *http.Request, status int, data any) error {
+	res.Header().Add("Vary", "Accept")
+	mediaType, enc, supported := negotiate(req.Header.Get("Accept"), data)
+	if enc == nil {
+		WriteError(res, http.StatusNotAcceptable, "Not acceptable, supported formats: "+strings.Join(supported, ", "))
+		return nil
+	}
+
+	// Ответ собирается в памяти, чтобы ошибка кодирования ещё могла стать ответом 500.
+	var buf bytes.Buffer
+	if err := enc.Encode(&buf, data); err != nil {
+		slog.ErrorContext(req.Context(), "failed to encode response", "content_type", mediaType, "error", err)
+		WriteError(res, http.StatusInternalServerError, "Failed to encode response")
+		return err
+	}
+	res.Header().Set("Content-Type", mediaType)
+	res.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	res.WriteHeader(status)
+	_, err := res.Write(buf.Bytes())
+	return err
+}
+
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// negotiate выбирает формат по RFC 9110: q формата берётся из самого точного
+// подходящего диапазона Accept (q=0 - отказ). Из форматов с наибольшим q выигрывает
+// названный точнее, затем - зарегистрированный раньше.
+func negotiate(accept string, data any) (string, Encoder, []string) {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+
+	var candidates []registeredEncoder
+	supported := make([]string, 0, len(encoders))
+	for _, e := range encoders {
+		if e.encoder.Supports(data) {
+			candidates = append(candidates, e)
+			supported = append(supported, e.mediaType)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", nil, supported
+	}
+	if strings.TrimSpace(accept) == "" {
+		return candidates[0].mediaType, candidates[0].encoder, supported
+	}
+
+	ranges := parseAccept(accept)
+	var best *registeredEncoder
+	bestQ, bestPrecision := 0.0, -1
+	for i, c := range candidates {
+		q, precision := 0.0, -1
+		for _, r := range ranges {
+			if p := specificity(r.mediaType); p > precision && mediaMatches(r.mediaType, c.mediaType) {
+				q, precision = r.q, p
+			}
+		}
+		if q > bestQ || (q == bestQ && q > 0 && precision > bestPrecision) {
+			best, bestQ, bestPrecision = &candidates[i], q, precision
+		}
+	}
+	if best == nil {
+		return "", nil, supported
+	}
+	return best.mediaType, best.encoder, supported
+}
+
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+	}
+	return ranges
+}
+
+// specificity - насколько точно диапазон называет формат: */* - 0, type/* - 1, type/subtype - 2.
+func specificity(pattern string) int {
+	switch {
+	case pattern == "*/*":
+		return 0
+	case strings.HasSuffix(pattern, "/*"):
+		return 1
+	}
+	return 2
+}
+
+func mediaMatches(pattern, mediaType string) bool {
+	if pattern == "*/*" || pattern == mediaType {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(pattern, "/*")
+	return ok && strings.HasPrefix(mediaType, prefix+"/")
+}
+
+type jsonEncoder struct{}
+
+func (jsonEncoder) Supports(any) bool { return true }
+
+func (jsonEncoder) Encode(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// xmlEncoder принимает только значения, которые сами задают корневой элемент.
+type xmlEncoder struct{}
+
+func (xmlEncoder) Supports(v any) bool {
+	_, ok := v.(xml.Marshaler)
+	return ok
+}
+
+func (xmlEncoder) Encode(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type csvEncoder struct{}
+
+func (csvEncoder) Supports(v any) bool {
+	_, ok := v.(Table)
+	return ok
+}
+
+func (csvEncoder) Encode(w io.Writer, v any) error {
+	t := v.(Table)
+	cw := csv.NewWriter(w)
+	cw.Write(t.Header())
+	cw.WriteAll(t.Rows())
+	return cw.Error()
+}
+
+// yamlEncoder повторяет JSON-представление: имена и порядок полей берутся из него,
+// поэтому отдельные теги для YAML не нужны.
+type yamlEncoder struct{}
+
+func (yamlEncoder) Supports(any) bool { return true }
+
+func (yamlEncoder) Encode(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// blockStyle убирает оформление JSON (скобки и кавычки), оставляя YAML решать, где они нужны.
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
+
+type protoEncoder struct{}
+
+func (protoEncoder) Supports(v any) bool {
+	_, ok := v.(ProtoConverter)
+	return ok
+}
+
+func (protoEncoder) Encode(w io.Writer, v any) error {
+	data, err := proto.Marshal(v.(ProtoConverter).ToProto())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,155 @@
+package httputil
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// table поддерживает JSON, XML, CSV и YAML, но не protobuf.
+type table struct {
+	Code string `json:"code"`
+}
+
+func (t table) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(t.Code, xml.StartElement{Name: xml.Name{Local: "table"}})
+}
+
+func (t table) Header() []string { return []string{"code"} }
+func (t table) Rows() [][]string { return [][]string{{t.Code}} }
+
+func TestParseAccept(t *testing.T) {
+	got := parseAccept("text/csv;q=0.5, application/json , garbage/;q=1, */*;q=0, application/yaml;q=x")
+	want := []acceptRange{
+		{mediaType: "text/csv", q: 0.5},
+		{mediaType: "application/json", q: 1},
+		{mediaType: "*/*", q: 0},
+		// Неразборчивый q не отменяет диапазон.
+		{mediaType: "application/yaml", q: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseAccept() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("range %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		data   any
+		want   string // "" - 406
+	}{
+		{name: "no Accept", accept: "", data: table{}, want: "application/json"},
+		{name: "any", accept: "*/*", data: table{}, want: "application/json"},
+		{name: "exact", accept: "text/csv", data: table{}, want: "text/csv"},
+		{name: "highest q", accept: "application/json;q=0.5, application/yaml;q=0.9, text/csv;q=0.7", data: table{}, want: "application/yaml"},
+		{name: "more specific range wins", accept: "*/*;q=0.1, application/*;q=0.2, application/xml;q=0.3", data: table{}, want: "application/xml"},
+		{name: "equal q prefers the named format", accept: "*/*, text/csv", data: table{}, want: "text/csv"},
+		{name: "equal q keeps registration order", accept: "application/*", data: table{}, want: "application/json"},
+		{name: "q=0 refuses a format", accept: "application/json;q=0, */*", data: table{}, want: "application/xml"},
+		{name: "q=0 on a precise range overrides the wildcard", accept: "application/*, application/json;q=0, application/xml;q=0", data: table{}, want: "application/yaml"},
+		{name: "unsupported by the value", accept: "text/csv", data: map[string]string{}, want: ""},
+		{name: "everything refused", accept: "*/*;q=0", data: table{}, want: ""},
+		{name: "unknown format", accept: "image/png", data: table{}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, enc, _ := negotiate(tt.accept, tt.data)
+			if got != tt.want || (enc == nil) != (tt.want == "") {
+				t.Errorf("negotiate(%q) = %q, want %q", tt.accept, got, tt.want)
+			}
+		})
+	}
+}
+
+func write(t *testing.T, accept string, data any) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	res := httptest.NewRecorder()
+	Write(res, req, http.StatusOK, data)
+	return res
+}
+
+func TestWriteNotAcceptable(t *testing.T) {
+	res := write(t, "text/csv", map[string]string{})
+	if res.Code != http.StatusNotAcceptable {
+		t.Fatalf("status = %d, want 406", res.Code)
+	}
+	if body := res.Body.String(); !strings.Contains(body, "application/json, application/yaml") || strings.Contains(body, "text/csv") {
+		t.Errorf("body = %s, want the formats supported by the value", body)
+	}
+	if res.Header().Get("Vary") != "Accept" {
+		t.Errorf("Vary = %q, want Accept", res.Header().Get("Vary"))
+	}
+}
+
+func TestWriteContentLength(t *testing.T) {
+	res := write(t, "text/csv", table{Code: "USD"})
+	if res.Code != http.StatusOK || res.Header().Get("Content-Type") != "text/csv" {
+		t.Fatalf("status %d, Content-Type %q; want 200, text/csv", res.Code, res.Header().Get("Content-Type"))
+	}
+	if got := res.Header().Get("Content-Length"); got != strconv.Itoa(res.Body.Len()) || res.Body.String() != "code\nUSD\n" {
+		t.Errorf("Content-Length %s, body %q; want %d, %q", got, res.Body.String(), res.Body.Len(), "code\nUSD\n")
+	}
+}
+
+// failingEncoder успевает записать часть ответа перед ошибкой.
+type failingEncoder struct{}
+
+type failing struct{}
+
+func (failingEncoder) Supports(v any) bool { _, ok := v.(failing); return ok }
+
+func (failingEncoder) Encode(w io.Writer, v any) error {
+	io.WriteString(w, "partial")
+	return errors.New("encode failed")
+}
+
+func TestWriteEncodeError(t *testing.T) {
+	RegisterEncoder("application/x-failing", failingEncoder{})
+
+	res := write(t, "application/x-failing", failing{})
+	if res.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", res.Code)
+	}
+	if ct := res.Header().Get("Content-Type"); ct != "application/json" || strings.Contains(res.Body.String(), "partial") {
+		t.Errorf("Content-Type %q, body %q; want a JSON error without the partial body", ct, res.Body.String())
+	}
+}
+
+func TestYAMLQuotesAmbiguousStrings(t *testing.T) {
+	in := map[string]any{"code": "010", "flag": "true", "exp": "1e3", "null": "null", "rate": 90.5, "name": "US dollar"}
+	res := write(t, "application/yaml", in)
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d", res.Code)
+	}
+
+	// Строки, похожие на числа и литералы, должны остаться строками.
+	var out map[string]any
+	if err := yaml.Unmarshal(res.Body.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal %q: %v", res.Body.String(), err)
+	}
+	for k, v := range in {
+		if out[k] != v {
+			t.Errorf("%s = %#v, want %#v; body:\n%s", k, out[k], v, res.Body.String())
+		}
+	}
+	if strings.Contains(res.Body.String(), "{") || strings.Contains(res.Body.String(), `"US dollar"`) {
+		t.Errorf("body keeps JSON styling:\n%s", res.Body.String())
+	}
+}
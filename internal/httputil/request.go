@@ -0,0 +1,125 @@
+package httputil
+
+import (
+	"currency-converter/internal/validation"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// MaxBodySize ограничивает тело JSON-запроса. Архивы импорта читаются отдельно со своим лимитом.
+const MaxBodySize = 1 << 20
+
+// RequestError - тело запроса, которое не удалось разобрать; Status - код ответа.
+type RequestError struct {
+	Status  int
+	Message string
+	Fields  []validation.FieldError
+}
+
+func (e *RequestError) Error() string {
+	if len(e.Fields) == 0 {
+		return e.Message
+	}
+	return (&validation.Error{Fields: e.Fields}).Error()
+}
+
+// ReadJson строго разбирает тело запроса в v: Content-Type application/json
+// (или +json), не больше MaxBodySize, ровно одно значение JSON и только
+// известные поля. Ошибка всегда *RequestError, её отдаёт WriteRequestError.
+func ReadJson(res http.ResponseWriter, req *http.Request, v any) error {
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+		return &RequestError{Status: http.StatusUnsupportedMediaType, Message: "Content-Type must be application/json"}
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(res, req.Body, MaxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return decodeError(err)
+	}
+	var extra json.RawMessage
+	if err := decoder.Decode(&extra); err != io.EOF {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return decodeError(err)
+		}
+		return &RequestError{Status: http.StatusBadRequest, Message: "Request body must contain a single JSON value"}
+	}
+	return nil
+}
+
+func decodeError(err error) *RequestError {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		tooLarge  *http.MaxBytesError
+	)
+	switch {
+	case errors.As(err, &tooLarge):
+		return &RequestError{
+			Status:  http.StatusRequestEntityTooLarge,
+			Message: fmt.Sprintf("Request body is larger than %d bytes", tooLarge.Limit),
+		}
+	case errors.Is(err, io.EOF):
+		return &RequestError{Status: http.StatusBadRequest, Message: "Request body is empty"}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return &RequestError{Status: http.StatusBadRequest, Message: "Malformed JSON: unexpected end of body"}
+	case errors.As(err, &syntaxErr):
+		return &RequestError{Status: http.StatusBadRequest, Message: fmt.Sprintf("Malformed JSON at offset %d: %v", syntaxErr.Offset, err)}
+	case errors.As(err, &typeErr):
+		return invalidFields(validation.FieldError{
+			Field:   typeErr.Field,
+			Message: fmt.Sprintf("must be %s, got %s", jsonType(typeErr.Type), typeErr.Value),
+		})
+	}
+	// У ошибки неизвестного поля нет своего типа, имя поля есть только в тексте.
+	if name, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		if unquoted, uerr := strconv.Unquote(name); uerr == nil {
+			name = unquoted
+		}
+		return invalidFields(validation.FieldError{Field: name, Message: "unknown field"})
+	}
+	return &RequestError{Status: http.StatusBadRequest, Message: "Invalid JSON: " + err.Error()}
+}
+
+func invalidFields(fields ...validation.FieldError) *RequestError {
+	return &RequestError{Status: http.StatusBadRequest, Message: "Invalid request body", Fields: fields}
+}
+
+// jsonType называет тип Go так, как его видит клиент в JSON.
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a non-negative integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Pointer:
+		return jsonType(t.Elem())
+	}
+	return "an object"
+}
+
+// WriteRequestError отвечает на ошибку ReadJson; прочие ошибки считаются неверным запросом.
+func WriteRequestError(res http.ResponseWriter, err error) {
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) {
+		WriteError(res, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	WriteFieldErrors(res, reqErr.Status, reqErr.Message, reqErr.Fields)
+}
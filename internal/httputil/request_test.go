@@ -0,0 +1,156 @@
+package httputil
+
+import (
+	"currency-converter/internal/validation"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type request struct {
+	Code   string   `json:"code"`
+	Rate   float64  `json:"rate"`
+	Count  int      `json:"count"`
+	Tags   []string `json:"tags"`
+	Nested struct {
+		Active bool `json:"active"`
+	} `json:"nested"`
+}
+
+func TestReadJson(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int // 0 - тело разобрано
+		wantMessage string
+		wantFields  []validation.FieldError
+	}{
+		{name: "valid", contentType: "application/json", body: `{"code":"USD","rate":90}`},
+		{name: "json with charset", contentType: "application/json; charset=utf-8", body: `{"code":"USD"}`},
+		{name: "structured json suffix", contentType: "application/merge-patch+json", body: `{"code":"USD"}`},
+		{name: "surrounding whitespace", contentType: "application/json", body: " \n{\"code\":\"USD\"}\n "},
+		{name: "missing Content-Type", contentType: "", body: `{}`, wantStatus: http.StatusUnsupportedMediaType},
+		{name: "wrong Content-Type", contentType: "text/plain", body: `{}`, wantStatus: http.StatusUnsupportedMediaType},
+		{
+			name: "body above MaxBodySize", contentType: "application/json",
+			body:       `{"code":"` + strings.Repeat("x", MaxBodySize) + `"}`,
+			wantStatus: http.StatusRequestEntityTooLarge, wantMessage: "Request body is larger than 1048576 bytes",
+		},
+		{
+			// Само значение помещается в лимит, лимит превышают данные после него.
+			name: "trailing data above MaxBodySize", contentType: "application/json",
+			body:       `{"code":"USD"}` + strings.Repeat(" ", MaxBodySize),
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{name: "empty body", contentType: "application/json", body: ``, wantStatus: http.StatusBadRequest, wantMessage: "Request body is empty"},
+		{
+			name: "trailing value", contentType: "application/json", body: `{"code":"USD"} {"code":"EUR"}`,
+			wantStatus: http.StatusBadRequest, wantMessage: "Request body must contain a single JSON value",
+		},
+		{
+			name: "trailing garbage", contentType: "application/json", body: `{"code":"USD"}x`,
+			wantStatus: http.StatusBadRequest, wantMessage: "Request body must contain a single JSON value",
+		},
+		{
+			name: "truncated", contentType: "application/json", body: `{"code":"USD"`,
+			wantStatus: http.StatusBadRequest, wantMessage: "Malformed JSON: unexpected end of body",
+		},
+		{
+			name: "syntax error", contentType: "application/json", body: `{"code" "USD"}`,
+			wantStatus: http.StatusBadRequest, wantMessage: "Malformed JSON at offset 9: invalid character '\"' after object key",
+		},
+		{
+			name: "unknown field", contentType: "application/json", body: `{"code":"USD","ratee":90}`,
+			wantStatus: http.StatusBadRequest, wantMessage: "Invalid request body",
+			wantFields: []validation.FieldError{{Field: "ratee", Message: "unknown field"}},
+		},
+		{
+			name: "unknown field with quotes in the name", contentType: "application/json", body: `{"a\"b":1}`,
+			wantStatus: http.StatusBadRequest,
+			wantFields: []validation.FieldError{{Field: `a"b`, Message: "unknown field"}},
+		},
+		{
+			name: "string instead of number", contentType: "application/json", body: `{"rate":"90"}`,
+			wantStatus: http.StatusBadRequest,
+			wantFields: []validation.FieldError{{Field: "rate", Message: "must be a number, got string"}},
+		},
+		{
+			name: "fraction instead of integer", contentType: "application/json", body: `{"count":1.5}`,
+			wantStatus: http.StatusBadRequest,
+			wantFields: []validation.FieldError{{Field: "count", Message: "must be an integer, got number 1.5"}},
+		},
+		{
+			name: "object instead of array", contentType: "application/json", body: `{"tags":{}}`,
+			wantStatus: http.StatusBadRequest,
+			wantFields: []validation.FieldError{{Field: "tags", Message: "must be an array, got object"}},
+		},
+		{
+			name: "nested field", contentType: "application/json", body: `{"nested":{"active":"yes"}}`,
+			wantStatus: http.StatusBadRequest,
+			wantFields: []validation.FieldError{{Field: "nested.active", Message: "must be a boolean, got string"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			res := httptest.NewRecorder()
+
+			var v request
+			err := ReadJson(res, req, &v)
+			if tt.wantStatus == 0 {
+				if err != nil || v.Code != "USD" {
+					t.Fatalf("ReadJson() = %v, code %q; want USD", err, v.Code)
+				}
+				return
+			}
+
+			var reqErr *RequestError
+			if !errors.As(err, &reqErr) {
+				t.Fatalf("ReadJson() error = %v, want *RequestError", err)
+			}
+			if reqErr.Status != tt.wantStatus {
+				t.Errorf("status = %d, want %d (%v)", reqErr.Status, tt.wantStatus, err)
+			}
+			if tt.wantMessage != "" && reqErr.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", reqErr.Message, tt.wantMessage)
+			}
+			if !slices.Equal(reqErr.Fields, tt.wantFields) {
+				t.Errorf("fields = %+v, want %+v", reqErr.Fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+// Имя неизвестного поля decodeError достаёт из текста ошибки encoding/json:
+// тест закрепляет этот формат, чтобы смена текста не прошла незамеченной.
+func TestUnknownFieldErrorFormat(t *testing.T) {
+	decoder := json.NewDecoder(strings.NewReader(`{"ratee":1}`))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&request{})
+	if err == nil || err.Error() != `json: unknown field "ratee"` {
+		t.Fatalf("encoding/json error = %v, want json: unknown field \"ratee\"", err)
+	}
+}
+
+func TestWriteRequestError(t *testing.T) {
+	res := httptest.NewRecorder()
+	WriteRequestError(res, invalidFields(validation.FieldError{Field: "rate", Message: "must be a number, got string"}))
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", res.Code)
+	}
+	var body ErrorResponse
+	if err := json.Unmarshal(res.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Error != "Invalid request body" || len(body.Fields) != 1 || body.Fields[0].Field != "rate" {
+		t.Errorf("body = %+v, want the field error", body)
+	}
+}
@@ -1,8 +1,9 @@
 package httputil
 
 import (
+	"currency-converter/internal/validation"
 	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
 )
 
@@ -12,7 +13,7 @@ func WriteJson(res http.ResponseWriter, status int, data any) error {
 
 	encoder := json.NewEncoder(res)
 	if err := encoder.Encode(data); err != nil {
-		log.Printf("coding error  JSON: %v", err)
+		slog.Error("failed to encode JSON response", "error", err)
 		return err
 	}
 	return nil
@@ -22,6 +23,13 @@ func WriteError(res http.ResponseWriter, status int, msg string) {
 	WriteJson(res, status, map[string]string{"error:": msg})
 }
 
-func ReadJson(req http.Request, v any) error {
-	return json.NewDecoder(req.Body).Decode(v)
-}
\ No newline at end of file
+// ErrorResponse - ответ с ошибкой; Fields перечисляет все ошибки по полям запроса.
+type ErrorResponse struct {
+	Error  string                  `json:"error:"`
+	Fields []validation.FieldError `json:"fields,omitempty"`
+}
+
+// WriteFieldErrors - WriteError со списком ошибок по полям запроса.
+func WriteFieldErrors(res http.ResponseWriter, status int, msg string, fields []validation.FieldError) {
+	WriteJson(res, status, ErrorResponse{Error: msg, Fields: fields})
+}
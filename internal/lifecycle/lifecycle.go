@@ -0,0 +1,104 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Component - часть приложения с явным запуском и остановкой.
+// Start должен вернуть управление, как только компонент готов к работе.
+type Component interface {
+	Name() string
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+// Failer реализуют компоненты, которые могут аварийно завершиться уже после старта
+// (например, сервер, у которого упал Serve).
+type Failer interface {
+	Failed() <-chan error
+}
+
+// Manager запускает компоненты в порядке добавления и останавливает в обратном,
+// укладываясь в общий дедлайн на остановку.
+type Manager struct {
+	components      []Component
+	shutdownTimeout time.Duration
+}
+
+func New(shutdownTimeout time.Duration) *Manager {
+	return &Manager{shutdownTimeout: shutdownTimeout}
+}
+
+func (m *Manager) Add(components ...Component) {
+	m.components = append(m.components, components...)
+}
+
+// Run запускает все компоненты и блокируется до отмены ctx или падения одного из них,
+// после чего останавливает уже запущенные компоненты.
+func (m *Manager) Run(ctx context.Context) error {
+	started := make([]Component, 0, len(m.components))
+
+	var runErr error
+	for _, c := range m.components {
+		if err := c.Start(ctx); err != nil {
+			runErr = fmt.Errorf("failed to start %s: %w", c.Name(), err)
+			break
+		}
+		slog.InfoContext(ctx, "component started", "component", c.Name())
+		started = append(started, c)
+	}
+
+	if runErr == nil {
+		runErr = m.wait(ctx, started)
+	}
+
+	return errors.Join(runErr, m.stop(started))
+}
+
+func (m *Manager) wait(ctx context.Context, started []Component) error {
+	failed := make(chan error, len(started))
+	done := make(chan struct{})
+	defer close(done)
+
+	for _, c := range started {
+		f, ok := c.(Failer)
+		if !ok {
+			continue
+		}
+		go func(name string, ch <-chan error) {
+			select {
+			case err := <-ch:
+				failed <- fmt.Errorf("%s failed: %w", name, err)
+			case <-done:
+			}
+		}(c.Name(), f.Failed())
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-failed:
+		slog.ErrorContext(ctx, "component failed, shutting down", "error", err)
+		return err
+	}
+}
+
+func (m *Manager) stop(started []Component) error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		c := started[i]
+		if err := c.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", c.Name(), err))
+			continue
+		}
+		slog.InfoContext(ctx, "component stopped", "component", c.Name())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,126 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// AlertCondition - условие срабатывания правила для курса пары Base/Quote.
+type AlertCondition string
+
+const (
+	// AlertAbove - курс поднялся выше порога: при прошлой проверке был не выше, теперь выше.
+	AlertAbove AlertCondition = "above"
+	// AlertBelow - курс опустился ниже порога: при прошлой проверке был не ниже, теперь ниже.
+	AlertBelow AlertCondition = "below"
+	// AlertChangePct - курс изменился за день больше чем на Threshold процентов в любую сторону.
+	AlertChangePct AlertCondition = "change_pct"
+)
+
+// AlertRule - правило уведомления о курсе валютной пары (например, USD/RUB выше 95).
+type AlertRule struct {
+	ID              string         `json:"id"`
+	Base            string         `json:"base"`
+	Quote           string         `json:"quote"`
+	Condition       AlertCondition `json:"condition"`
+	Threshold       float64        `json:"threshold"`
+	CooldownSeconds int64          `json:"cooldown_seconds"`
+	WebhookURL      string         `json:"webhook_url"`
+	CreatedAt       time.Time      `json:"created_at"`
+	LastFiredAt     *time.Time     `json:"last_fired_at,omitempty"`
+	LastRate        float64        `json:"last_rate,omitempty"` // курс пары при последней проверке
+}
+
+type AlertRequest struct {
+	Base            string         `json:"base"`
+	Quote           string         `json:"quote"`
+	Condition       AlertCondition `json:"condition"`
+	Threshold       float64        `json:"threshold"`
+	CooldownSeconds int64          `json:"cooldown_seconds"`
+	WebhookURL      string         `json:"webhook_url"`
+}
+
+// AlertEvent - срабатывание правила, отправляется на WebhookURL.
+type AlertEvent struct {
+	Rule          *AlertRule `json:"rule"`
+	Pair          string     `json:"pair"`
+	Rate          float64    `json:"rate"`
+	PreviousRate  float64    `json:"previous_rate,omitempty"`
+	ChangePercent float64    `json:"change_percent"`
+	Message       string     `json:"message"`
+	FiredAt       time.Time  `json:"fired_at"`
+}
+
+func (r *AlertRule) Pair() string {
+	return r.Base + "/" + r.Quote
+}
+
+func (r *AlertRule) Cooldown() time.Duration {
+	return time.Duration(r.CooldownSeconds) * time.Second
+}
+
+// CoolingDown сообщает, что правило уже сработало недавно и повторно уведомлять рано.
+func (r *AlertRule) CoolingDown(now time.Time) bool {
+	return r.LastFiredAt != nil && now.Sub(*r.LastFiredAt) < r.Cooldown()
+}
+
+// Evaluate проверяет правило для курса пары rate. Правило срабатывает, только когда
+// условие выполняется сейчас, но не выполнялось для LastRate, - один раз на пересечение
+// порога, а не на каждой синхронизации, пока курс за ним. Пока LastRate неизвестен,
+// правило не срабатывает. previous - вчерашний курс для change_pct; нулевой, если он
+// неизвестен или несопоставим с rate (например, курс переопределён вручную).
+func (r *AlertRule) Evaluate(rate, previous float64, now time.Time) (*AlertEvent, bool) {
+	if r.LastRate <= 0 || r.holds(r.LastRate, previous) || !r.holds(rate, previous) {
+		return nil, false
+	}
+
+	change := changePercent(rate, previous)
+	var msg string
+	switch r.Condition {
+	case AlertAbove:
+		msg = fmt.Sprintf("%s is %.4f, above %.4f", r.Pair(), rate, r.Threshold)
+	case AlertBelow:
+		msg = fmt.Sprintf("%s is %.4f, below %.4f", r.Pair(), rate, r.Threshold)
+	case AlertChangePct:
+		msg = fmt.Sprintf("%s moved %+.2f%% in a day to %.4f", r.Pair(), change, rate)
+	}
+
+	return &AlertEvent{
+		Rule:          r,
+		Pair:          r.Pair(),
+		Rate:          rate,
+		PreviousRate:  previous,
+		ChangePercent: change,
+		Message:       msg,
+		FiredAt:       now,
+	}, true
+}
+
+// holds сообщает, выполняется ли условие правила для курса rate.
+func (r *AlertRule) holds(rate, previous float64) bool {
+	switch r.Condition {
+	case AlertAbove:
+		return rate > r.Threshold
+	case AlertBelow:
+		return rate < r.Threshold
+	case AlertChangePct:
+		return previous > 0 && math.Abs(changePercent(rate, previous)) > r.Threshold
+	}
+	return false
+}
+
+func changePercent(rate, previous float64) float64 {
+	if previous <= 0 {
+		return 0
+	}
+	return (rate - previous) / previous * 100
+}
+
+func ValidAlertCondition(c AlertCondition) bool {
+	switch c {
+	case AlertAbove, AlertBelow, AlertChangePct:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAlertRuleEvaluate(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition AlertCondition
+		threshold float64
+		lastRate  float64
+		rate      float64
+		previous  float64
+		fired     bool
+	}{
+		{name: "above: crossed", condition: AlertAbove, threshold: 95, lastRate: 94, rate: 96, fired: true},
+		{name: "above: from the threshold", condition: AlertAbove, threshold: 95, lastRate: 95, rate: 96, fired: true},
+		{name: "above: already above", condition: AlertAbove, threshold: 95, lastRate: 96, rate: 97},
+		{name: "above: same rate again", condition: AlertAbove, threshold: 95, lastRate: 96, rate: 96, previous: 94},
+		{name: "above: still below", condition: AlertAbove, threshold: 95, lastRate: 93, rate: 94},
+		{name: "above: not checked yet", condition: AlertAbove, threshold: 95, rate: 96, previous: 94},
+		{name: "below: crossed", condition: AlertBelow, threshold: 90, lastRate: 91, rate: 89, fired: true},
+		{name: "below: from the threshold", condition: AlertBelow, threshold: 90, lastRate: 90, rate: 89, fired: true},
+		{name: "below: already below", condition: AlertBelow, threshold: 90, lastRate: 89, rate: 88},
+		{name: "change_pct: moved", condition: AlertChangePct, threshold: 2, lastRate: 100, rate: 103, previous: 100, fired: true},
+		{name: "change_pct: same day again", condition: AlertChangePct, threshold: 2, lastRate: 103, rate: 103, previous: 100},
+		{name: "change_pct: small move", condition: AlertChangePct, threshold: 2, lastRate: 100, rate: 101, previous: 100},
+		{name: "change_pct: unknown previous", condition: AlertChangePct, threshold: 2, lastRate: 100, rate: 110},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &AlertRule{Base: "USD", Quote: "RUB", Condition: tt.condition, Threshold: tt.threshold, LastRate: tt.lastRate}
+			event, fired := rule.Evaluate(tt.rate, tt.previous, time.Now())
+			if fired != tt.fired {
+				t.Fatalf("Evaluate(%v, %v) with last rate %v fired = %v, want %v", tt.rate, tt.previous, tt.lastRate, fired, tt.fired)
+			}
+			if fired && (event.Rate != tt.rate || event.PreviousRate != tt.previous) {
+				t.Errorf("event = %+v, want rate %v and previous %v", event, tt.rate, tt.previous)
+			}
+		})
+	}
+}
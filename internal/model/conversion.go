@@ -1,10 +1,14 @@
 package model
 
+import "time"
+
 type Conversion struct {
 	Amount float64   `json:"amount"`
 	From   *Currency `json:"from"`
 	To     *Currency `json:"to"`
 	Result float64   `json:"result"`
+	// Время конвертации; у записей, сделанных до его появления, не заполнено.
+	CreatedAt time.Time `json:"created_at,omitzero"`
 }
 
 type ConversionRequest struct {
@@ -16,9 +20,10 @@ type ConversionRequest struct {
 // Конструктор конвертирования
 func NewConversion(amount float64, from *Currency, to *Currency, result float64) *Conversion {
 	return &Conversion{
-		Amount: amount,
-		From:   from,
-		To:     to,
-		Result: result,
+		Amount:    amount,
+		From:      from,
+		To:        to,
+		Result:    result,
+		CreatedAt: time.Now().UTC(),
 	}
 }
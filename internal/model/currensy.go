@@ -5,6 +5,12 @@ type Currency struct {
 	Rate   float64 `json:"rate"`
 	Name   string  `json:"name"`
 	Symbol string  `json:"symbol"`
+
+	// Изменение за день относительно предыдущего курса ЦБ РФ; заполняется сервисом.
+	// Нулевое изменение выводится всегда, а без previous_rate предыдущий курс неизвестен.
+	PreviousRate  float64 `json:"previous_rate,omitempty"`
+	Change        float64 `json:"change"`
+	ChangePercent float64 `json:"change_percent"`
 }
 
 // Конструктор новой валюты
@@ -17,4 +23,19 @@ func NewCurrency(code string, rate float64, name string, symbol string) *Currenc
 	}
 }
 
+// SetPrevious запоминает предыдущий курс и пересчитывает изменение к текущему.
+// Нулевой previous означает, что предыдущий курс неизвестен.
+func (c *Currency) SetPrevious(previous float64) {
+	c.PreviousRate = previous
+	c.Change, c.ChangePercent = 0, 0
+	if previous > 0 {
+		c.Change = c.Rate - previous
+		c.ChangePercent = c.Change / previous * 100
+	}
+}
 
+// Movers - валюты с наибольшим ростом и падением курса к рублю за день.
+type Movers struct {
+	Gainers []*Currency `json:"gainers"`
+	Losers  []*Currency `json:"losers"`
+}
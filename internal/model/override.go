@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// RateOverride - курс, выставленный вручную поверх курса поставщика (ЦБ РФ).
+type RateOverride struct {
+	Code         string     `json:"code"`
+	Rate         float64    `json:"rate"`
+	Reason       string     `json:"reason"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	ProviderRate float64    `json:"provider_rate,omitempty"` // последний курс поставщика, восстанавливается после истечения
+}
+
+type OverrideRequest struct {
+	Rate      float64    `json:"rate"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Expired сообщает, истёк ли срок действия переопределения на момент now.
+func (o *RateOverride) Expired(now time.Time) bool {
+	return o.ExpiresAt != nil && !now.Before(*o.ExpiresAt)
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// PairStats - конвертации по одной паре валют за период.
+type PairStats struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Count int    `json:"count"`
+	// Сумма Amount в валюте From, сумма Result в валюте To и оборот в рублях по курсу на момент конвертации.
+	Volume        float64 `json:"volume"`
+	Converted     float64 `json:"converted"`
+	VolumeRUB     float64 `json:"volume_rub"`
+	AverageAmount float64 `json:"average_amount"`
+}
+
+// DailyConversions - конвертации за сутки (UTC).
+type DailyConversions struct {
+	Date      time.Time `json:"date"`
+	Count     int       `json:"count"`
+	VolumeRUB float64   `json:"volume_rub"`
+}
+
+// ConversionReport - сводка по истории конвертаций за [From, To).
+type ConversionReport struct {
+	From             time.Time `json:"from,omitzero"`
+	To               time.Time `json:"to,omitzero"`
+	Count            int       `json:"count"`
+	VolumeRUB        float64   `json:"volume_rub"`
+	AverageAmountRUB float64   `json:"average_amount_rub"`
+	// Конвертации без даты (записанные до её появления); учитываются только в отчёте без периода.
+	Undated  int                `json:"undated,omitempty"`
+	Pairs    []PairStats        `json:"pairs"`     // по алфавиту
+	TopPairs []PairStats        `json:"top_pairs"` // по числу конвертаций
+	Daily    []DailyConversions `json:"daily"`     // каждый день от первой до последней конвертации
+}
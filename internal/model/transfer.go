@@ -0,0 +1,46 @@
+package model
+
+// ConflictPolicy - что делать с валютой, которая уже есть с другими данными.
+type ConflictPolicy string
+
+const (
+	ConflictFail      ConflictPolicy = "fail" // не импортировать ничего
+	ConflictSkip      ConflictPolicy = "skip" // оставить существующую
+	ConflictOverwrite ConflictPolicy = "overwrite"
+)
+
+type ImportOptions struct {
+	DryRun     bool
+	OnConflict ConflictPolicy
+}
+
+// ImportCounts - итог по разделу архива. Unchanged - записи, которые уже есть
+// с теми же данными; Skipped - конфликты, оставленные как есть.
+type ImportCounts struct {
+	Total     int `json:"total"`
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Skipped   int `json:"skipped"`
+}
+
+// ImportIssue - ошибка или конфликт в записи архива; Record - её номер в разделе с 1.
+type ImportIssue struct {
+	Section string `json:"section"`
+	Record  int    `json:"record,omitempty"`
+	Key     string `json:"key,omitempty"`
+	Message string `json:"message"`
+}
+
+type ImportReport struct {
+	Version     int            `json:"version"`
+	Format      string         `json:"format"`
+	DryRun      bool           `json:"dry_run"`
+	OnConflict  ConflictPolicy `json:"on_conflict"`
+	Applied     bool           `json:"applied"`
+	Currencies  ImportCounts   `json:"currencies"`
+	RateHistory ImportCounts   `json:"rate_history"`
+	Conversions ImportCounts   `json:"conversions"`
+	Conflicts   []ImportIssue  `json:"conflicts"`
+	Errors      []ImportIssue  `json:"errors"`
+}
@@ -0,0 +1,115 @@
+package model
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// WebhookEvent - тип события, на которое можно подписаться.
+type WebhookEvent string
+
+const (
+	// EventRatesUpdated - синхронизация с ЦБ РФ изменила хотя бы один курс.
+	EventRatesUpdated WebhookEvent = "rates.updated"
+	// EventCurrencyCreated - валюта добавлена через API.
+	EventCurrencyCreated WebhookEvent = "currency.created"
+	// EventCurrencyUpdated - валюта изменена через API (в том числе upsert).
+	EventCurrencyUpdated WebhookEvent = "currency.updated"
+	// EventCurrencyDeleted - валюта удалена через API.
+	EventCurrencyDeleted WebhookEvent = "currency.deleted"
+	// EventConversionCreated - выполнена конвертация.
+	EventConversionCreated WebhookEvent = "conversion.created"
+)
+
+var WebhookEvents = []WebhookEvent{
+	EventRatesUpdated,
+	EventCurrencyCreated,
+	EventCurrencyUpdated,
+	EventCurrencyDeleted,
+	EventConversionCreated,
+}
+
+func ValidWebhookEvent(e WebhookEvent) bool {
+	return slices.Contains(WebhookEvents, e)
+}
+
+// WebhookSubscription - адрес, на который отправляются события. Пустой Events
+// означает подписку на все события. Secret подписывает тело запроса (HMAC-SHA256)
+// и отдаётся клиенту только при создании подписки.
+type WebhookSubscription struct {
+	ID        string         `json:"id"`
+	URL       string         `json:"url"`
+	Events    []WebhookEvent `json:"events"`
+	Secret    string         `json:"secret,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type WebhookSubscriptionRequest struct {
+	URL    string         `json:"url"`
+	Events []WebhookEvent `json:"events"`
+	// Если секрет не задан, он генерируется.
+	Secret string `json:"secret"`
+}
+
+// Wants сообщает, подписан ли получатель на событие.
+func (s *WebhookSubscription) Wants(e WebhookEvent) bool {
+	return len(s.Events) == 0 || slices.Contains(s.Events, e)
+}
+
+// Redacted возвращает копию подписки без секрета.
+func (s *WebhookSubscription) Redacted() *WebhookSubscription {
+	c := *s
+	c.Secret = ""
+	return &c
+}
+
+// WebhookPayload - тело запроса, которое получает подписчик.
+type WebhookPayload struct {
+	ID        string       `json:"id"`
+	Event     WebhookEvent `json:"event"`
+	CreatedAt time.Time    `json:"created_at"`
+	Data      any          `json:"data"`
+}
+
+// DeliveryStatus - состояние доставки события подписчику.
+type DeliveryStatus string
+
+const (
+	// DeliveryPending - доставка ещё не удалась, но попытки не исчерпаны.
+	DeliveryPending DeliveryStatus = "pending"
+	// DeliveryDelivered - подписчик ответил 2xx.
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryDead - попытки исчерпаны, доставка попала в список недоставленных.
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+// WebhookDelivery - одна доставка события одному подписчику вместе с историей попыток.
+type WebhookDelivery struct {
+	ID             string          `json:"id"`
+	EventID        string          `json:"event_id"`
+	SubscriptionID string          `json:"subscription_id"`
+	URL            string          `json:"url"`
+	Event          WebhookEvent    `json:"event"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseCode   int             `json:"response_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at,omitempty"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+}
+
+// RateChange - изменение курса валюты к рублю при синхронизации.
+type RateChange struct {
+	Code    string   `json:"code"`
+	OldRate *float64 `json:"old_rate,omitempty"` // nil для новой валюты
+	NewRate float64  `json:"new_rate"`
+}
+
+// RatesUpdated - данные события rates.updated.
+type RatesUpdated struct {
+	SyncedAt time.Time    `json:"synced_at"`
+	Changes  []RateChange `json:"changes"`
+}
@@ -0,0 +1,40 @@
+package notify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Заголовки подписанного webhook. Получатель проверяет подпись, вычисляя
+// HMAC-SHA256 от "<timestamp>.<тело запроса>" своим секретом, и отбрасывает
+// запросы со старым timestamp, чтобы их нельзя было повторить.
+const (
+	HeaderID        = "X-Webhook-ID"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Sign возвращает подпись в виде "sha256=<hex>".
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignedHeader собирает заголовки доставки с подписью на момент now.
+func SignedHeader(id, event, secret string, body []byte, now time.Time) http.Header {
+	ts := now.Unix()
+	h := make(http.Header)
+	h.Set(HeaderID, id)
+	h.Set(HeaderEvent, event)
+	h.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	h.Set(HeaderSignature, Sign(secret, ts, body))
+	return h
+}
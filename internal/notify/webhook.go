@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"currency-converter/internal/requestid"
+	"currency-converter/internal/tracing"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Webhook отправляет JSON POST-запросом на адрес получателя.
+type Webhook struct {
+	client *http.Client
+}
+
+func NewWebhook(timeout time.Duration) *Webhook {
+	return &Webhook{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: tracing.Transport(http.DefaultTransport),
+		},
+	}
+}
+
+// Send считает доставку успешной при любом ответе 2xx.
+func (w *Webhook) Send(ctx context.Context, url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+	_, err = w.Post(ctx, url, body, nil)
+	return err
+}
+
+// Post отправляет готовое тело с дополнительными заголовками и возвращает код ответа
+// (0, если ответа не было). Ответ вне 2xx считается ошибкой.
+func (w *Webhook) Post(ctx context.Context, url string, body []byte, header http.Header) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if id := requestid.FromContext(ctx); id != "" {
+		req.Header.Set(requestid.Header, id)
+	}
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to deliver webhook: %w", err)
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return res.StatusCode, fmt.Errorf("webhook receiver responded with status %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"context"
+	"currency-converter/internal/auth"
+	"currency-converter/internal/metrics"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor ограничивает частоту вызовов метода; должен стоять после
+// перехватчика аутентификации. Время до повтора передаётся в заголовке retry-after.
+// Перебор учётных данных до аутентификации сдерживает AuthUnaryServerInterceptor.
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := l.checkGRPC(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (l *Limiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := l.checkGRPC(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// AuthUnaryServerInterceptor ограничивает по адресу клиента неудачные попытки
+// аутентификации (ответы Unauthenticated); должен стоять перед перехватчиком аутентификации.
+func (l *Limiter) AuthUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !l.authEnabled() {
+			return handler(ctx, req)
+		}
+		host := peerHost(ctx)
+		if err := l.checkAuthGRPC(ctx, host, info.FullMethod); err != nil {
+			return nil, err
+		}
+		resp, err := handler(ctx, req)
+		if status.Code(err) == codes.Unauthenticated {
+			l.authFailed(host)
+		}
+		return resp, err
+	}
+}
+
+func (l *Limiter) AuthStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !l.authEnabled() {
+			return handler(srv, ss)
+		}
+		host := peerHost(ss.Context())
+		if err := l.checkAuthGRPC(ss.Context(), host, info.FullMethod); err != nil {
+			return err
+		}
+		err := handler(srv, ss)
+		if status.Code(err) == codes.Unauthenticated {
+			l.authFailed(host)
+		}
+		return err
+	}
+}
+
+func (l *Limiter) checkAuthGRPC(ctx context.Context, host, method string) error {
+	blocked, retryAfter := l.authBlocked(host)
+	if !blocked {
+		return nil
+	}
+	metrics.RateLimited.WithLabelValues("grpc", method).Inc()
+	seconds := retryAfterSeconds(retryAfter)
+	grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(seconds)))
+	return status.Errorf(codes.ResourceExhausted, "too many failed authentication attempts, retry in %ds", seconds)
+}
+
+func (l *Limiter) checkGRPC(ctx context.Context, method string) error {
+	if !l.Enabled() {
+		return nil
+	}
+	ok, retryAfter := l.Allow(method, grpcClientKey(ctx))
+	if ok {
+		return nil
+	}
+	metrics.RateLimited.WithLabelValues("grpc", method).Inc()
+	seconds := retryAfterSeconds(retryAfter)
+	grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(seconds)))
+	return status.Errorf(codes.ResourceExhausted, "rate limit exceeded, retry in %ds", seconds)
+}
+
+func grpcClientKey(ctx context.Context) string {
+	if p, ok := auth.FromContext(ctx); ok {
+		return "principal:" + p.Subject
+	}
+	if host := peerHost(ctx); host != "" {
+		return "ip:" + host
+	}
+	return "unknown"
+}
+
+func peerHost(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return hostOf(p.Addr.String())
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"currency-converter/internal/auth"
+	"currency-converter/internal/httputil"
+	"currency-converter/internal/metrics"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// Middleware ограничивает частоту запросов к маршруту. Ставится внутри auth.Require,
+// чтобы аутентифицированный клиент учитывался по имени, а не по адресу; перебор
+// учётных данных до аутентификации сдерживает AuthMiddleware.
+// Маршрут берётся из шаблона ServeMux, поэтому обработчик должен быть зарегистрирован в mux.
+func (l *Limiter) Middleware(next http.HandlerFunc) http.Handler {
+	if !l.Enabled() {
+		return next
+	}
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		ok, retryAfter := l.Allow(req.Pattern, httpClientKey(req))
+		if !ok {
+			metrics.RateLimited.WithLabelValues("http", req.Pattern).Inc()
+			res.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(retryAfter)))
+			httputil.WriteError(res, http.StatusTooManyRequests, "rate limit exceeded, retry later")
+			return
+		}
+		next.ServeHTTP(res, req)
+	})
+}
+
+// AuthMiddleware ограничивает по адресу клиента неудачные попытки аутентификации
+// (ответы 401), чтобы ключи и токены нельзя было перебирать. Ставится снаружи
+// auth.Require; успешные запросы лимит не расходуют.
+func (l *Limiter) AuthMiddleware(next http.Handler) http.Handler {
+	if !l.authEnabled() {
+		return next
+	}
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		host := hostOf(req.RemoteAddr)
+		if blocked, retryAfter := l.authBlocked(host); blocked {
+			metrics.RateLimited.WithLabelValues("http", req.Pattern).Inc()
+			res.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(retryAfter)))
+			httputil.WriteError(res, http.StatusTooManyRequests, "too many failed authentication attempts, retry later")
+			return
+		}
+		rec := &statusRecorder{ResponseWriter: res, status: http.StatusOK}
+		next.ServeHTTP(rec, req)
+		if rec.status == http.StatusUnauthorized {
+			l.authFailed(host)
+		}
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func httpClientKey(req *http.Request) string {
+	if p, ok := auth.FromContext(req.Context()); ok {
+		return "principal:" + p.Subject
+	}
+	return "ip:" + hostOf(req.RemoteAddr)
+}
+
+func hostOf(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
@@ -0,0 +1,219 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Limit - параметры token bucket: Rate запросов в секунду с запасом Burst.
+// Нулевой Rate означает отсутствие ограничения.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+func (l Limit) unlimited() bool {
+	return l.Rate <= 0
+}
+
+// ParseLimit разбирает лимит в формате "rate:burst" (например, "10:20");
+// без burst он равен округлённому вверх rate.
+func ParseLimit(s string) (Limit, error) {
+	rateStr, burstStr, hasBurst := strings.Cut(strings.TrimSpace(s), ":")
+	r, err := strconv.ParseFloat(rateStr, 64)
+	if err != nil || r < 0 {
+		return Limit{}, fmt.Errorf("invalid rate %q", rateStr)
+	}
+	l := Limit{Rate: r, Burst: int(math.Ceil(r))}
+	if hasBurst {
+		b, err := strconv.Atoi(burstStr)
+		if err != nil || b < 0 {
+			return Limit{}, fmt.Errorf("invalid burst %q", burstStr)
+		}
+		l.Burst = b
+	}
+	if l.Rate > 0 && l.Burst == 0 {
+		l.Burst = 1
+	}
+	return l, nil
+}
+
+// Rules - лимит по умолчанию и лимиты для отдельных маршрутов REST
+// ("POST /conversion") или gRPC-методов ("/CurrencyConverter.ConversionService/CreateConversion").
+// Auth ограничивает неудачные попытки аутентификации с одного адреса.
+type Rules struct {
+	Default Limit
+	Routes  map[string]Limit
+	Auth    Limit
+}
+
+// ParseRules разбирает лимит по умолчанию, список "route=rate:burst" через запятую
+// и лимит неудачных попыток аутентификации.
+func ParseRules(def, routes, auth string) (Rules, error) {
+	rules := Rules{Routes: make(map[string]Limit)}
+	if strings.TrimSpace(def) != "" {
+		l, err := ParseLimit(def)
+		if err != nil {
+			return Rules{}, fmt.Errorf("invalid default rate limit: %w", err)
+		}
+		rules.Default = l
+	}
+	if strings.TrimSpace(auth) != "" {
+		l, err := ParseLimit(auth)
+		if err != nil {
+			return Rules{}, fmt.Errorf("invalid authentication rate limit: %w", err)
+		}
+		rules.Auth = l
+	}
+
+	for _, entry := range strings.Split(routes, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		route, limit, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(route) == "" {
+			return Rules{}, fmt.Errorf("invalid rate limit rule %q, expected route=rate:burst", entry)
+		}
+		l, err := ParseLimit(limit)
+		if err != nil {
+			return Rules{}, fmt.Errorf("invalid rate limit for %q: %w", route, err)
+		}
+		rules.Routes[strings.TrimSpace(route)] = l
+	}
+	return rules, nil
+}
+
+func (r Rules) limitFor(route string) Limit {
+	if l, ok := r.Routes[route]; ok {
+		return l
+	}
+	return r.Default
+}
+
+// idleTTL - через сколько забывается клиент без запросов; его корзина к тому времени уже полна.
+const idleTTL = 10 * time.Minute
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// Limiter хранит отдельную корзину на каждую пару (маршрут, клиент).
+type Limiter struct {
+	rules Rules
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func New(rules Rules) *Limiter {
+	return &Limiter{
+		rules:   rules,
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Enabled сообщает, задан ли хоть один лимит.
+func (l *Limiter) Enabled() bool {
+	if l == nil {
+		return false
+	}
+	if !l.rules.Default.unlimited() {
+		return true
+	}
+	for _, limit := range l.rules.Routes {
+		if !limit.unlimited() {
+			return true
+		}
+	}
+	return false
+}
+
+// Allow расходует токен клиента на маршруте. Если токена нет, возвращает false
+// и время, через которое стоит повторить запрос.
+func (l *Limiter) Allow(route, client string) (bool, time.Duration) {
+	limit := l.rules.limitFor(route)
+	if limit.unlimited() {
+		return true, 0
+	}
+
+	now := l.now()
+	b := l.bucketFor(route+"|"+client, limit, now)
+	res := b.limiter.ReserveN(now, 1)
+	if !res.OK() {
+		return false, time.Second
+	}
+	if delay := res.DelayFrom(now); delay > 0 {
+		res.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+func (l *Limiter) authEnabled() bool {
+	return l != nil && !l.rules.Auth.unlimited()
+}
+
+// authBlocked сообщает, исчерпал ли адрес неудачные попытки аутентификации,
+// и через сколько появится следующая. Попытка при этом не расходуется.
+func (l *Limiter) authBlocked(host string) (bool, time.Duration) {
+	now := l.now()
+	b := l.bucketFor("auth|"+host, l.rules.Auth, now)
+	tokens := b.limiter.TokensAt(now)
+	if tokens >= 1 {
+		return false, 0
+	}
+	return true, time.Duration((1 - tokens) / l.rules.Auth.Rate * float64(time.Second))
+}
+
+// authFailed расходует попытку аутентификации адреса.
+func (l *Limiter) authFailed(host string) {
+	now := l.now()
+	l.bucketFor("auth|"+host, l.rules.Auth, now).limiter.AllowN(now, 1)
+}
+
+// bucketFor возвращает корзину по ключу, создавая её с лимитом limit.
+func (l *Limiter) bucketFor(key string, limit Limit, now time.Time) *bucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(limit.Rate), limit.Burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+	return b
+}
+
+// sweep раз в idleTTL удаляет корзины простаивающих клиентов; вызывается под l.mu.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleTTL {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) > idleTTL {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// retryAfterSeconds округляет задержку вверх до целых секунд для заголовка Retry-After.
+func retryAfterSeconds(d time.Duration) int {
+	s := int(math.Ceil(d.Seconds()))
+	if s < 1 {
+		s = 1
+	}
+	return s
+}
@@ -0,0 +1,206 @@
+package ratelimit
+
+import (
+	"context"
+	"currency-converter/internal/auth"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// clock - управляемое время лимитера.
+type clock struct{ now time.Time }
+
+func newLimiter(t *testing.T, rules Rules) (*Limiter, *clock) {
+	t.Helper()
+	c := &clock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l := New(rules)
+	l.now = func() time.Time { return c.now }
+	return l, c
+}
+
+func TestAllowRefill(t *testing.T) {
+	l, c := newLimiter(t, Rules{Default: Limit{Rate: 2, Burst: 3}})
+
+	for i := range 3 {
+		if ok, _ := l.Allow("GET /x", "a"); !ok {
+			t.Fatalf("request %d within burst rejected", i+1)
+		}
+	}
+	ok, retryAfter := l.Allow("GET /x", "a")
+	if ok || retryAfter != 500*time.Millisecond {
+		t.Fatalf("Allow() over burst = %v, %v; want false, 500ms", ok, retryAfter)
+	}
+
+	// Отказ токен не расходует: через полсекунды появляется ровно один.
+	c.now = c.now.Add(500 * time.Millisecond)
+	if ok, _ := l.Allow("GET /x", "a"); !ok {
+		t.Fatal("token was not refilled")
+	}
+	if ok, _ := l.Allow("GET /x", "a"); ok {
+		t.Fatal("more than one token refilled in 500ms")
+	}
+}
+
+func TestAllowIsolation(t *testing.T) {
+	l, _ := newLimiter(t, Rules{
+		Default: Limit{Rate: 1, Burst: 1},
+		Routes:  map[string]Limit{"GET /free": {}},
+	})
+
+	if ok, _ := l.Allow("GET /x", "a"); !ok {
+		t.Fatal("first request rejected")
+	}
+	if ok, _ := l.Allow("GET /x", "a"); ok {
+		t.Fatal("second request of client a allowed")
+	}
+	if ok, _ := l.Allow("GET /x", "b"); !ok {
+		t.Error("client b shares the bucket of client a")
+	}
+	if ok, _ := l.Allow("GET /y", "a"); !ok {
+		t.Error("route GET /y shares the bucket of GET /x")
+	}
+	for range 5 {
+		if ok, _ := l.Allow("GET /free", "a"); !ok {
+			t.Fatal("route without a limit rejected a request")
+		}
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Limit
+		wantErr bool
+	}{
+		{in: "10:20", want: Limit{Rate: 10, Burst: 20}},
+		{in: "2.5", want: Limit{Rate: 2.5, Burst: 3}},
+		{in: "0.1:0", want: Limit{Rate: 0.1, Burst: 1}},
+		{in: "0", want: Limit{}},
+		{in: "-1", wantErr: true},
+		{in: "1:x", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLimit(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLimit(%q) = %+v, %v; want %+v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// serve прогоняет запрос с адреса addr через mux, как в сервере.
+func serve(h http.Handler, addr string, principal *auth.Principal) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	mux.Handle("GET /x", h)
+	req := httptest.NewRequest(http.MethodGet, "/x", nil)
+	req.RemoteAddr = addr
+	if principal != nil {
+		req = req.WithContext(auth.WithPrincipal(req.Context(), principal))
+	}
+	res := httptest.NewRecorder()
+	mux.ServeHTTP(res, req)
+	return res
+}
+
+func TestMiddleware(t *testing.T) {
+	l, _ := newLimiter(t, Rules{Default: Limit{Rate: 0.5, Burst: 1}})
+	h := l.Middleware(func(res http.ResponseWriter, req *http.Request) {})
+
+	if res := serve(h, "10.0.0.1:1000", nil); res.Code != http.StatusOK {
+		t.Fatalf("first request: status %d", res.Code)
+	}
+	res := serve(h, "10.0.0.1:2000", nil)
+	if res.Code != http.StatusTooManyRequests || res.Header().Get("Retry-After") != "2" {
+		t.Fatalf("second request from the same host: status %d, Retry-After %q; want 429, 2",
+			res.Code, res.Header().Get("Retry-After"))
+	}
+
+	// Аутентифицированный клиент учитывается по имени, а не по адресу.
+	alice := &auth.Principal{Subject: "alice", Role: auth.RoleReader}
+	if res := serve(h, "10.0.0.1:3000", alice); res.Code != http.StatusOK {
+		t.Errorf("alice from the same host: status %d", res.Code)
+	}
+	if res := serve(h, "10.0.0.2:1000", alice); res.Code != http.StatusTooManyRequests {
+		t.Errorf("alice from another host: status %d, want 429", res.Code)
+	}
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	l, c := newLimiter(t, Rules{Auth: Limit{Rate: 1, Burst: 2}})
+	status := http.StatusUnauthorized
+	h := l.AuthMiddleware(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(status)
+	}))
+
+	for i := range 2 {
+		if res := serve(h, "10.0.0.1:1000", nil); res.Code != http.StatusUnauthorized {
+			t.Fatalf("attempt %d: status %d, want 401", i+1, res.Code)
+		}
+	}
+	res := serve(h, "10.0.0.1:1000", nil)
+	if res.Code != http.StatusTooManyRequests || res.Header().Get("Retry-After") != "1" {
+		t.Fatalf("after failed attempts: status %d, Retry-After %q; want 429, 1", res.Code, res.Header().Get("Retry-After"))
+	}
+	if res := serve(h, "10.0.0.2:1000", nil); res.Code != http.StatusUnauthorized {
+		t.Errorf("another host: status %d, want 401", res.Code)
+	}
+
+	// Успешные запросы попыток не расходуют.
+	c.now = c.now.Add(time.Second)
+	status = http.StatusOK
+	for i := range 5 {
+		if res := serve(h, "10.0.0.1:1000", nil); res.Code != http.StatusOK {
+			t.Fatalf("authenticated request %d: status %d", i+1, res.Code)
+		}
+	}
+}
+
+func peerContext(host string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(host), Port: 5000}})
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	l, _ := newLimiter(t, Rules{Default: Limit{Rate: 1, Burst: 1}})
+	interceptor := l.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc/Method"}
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+
+	if _, err := interceptor(peerContext("10.0.0.1"), nil, info, handler); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	_, err := interceptor(peerContext("10.0.0.1"), nil, info, handler)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("second call: %v, want ResourceExhausted", err)
+	}
+	if _, err := interceptor(peerContext("10.0.0.2"), nil, info, handler); err != nil {
+		t.Errorf("call from another host: %v", err)
+	}
+}
+
+func TestAuthUnaryServerInterceptor(t *testing.T) {
+	l, _ := newLimiter(t, Rules{Auth: Limit{Rate: 1, Burst: 1}})
+	interceptor := l.AuthUnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc/Method"}
+	calls := 0
+	handler := func(context.Context, any) (any, error) {
+		calls++
+		return nil, status.Error(codes.Unauthenticated, "bad key")
+	}
+
+	if _, err := interceptor(peerContext("10.0.0.1"), nil, info, handler); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("first attempt: %v, want Unauthenticated", err)
+	}
+	if _, err := interceptor(peerContext("10.0.0.1"), nil, info, handler); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("second attempt: %v, want ResourceExhausted", err)
+	}
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+}
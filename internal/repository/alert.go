@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"currency-converter/internal/model"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// SaveAlert создаёт правило или заменяет существующее с тем же ID.
+func (r *repo) SaveAlert(ctx context.Context, rule *model.AlertRule) error {
+	if err := r.lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock()
+
+	prev, existed := r.alerts[rule.ID]
+	r.alerts[rule.ID] = rule
+	if err := r.saveAlertsToFile(ctx); err != nil {
+		if existed {
+			r.alerts[rule.ID] = prev
+		} else {
+			delete(r.alerts, rule.ID)
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *repo) GetAlerts(ctx context.Context) (map[string]*model.AlertRule, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	copyMap := make(map[string]*model.AlertRule, len(r.alerts))
+	for id, rule := range r.alerts {
+		copyMap[id] = rule
+	}
+	return copyMap, nil
+}
+
+func (r *repo) DeleteAlert(ctx context.Context, id string) error {
+	if err := r.lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock()
+
+	prev, exists := r.alerts[id]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrAlertNotFound, id)
+	}
+
+	delete(r.alerts, id)
+	if err := r.saveAlertsToFile(ctx); err != nil {
+		r.alerts[id] = prev
+		return err
+	}
+	return nil
+}
+
+func (r *repo) saveAlertsToFile(ctx context.Context) error {
+	data, err := json.MarshalIndent(r.alerts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal alerts data: %w", err)
+	}
+	if err := writeFileSync(ctx, alertFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write alerts to file: %w", err)
+	}
+	return nil
+}
+
+func (r *repo) LoadAlerts(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	fileData, err := os.ReadFile(alertFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			os.MkdirAll("data", 0755)
+			return nil
+		}
+		return fmt.Errorf("failed to read alerts file: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := json.Unmarshal(fileData, &r.alerts); err != nil {
+		return fmt.Errorf("failed to unmarshal alerts data: %w", err)
+	}
+	return nil
+}
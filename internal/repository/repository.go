@@ -1,79 +1,322 @@
 package repository
 
 import (
+	"context"
+	"currency-converter/internal/audit"
+	"currency-converter/internal/history"
+	"currency-converter/internal/metrics"
 	"currency-converter/internal/model"
+	"currency-converter/internal/tracing"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
 	currencyFile   = "data/currency.json"
 	conversionFile = "data/conversion.json"
+	overrideFile   = "data/overrides.json"
+	alertFile      = "data/alerts.json"
+	webhookFile    = "data/webhooks.json"
+	deliveryFile   = "data/webhook_deliveries.jsonl"
+	// legacyDeliveryFile - история доставок до перехода на журнал; переносится при загрузке.
+	legacyDeliveryFile = "data/webhook_deliveries.json"
 )
 
+var tracer = otel.Tracer("currency-converter/internal/repository")
+
+var (
+	ErrCurrencyExists   = errors.New("currency already exists")
+	ErrOverrideNotFound = errors.New("rate override not found")
+	ErrAlertNotFound    = errors.New("alert rule not found")
+	ErrWebhookNotFound  = errors.New("webhook subscription not found")
+)
+
+// Repository - хранилище сущностей. Все операции проверяют контекст: отменённый
+// или просроченный запрос не начинает работу, а уже начатая запись на диск
+// доводится до конца, чтобы файл и память не разошлись.
 type Repository interface {
-	Store(entity model.Entity) error
-	GetCurrencies() map[string]*model.Currency
-	GetConversions() []*model.Conversion
-	UpdateCurrency(currency *model.Currency) error
-	LoadCurrencies() error
-	LoadConversions() error
+	Store(ctx context.Context, entity model.Entity) error
+	InsertCurrency(ctx context.Context, currency *model.Currency) error
+	GetCurrencies(ctx context.Context) (map[string]*model.Currency, error)
+	GetConversions(ctx context.Context) ([]*model.Conversion, error)
+	UpdateCurrency(ctx context.Context, currency *model.Currency) error
+	DeleteCurrency(ctx context.Context, code string) error
+	LoadCurrencies(ctx context.Context) error
+	LoadConversions(ctx context.Context) error
+	GetRateHistory(ctx context.Context) ([]history.Observation, error)
+	Import(ctx context.Context, batch ImportBatch) error
+
+	SetOverride(ctx context.Context, override *model.RateOverride) error
+	GetOverrides(ctx context.Context) (map[string]*model.RateOverride, error)
+	DeleteOverride(ctx context.Context, code string) error
+	LoadOverrides(ctx context.Context) error
+
+	SaveAlert(ctx context.Context, rule *model.AlertRule) error
+	GetAlerts(ctx context.Context) (map[string]*model.AlertRule, error)
+	DeleteAlert(ctx context.Context, id string) error
+	LoadAlerts(ctx context.Context) error
+
+	SaveWebhook(ctx context.Context, sub *model.WebhookSubscription) error
+	GetWebhooks(ctx context.Context) (map[string]*model.WebhookSubscription, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	SaveDelivery(ctx context.Context, d *model.WebhookDelivery) error
+	GetDeliveries(ctx context.Context) ([]*model.WebhookDelivery, error)
+	LoadWebhooks(ctx context.Context) error
 }
 
 type repo struct {
 	mu          sync.RWMutex
 	currencies  map[string]*model.Currency
 	conversions []*model.Conversion
+	overrides   map[string]*model.RateOverride
+	alerts      map[string]*model.AlertRule
+	webhooks    map[string]*model.WebhookSubscription
+	deliveries  []*model.WebhookDelivery
+	// deliveryLogLines - строк в журнале доставок, включая устаревшие состояния.
+	deliveryLogLines int
+	auditLog         *audit.Log
+	history          *history.Store
 }
 
-func NewRepository() Repository {
+// NewRepository создаёт хранилище; каждое изменение курса записывается в auditLog
+// и в историю курсов (если они заданы).
+func NewRepository(auditLog *audit.Log, rateHistory *history.Store) Repository {
 	return &repo{
 		currencies:  make(map[string]*model.Currency),
 		conversions: []*model.Conversion{},
+		overrides:   make(map[string]*model.RateOverride),
+		alerts:      make(map[string]*model.AlertRule),
+		webhooks:    make(map[string]*model.WebhookSubscription),
+		deliveries:  []*model.WebhookDelivery{},
+		auditLog:    auditLog,
+		history:     rateHistory,
 	}
 }
 
-func (r *repo) Store(entity model.Entity) error {
-	r.mu.Lock()
+func (r *repo) Store(ctx context.Context, entity model.Entity) error {
+	if err := r.lock(ctx); err != nil {
+		return err
+	}
 	defer r.mu.Unlock()
 
 	switch v := entity.(type) {
 	case *model.Currency:
+		prev, existed := r.currencies[v.Code]
 		r.currencies[v.Code] = v
-		return r.saveCurrenciesToFile()
+		return r.commitCurrency(ctx, v.Code, prev, existed, v)
 	case *model.Conversion:
 		r.conversions = append(r.conversions, v)
-		return r.saveConversionsToFile()
+		if err := r.saveConversionsToFile(ctx); err != nil {
+			r.conversions = r.conversions[:len(r.conversions)-1]
+			return err
+		}
+		return nil
 	default:
 		return fmt.Errorf("unknown entity type provided")
 	}
 }
 
-func (r *repo) saveCurrenciesToFile() error {
+// InsertCurrency добавляет валюту, только если её ещё нет в хранилище.
+func (r *repo) InsertCurrency(ctx context.Context, currency *model.Currency) error {
+	if err := r.lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock()
+
+	if _, exists := r.currencies[currency.Code]; exists {
+		return fmt.Errorf("%w: %s", ErrCurrencyExists, currency.Code)
+	}
+
+	r.currencies[currency.Code] = currency
+	return r.commitCurrency(ctx, currency.Code, nil, false, currency)
+}
+
+// lock захватывает блокировку на запись, пока запрос ещё актуален. Ожидание
+// прерывается отменой или дедлайном ctx, даже если блокировку держит другой
+// писатель; захваченную позже блокировку тогда сразу отпускает фоновая горутина.
+func (r *repo) lock(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	locked := make(chan struct{})
+	go func() {
+		r.mu.Lock()
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+		if err := ctx.Err(); err != nil {
+			r.mu.Unlock()
+			return err
+		}
+		return nil
+	case <-ctx.Done():
+		go func() {
+			<-locked
+			r.mu.Unlock()
+		}()
+		return ctx.Err()
+	}
+}
+
+// commitCurrency сохраняет уже изменённую карту валют и пишет изменение в журнал аудита.
+// Если не удалось ни то, ни другое, изменение откатывается: курс, который не попал
+// в журнал, не должен отдаваться клиентам. next == nil означает удаление.
+func (r *repo) commitCurrency(ctx context.Context, code string, prev *model.Currency, existed bool, next *model.Currency) error {
+	rollback := func() {
+		if existed {
+			r.currencies[code] = prev
+		} else {
+			delete(r.currencies, code)
+		}
+	}
+
+	if err := r.saveCurrenciesToFile(ctx); err != nil {
+		rollback()
+		return err
+	}
+
+	action := audit.ActionUpdate
+	var oldRate, newRate *float64
+	if existed {
+		oldRate = audit.RatePtr(prev.Rate)
+	} else {
+		action = audit.ActionCreate
+	}
+	if next != nil {
+		newRate = audit.RatePtr(next.Rate)
+	} else {
+		action = audit.ActionDelete
+	}
+
+	if err := r.auditLog.RecordChange(ctx, action, code, oldRate, newRate); err != nil {
+		rollback()
+		if saveErr := r.saveCurrenciesToFile(ctx); saveErr != nil {
+			slog.ErrorContext(ctx, "failed to roll back currency after audit error", "code", code, "error", saveErr)
+		}
+		return err
+	}
+
+	// История не влияет на актуальный курс, поэтому её ошибка не откатывает изменение.
+	if next != nil {
+		if err := r.history.Record(code, next.Rate, time.Now()); err != nil {
+			slog.ErrorContext(ctx, "failed to record rate history", "code", code, "error", err)
+		}
+	}
+	return nil
+}
+
+func (r *repo) saveCurrenciesToFile(ctx context.Context) error {
 	data, err := json.MarshalIndent(r.currencies, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal currencies data: %w", err)
 	}
-	if err := os.WriteFile(currencyFile, data, 0644); err != nil {
+	if err := writeFileSync(ctx, currencyFile, data, 0644); err != nil {
 		return fmt.Errorf("failed to write currencies to file: %w", err)
 	}
 	return nil
 }
 
-func (r *repo) saveConversionsToFile() error {
+func (r *repo) saveConversionsToFile(ctx context.Context) error {
 	data, err := json.MarshalIndent(r.conversions, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal conversions data: %w", err)
 	}
-	if err := os.WriteFile(conversionFile, data, 0644); err != nil {
+	if err := writeFileSync(ctx, conversionFile, data, 0644); err != nil {
 		return fmt.Errorf("failed to write conversions to file: %w", err)
 	}
 	return nil
 }
 
-func (r *repo) LoadCurrencies() error {
+// writeFileSync пишет данные во временный файл, сбрасывает его на диск
+// и атомарно подменяет целевой файл, чтобы сбой не оставил его обрезанным.
+func writeFileSync(ctx context.Context, path string, data []byte, perm os.FileMode) (err error) {
+	_, span := tracer.Start(ctx, "repository.writeFile", trace.WithAttributes(
+		attribute.String("file", filepath.Base(path)),
+		attribute.Int("bytes", len(data)),
+	))
+	start := time.Now()
+	defer func() {
+		metrics.RepositoryWriteDuration.
+			WithLabelValues(filepath.Base(path), metrics.Result(err)).
+			Observe(time.Since(start).Seconds())
+		tracing.End(span, err)
+	}()
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, path)
+}
+
+// appendFileSync дописывает данные в конец файла и сбрасывает его на диск.
+// При ошибке файл обрезается до прежней длины, чтобы в нём не осталось половины записи.
+func appendFileSync(ctx context.Context, path string, data []byte, perm os.FileMode) (err error) {
+	_, span := tracer.Start(ctx, "repository.appendFile", trace.WithAttributes(
+		attribute.String("file", filepath.Base(path)),
+		attribute.Int("bytes", len(data)),
+	))
+	start := time.Now()
+	defer func() {
+		metrics.RepositoryWriteDuration.
+			WithLabelValues(filepath.Base(path), metrics.Result(err)).
+			Observe(time.Since(start).Seconds())
+		tracing.End(span, err)
+	}()
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if _, err = file.Write(data); err == nil {
+		err = file.Sync()
+	}
+	if err != nil {
+		file.Truncate(info.Size())
+		return err
+	}
+	return file.Close()
+}
+
+func (r *repo) LoadCurrencies(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	fileData, err := os.ReadFile(currencyFile)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -92,7 +335,10 @@ func (r *repo) LoadCurrencies() error {
 	return nil
 }
 
-func (r *repo) LoadConversions() error {
+func (r *repo) LoadConversions(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	fileData, err := os.ReadFile(conversionFile)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -111,19 +357,160 @@ func (r *repo) LoadConversions() error {
 	return nil
 }
 
-func (r *repo) UpdateCurrency(currency *model.Currency) error {
-	r.mu.Lock()
+// ImportBatch - записи архива, которые применяются вместе.
+type ImportBatch struct {
+	Currencies  []*model.Currency
+	RateHistory []history.Observation
+	Conversions []*model.Conversion
+}
+
+// Import применяет архив под одной блокировкой: конвертации, валюты и история курсов
+// пишутся по очереди, и если очередной шаг или журнал аудита не удался, выполненные
+// шаги отменяются. Записи аудита, успевшие попасть в журнал до ошибки, остаются:
+// журнал только дописывается.
+func (r *repo) Import(ctx context.Context, batch ImportBatch) error {
+	if err := r.lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock()
+
+	var undo []func()
+	rollback := func() {
+		for i := len(undo) - 1; i >= 0; i-- {
+			undo[i]()
+		}
+	}
+
+	if len(batch.Conversions) > 0 {
+		n := len(r.conversions)
+		r.conversions = append(r.conversions, batch.Conversions...)
+		if err := r.saveConversionsToFile(ctx); err != nil {
+			r.conversions = r.conversions[:n]
+			return fmt.Errorf("failed to import conversions: %w", err)
+		}
+		undo = append(undo, func() {
+			r.conversions = r.conversions[:n]
+			if err := r.saveConversionsToFile(ctx); err != nil {
+				slog.ErrorContext(ctx, "failed to roll back imported conversions", "error", err)
+			}
+		})
+	}
+
+	type change struct {
+		prev    *model.Currency
+		existed bool
+	}
+	before := make(map[string]change, len(batch.Currencies))
+	for _, cur := range batch.Currencies {
+		if _, ok := before[cur.Code]; !ok {
+			prev, existed := r.currencies[cur.Code]
+			before[cur.Code] = change{prev: prev, existed: existed}
+		}
+		r.currencies[cur.Code] = cur
+	}
+	restoreCurrencies := func() {
+		for code, c := range before {
+			if c.existed {
+				r.currencies[code] = c.prev
+			} else {
+				delete(r.currencies, code)
+			}
+		}
+	}
+	if len(batch.Currencies) > 0 {
+		if err := r.saveCurrenciesToFile(ctx); err != nil {
+			restoreCurrencies()
+			rollback()
+			return fmt.Errorf("failed to import currencies: %w", err)
+		}
+		undo = append(undo, func() {
+			restoreCurrencies()
+			if err := r.saveCurrenciesToFile(ctx); err != nil {
+				slog.ErrorContext(ctx, "failed to roll back imported currencies", "error", err)
+			}
+		})
+	}
+
+	// История пишется раньше текущих курсов валют, которые к ней добавятся ниже.
+	if r.history != nil && len(batch.RateHistory) > 0 {
+		undoHistory, err := r.history.Import(batch.RateHistory)
+		if err != nil {
+			rollback()
+			return fmt.Errorf("failed to import rate history: %w", err)
+		}
+		undo = append(undo, func() {
+			if err := undoHistory(); err != nil {
+				slog.ErrorContext(ctx, "failed to roll back imported rate history", "error", err)
+			}
+		})
+	}
+
+	for _, cur := range batch.Currencies {
+		action := audit.ActionCreate
+		var oldRate *float64
+		if c := before[cur.Code]; c.existed {
+			action, oldRate = audit.ActionUpdate, audit.RatePtr(c.prev.Rate)
+		}
+		if err := r.auditLog.RecordChange(ctx, action, cur.Code, oldRate, audit.RatePtr(cur.Rate)); err != nil {
+			rollback()
+			return fmt.Errorf("failed to import currency %s: %w", cur.Code, err)
+		}
+	}
+
+	// История не влияет на актуальный курс, поэтому её ошибка не откатывает импорт.
+	now := time.Now()
+	for _, cur := range batch.Currencies {
+		if err := r.history.Record(cur.Code, cur.Rate, now); err != nil {
+			slog.ErrorContext(ctx, "failed to record rate history", "code", cur.Code, "error", err)
+		}
+	}
+	return nil
+}
+
+func (r *repo) GetRateHistory(ctx context.Context) ([]history.Observation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if r.history == nil {
+		return []history.Observation{}, nil
+	}
+	return r.history.Observations()
+}
+
+func (r *repo) UpdateCurrency(ctx context.Context, currency *model.Currency) error {
+	if err := r.lock(ctx); err != nil {
+		return err
+	}
 	defer r.mu.Unlock()
 
-	if _, exists := r.currencies[currency.Code]; !exists {
+	prev, exists := r.currencies[currency.Code]
+	if !exists {
 		return fmt.Errorf("currency %s not found for update", currency.Code)
 	}
 
 	r.currencies[currency.Code] = currency
-	return r.saveCurrenciesToFile()
+	return r.commitCurrency(ctx, currency.Code, prev, true, currency)
+}
+
+func (r *repo) DeleteCurrency(ctx context.Context, code string) error {
+	if err := r.lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock()
+
+	prev, exists := r.currencies[code]
+	if !exists {
+		return fmt.Errorf("currency %s not found for delete", code)
+	}
+
+	delete(r.currencies, code)
+	return r.commitCurrency(ctx, code, prev, true, nil)
 }
 
-func (r *repo) GetCurrencies() map[string]*model.Currency {
+func (r *repo) GetCurrencies(ctx context.Context) (map[string]*model.Currency, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -131,14 +518,102 @@ func (r *repo) GetCurrencies() map[string]*model.Currency {
 	for code, currency := range r.currencies {
 		copyMap[code] = currency
 	}
-	return copyMap
+	return copyMap, nil
 }
 
-func (r *repo) GetConversions() []*model.Conversion {
+func (r *repo) GetConversions(ctx context.Context) ([]*model.Conversion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	result := make([]*model.Conversion, len(r.conversions))
 	copy(result, r.conversions)
-	return result
+	return result, nil
+}
+
+func (r *repo) SetOverride(ctx context.Context, override *model.RateOverride) error {
+	if err := r.lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock()
+
+	prev, existed := r.overrides[override.Code]
+	r.overrides[override.Code] = override
+	if err := r.saveOverridesToFile(ctx); err != nil {
+		if existed {
+			r.overrides[override.Code] = prev
+		} else {
+			delete(r.overrides, override.Code)
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *repo) GetOverrides(ctx context.Context) (map[string]*model.RateOverride, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	copyMap := make(map[string]*model.RateOverride, len(r.overrides))
+	for code, override := range r.overrides {
+		copyMap[code] = override
+	}
+	return copyMap, nil
+}
+
+func (r *repo) DeleteOverride(ctx context.Context, code string) error {
+	if err := r.lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock()
+
+	prev, exists := r.overrides[code]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrOverrideNotFound, code)
+	}
+
+	delete(r.overrides, code)
+	if err := r.saveOverridesToFile(ctx); err != nil {
+		r.overrides[code] = prev
+		return err
+	}
+	return nil
+}
+
+func (r *repo) saveOverridesToFile(ctx context.Context) error {
+	data, err := json.MarshalIndent(r.overrides, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal overrides data: %w", err)
+	}
+	if err := writeFileSync(ctx, overrideFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write overrides to file: %w", err)
+	}
+	return nil
+}
+
+func (r *repo) LoadOverrides(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	fileData, err := os.ReadFile(overrideFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			os.MkdirAll("data", 0755)
+			return nil
+		}
+		return fmt.Errorf("failed to read overrides file: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := json.Unmarshal(fileData, &r.overrides); err != nil {
+		return fmt.Errorf("failed to unmarshal overrides data: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,359 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"currency-converter/internal/audit"
+	"currency-converter/internal/history"
+	"currency-converter/internal/model"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+func newTestRepo(t *testing.T) *repo {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("data", 0755); err != nil {
+		t.Fatal(err)
+	}
+	return NewRepository(nil, nil).(*repo)
+}
+
+func TestLockReturnsContextErrorWhileHeld(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  func() (context.Context, context.CancelFunc)
+		want error
+	}{
+		{
+			name: "cancelled while waiting",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(20*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			want: context.Canceled,
+		},
+		{
+			name: "deadline while waiting",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 20*time.Millisecond)
+			},
+			want: context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRepo(t)
+			r.mu.Lock() // другой писатель
+			release := make(chan struct{})
+			defer close(release)
+			go func() {
+				<-release
+				r.mu.Unlock()
+			}()
+
+			ctx, cancel := tt.ctx()
+			defer cancel()
+			errc := make(chan error, 1)
+			go func() { errc <- r.Store(ctx, model.NewCurrency("USD", 90, "US dollar", "$")) }()
+
+			select {
+			case err := <-errc:
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("Store() error = %v, want %v", err, tt.want)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("Store() kept waiting for the lock after ctx was done")
+			}
+			if _, ok := r.currencies["USD"]; ok {
+				t.Error("currency stored although the request was abandoned")
+			}
+		})
+	}
+}
+
+func TestLockIsReleasedAfterAbandonedWait(t *testing.T) {
+	r := newTestRepo(t)
+	r.mu.Lock()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := r.lock(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("lock() error = %v, want context.Canceled", err)
+	}
+
+	// Брошенное ожидание не должно оставить блокировку захваченной навсегда.
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel2()
+	if err := r.lock(ctx2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("lock() error = %v, want context.DeadlineExceeded", err)
+	}
+	r.mu.Unlock()
+
+	if err := r.Store(context.Background(), model.NewCurrency("USD", 90, "US dollar", "$")); err != nil {
+		t.Fatalf("Store() after the holder released the lock: %v", err)
+	}
+}
+
+// newImportRepo - хранилище с журналом аудита и историей курсов, в котором уже есть
+// USD по 90, одна конвертация и одно наблюдение курса.
+func newImportRepo(t *testing.T) (*repo, *audit.Log) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	auditLog, err := audit.Open("data/audit.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { auditLog.Close() })
+	rateHistory, err := history.Open("data/rate_history.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { rateHistory.Close() })
+
+	r := NewRepository(auditLog, rateHistory).(*repo)
+	ctx := context.Background()
+	usd := model.NewCurrency("USD", 90, "US dollar", "$")
+	if err := r.Store(ctx, usd); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Store(ctx, model.NewConversion(1, usd, usd, 1)); err != nil {
+		t.Fatal(err)
+	}
+	return r, auditLog
+}
+
+func importBatch() ImportBatch {
+	usd, eur := model.NewCurrency("USD", 95, "US dollar", "$"), model.NewCurrency("EUR", 100, "Euro", "€")
+	return ImportBatch{
+		Currencies:  []*model.Currency{usd, eur},
+		RateHistory: []history.Observation{{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Code: "EUR", Rate: 99}},
+		Conversions: []*model.Conversion{model.NewConversion(2, usd, eur, 1.9)},
+	}
+}
+
+// checkUnchanged сверяет память хранилища, а с files - и его файлы, с состоянием newImportRepo.
+func checkUnchanged(t *testing.T, r *repo, files bool) {
+	t.Helper()
+	ctx := context.Background()
+	if curs, _ := r.GetCurrencies(ctx); len(curs) != 1 || curs["USD"].Rate != 90 {
+		t.Errorf("currencies = %v, want only USD at 90", curs)
+	}
+	if convs, _ := r.GetConversions(ctx); len(convs) != 1 {
+		t.Errorf("conversions = %d, want 1", len(convs))
+	}
+	if bars := r.history.Bars("EUR", time.Time{}, time.Time{}); len(bars) != 0 {
+		t.Errorf("EUR bars = %+v, want none", bars)
+	}
+
+	if !files {
+		return
+	}
+	if obs, _ := r.GetRateHistory(ctx); len(obs) != 1 || obs[0].Code != "USD" {
+		t.Errorf("rate history = %+v, want only the USD observation", obs)
+	}
+	// Файлы должны совпадать с памятью: перечитываем их в новое хранилище.
+	reloaded := NewRepository(nil, nil)
+	if err := reloaded.LoadCurrencies(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := reloaded.LoadConversions(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if curs, _ := reloaded.GetCurrencies(ctx); len(curs) != 1 || curs["USD"].Rate != 90 {
+		t.Errorf("currencies file = %v, want only USD at 90", curs)
+	}
+	if convs, _ := reloaded.GetConversions(ctx); len(convs) != 1 {
+		t.Errorf("conversions file = %d records, want 1", len(convs))
+	}
+}
+
+func TestImportIsAllOrNothing(t *testing.T) {
+	tests := []struct {
+		name   string
+		fail   func(t *testing.T, auditLog *audit.Log)
+		repair func(t *testing.T) // возвращает файлы на место, чтобы их можно было сверить
+	}{
+		{
+			// Каталог данных пропал: не удастся первая же запись.
+			name: "conversions not written",
+			fail: func(t *testing.T, _ *audit.Log) {
+				if err := os.RemoveAll("data"); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			// Конвертации уже записаны, а файл валют заменить нельзя.
+			name: "currencies not written",
+			fail: func(t *testing.T, _ *audit.Log) {
+				if err := os.Rename(currencyFile, currencyFile+".bak"); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Mkdir(currencyFile, 0755); err != nil {
+					t.Fatal(err)
+				}
+			},
+			repair: func(t *testing.T) {
+				if err := os.Remove(currencyFile); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Rename(currencyFile+".bak", currencyFile); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			// Все файлы и история записаны, отказывает журнал аудита.
+			name: "audit not written",
+			fail: func(t *testing.T, auditLog *audit.Log) {
+				auditLog.Close()
+			},
+			repair: func(*testing.T) {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, auditLog := newImportRepo(t)
+			tt.fail(t, auditLog)
+			if err := r.Import(context.Background(), importBatch()); err == nil {
+				t.Fatal("Import() error = nil, want a write error")
+			}
+			if tt.repair != nil {
+				tt.repair(t)
+			}
+			checkUnchanged(t, r, tt.repair != nil)
+		})
+	}
+}
+
+func TestImport(t *testing.T) {
+	r, auditLog := newImportRepo(t)
+	ctx := context.Background()
+	if err := r.Import(ctx, importBatch()); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+
+	curs, _ := r.GetCurrencies(ctx)
+	if len(curs) != 2 || curs["USD"].Rate != 95 || curs["EUR"].Rate != 100 {
+		t.Errorf("currencies = %v, want USD at 95 and EUR at 100", curs)
+	}
+	if convs, _ := r.GetConversions(ctx); len(convs) != 2 {
+		t.Errorf("conversions = %d, want 2", len(convs))
+	}
+	// Импортированное наблюдение и текущие курсы обеих валют.
+	if bars := r.history.Bars("EUR", time.Time{}, time.Time{}); len(bars) != 2 || bars[0].Close != 99 || bars[1].Close != 100 {
+		t.Errorf("EUR bars = %+v, want 99 from the archive and then 100", bars)
+	}
+	if entries, _ := auditLog.Query(audit.Filter{}); len(entries) != 3 {
+		t.Errorf("audit entries = %d, want USD create and the two imported currencies", len(entries))
+	}
+}
+
+func delivery(id string, status model.DeliveryStatus, attempts int) *model.WebhookDelivery {
+	return &model.WebhookDelivery{ID: id, Status: status, Attempts: attempts, Payload: json.RawMessage(`{}`)}
+}
+
+// deliveryStates - ID и состояние доставок по порядку, например "a:delivered/2".
+func deliveryStates(t *testing.T, r Repository) []string {
+	t.Helper()
+	list, err := r.GetDeliveries(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	states := make([]string, 0, len(list))
+	for _, d := range list {
+		states = append(states, fmt.Sprintf("%s:%s/%d", d.ID, d.Status, d.Attempts))
+	}
+	return states
+}
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.Count(data, []byte("\n"))
+}
+
+func TestDeliveryLog(t *testing.T) {
+	r := newTestRepo(t)
+	ctx := context.Background()
+	for _, d := range []*model.WebhookDelivery{
+		delivery("a", model.DeliveryPending, 0),
+		delivery("b", model.DeliveryPending, 0),
+		delivery("a", model.DeliveryPending, 1),
+		delivery("a", model.DeliveryDelivered, 2),
+		delivery("b", model.DeliveryDead, 3),
+	} {
+		if err := r.SaveDelivery(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{"a:delivered/2", "b:dead/3"}
+	if got := deliveryStates(t, r); !slices.Equal(got, want) {
+		t.Fatalf("deliveries = %v, want %v", got, want)
+	}
+	// Каждое состояние дописывается отдельной строкой.
+	if n := countLines(t, deliveryFile); n != 5 {
+		t.Errorf("log lines = %d, want 5", n)
+	}
+
+	reloaded := NewRepository(nil, nil)
+	if err := reloaded.LoadWebhooks(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := deliveryStates(t, reloaded); !slices.Equal(got, want) {
+		t.Errorf("reloaded deliveries = %v, want %v", got, want)
+	}
+
+	// Набралось достаточно устаревших строк - журнал переписывается.
+	r.deliveryLogLines = deliveryHistoryLimit + 3
+	if err := r.SaveDelivery(ctx, delivery("c", model.DeliveryPending, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if n := countLines(t, deliveryFile); n != 3 {
+		t.Errorf("log lines after compaction = %d, want 3", n)
+	}
+	reloaded = NewRepository(nil, nil)
+	if err := reloaded.LoadWebhooks(ctx); err != nil {
+		t.Fatal(err)
+	}
+	want = append(want, "c:pending/0")
+	if got := deliveryStates(t, reloaded); !slices.Equal(got, want) {
+		t.Errorf("deliveries after compaction = %v, want %v", got, want)
+	}
+}
+
+func TestDeliveryLogMigratesLegacyFile(t *testing.T) {
+	newTestRepo(t)
+	ctx := context.Background()
+	legacy, _ := json.Marshal([]*model.WebhookDelivery{
+		delivery("a", model.DeliveryDelivered, 1),
+		delivery("b", model.DeliveryDead, 3),
+	})
+	if err := os.WriteFile(legacyDeliveryFile, legacy, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRepository(nil, nil)
+	if err := r.LoadWebhooks(ctx); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"a:delivered/1", "b:dead/3"}
+	if got := deliveryStates(t, r); !slices.Equal(got, want) {
+		t.Fatalf("deliveries = %v, want %v", got, want)
+	}
+	if _, err := os.Stat(legacyDeliveryFile); !os.IsNotExist(err) {
+		t.Errorf("legacy file still exists: %v", err)
+	}
+	if n := countLines(t, deliveryFile); n != 2 {
+		t.Errorf("log lines = %d, want 2", n)
+	}
+}
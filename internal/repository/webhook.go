@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"currency-converter/internal/model"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// deliveryHistoryLimit - сколько доставок хранится в истории. Недоставленные
+// (dead) при обрезке не удаляются, чтобы их можно было отправить повторно.
+const deliveryHistoryLimit = 1000
+
+// SaveWebhook создаёт подписку или заменяет существующую с тем же ID.
+func (r *repo) SaveWebhook(ctx context.Context, sub *model.WebhookSubscription) error {
+	if err := r.lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock()
+
+	prev, existed := r.webhooks[sub.ID]
+	r.webhooks[sub.ID] = sub
+	if err := r.saveWebhooksToFile(ctx); err != nil {
+		if existed {
+			r.webhooks[sub.ID] = prev
+		} else {
+			delete(r.webhooks, sub.ID)
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *repo) GetWebhooks(ctx context.Context) (map[string]*model.WebhookSubscription, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	copyMap := make(map[string]*model.WebhookSubscription, len(r.webhooks))
+	for id, sub := range r.webhooks {
+		copyMap[id] = sub
+	}
+	return copyMap, nil
+}
+
+func (r *repo) DeleteWebhook(ctx context.Context, id string) error {
+	if err := r.lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock()
+
+	prev, exists := r.webhooks[id]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrWebhookNotFound, id)
+	}
+
+	delete(r.webhooks, id)
+	if err := r.saveWebhooksToFile(ctx); err != nil {
+		r.webhooks[id] = prev
+		return err
+	}
+	return nil
+}
+
+// SaveDelivery добавляет доставку в историю или обновляет её состояние. Новое состояние
+// дописывается в журнал доставок; когда устаревших строк в нём набирается больше
+// deliveryHistoryLimit, журнал переписывается заново.
+func (r *repo) SaveDelivery(ctx context.Context, d *model.WebhookDelivery) error {
+	if err := r.lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock()
+
+	line, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook delivery: %w", err)
+	}
+	if err := appendFileSync(ctx, deliveryFile, append(line, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write webhook delivery to file: %w", err)
+	}
+	r.deliveryLogLines++
+	r.deliveries = upsertDelivery(r.deliveries, d)
+
+	if r.deliveryLogLines > len(r.deliveries)+deliveryHistoryLimit {
+		// Состояние уже записано; неудачное сжатие повторится при следующей записи.
+		r.compactDeliveries(ctx)
+	}
+	return nil
+}
+
+// upsertDelivery заменяет доставку с тем же ID или добавляет её в конец и обрезает историю.
+func upsertDelivery(deliveries []*model.WebhookDelivery, d *model.WebhookDelivery) []*model.WebhookDelivery {
+	next := make([]*model.WebhookDelivery, 0, len(deliveries)+1)
+	replaced := false
+	for _, v := range deliveries {
+		if v.ID == d.ID {
+			v, replaced = d, true
+		}
+		next = append(next, v)
+	}
+	if !replaced {
+		next = append(next, d)
+	}
+	return trimDeliveries(next)
+}
+
+// trimDeliveries удаляет самые старые завершённые доставки сверх лимита.
+func trimDeliveries(deliveries []*model.WebhookDelivery) []*model.WebhookDelivery {
+	excess := len(deliveries) - deliveryHistoryLimit
+	if excess <= 0 {
+		return deliveries
+	}
+	result := make([]*model.WebhookDelivery, 0, deliveryHistoryLimit)
+	for _, d := range deliveries {
+		if excess > 0 && d.Status == model.DeliveryDelivered {
+			excess--
+			continue
+		}
+		result = append(result, d)
+	}
+	return result
+}
+
+// GetDeliveries возвращает историю доставок в порядке создания.
+func (r *repo) GetDeliveries(ctx context.Context) ([]*model.WebhookDelivery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	copySlice := make([]*model.WebhookDelivery, len(r.deliveries))
+	copy(copySlice, r.deliveries)
+	return copySlice, nil
+}
+
+func (r *repo) saveWebhooksToFile(ctx context.Context) error {
+	data, err := json.MarshalIndent(r.webhooks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhooks data: %w", err)
+	}
+	if err := writeFileSync(ctx, webhookFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write webhooks to file: %w", err)
+	}
+	return nil
+}
+
+// compactDeliveries переписывает журнал доставок, оставляя по строке на каждую доставку из истории.
+func (r *repo) compactDeliveries(ctx context.Context) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, d := range r.deliveries {
+		if err := encoder.Encode(d); err != nil {
+			return fmt.Errorf("failed to marshal webhook deliveries data: %w", err)
+		}
+	}
+	if err := writeFileSync(ctx, deliveryFile, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write webhook deliveries to file: %w", err)
+	}
+	r.deliveryLogLines = len(r.deliveries)
+	return nil
+}
+
+// loadDeliveries читает журнал доставок: последняя строка с ID - текущее состояние доставки.
+// История в прежнем формате (массив JSON) переносится в журнал.
+func (r *repo) loadDeliveries(ctx context.Context) error {
+	file, err := os.Open(deliveryFile)
+	if os.IsNotExist(err) {
+		return r.migrateDeliveries(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read webhook deliveries file: %w", err)
+	}
+	defer file.Close()
+
+	deliveries, lines := []*model.WebhookDelivery{}, 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		lines++
+		var d model.WebhookDelivery
+		if err := json.Unmarshal(scanner.Bytes(), &d); err != nil {
+			return fmt.Errorf("failed to unmarshal webhook delivery on line %d: %w", lines, err)
+		}
+		deliveries = upsertDelivery(deliveries, &d)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read webhook deliveries file: %w", err)
+	}
+	r.deliveries, r.deliveryLogLines = deliveries, lines
+	return nil
+}
+
+func (r *repo) migrateDeliveries(ctx context.Context) error {
+	fileData, err := os.ReadFile(legacyDeliveryFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read webhook deliveries file: %w", err)
+	}
+	if err := json.Unmarshal(fileData, &r.deliveries); err != nil {
+		return fmt.Errorf("failed to unmarshal webhook deliveries data: %w", err)
+	}
+	if err := r.compactDeliveries(ctx); err != nil {
+		return err
+	}
+	return os.Remove(legacyDeliveryFile)
+}
+
+// LoadWebhooks загружает подписки и историю доставок.
+func (r *repo) LoadWebhooks(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fileData, err := os.ReadFile(webhookFile)
+	switch {
+	case os.IsNotExist(err):
+		os.MkdirAll("data", 0755)
+	case err != nil:
+		return fmt.Errorf("failed to read webhooks file: %w", err)
+	default:
+		if err := json.Unmarshal(fileData, &r.webhooks); err != nil {
+			return fmt.Errorf("failed to unmarshal webhooks data: %w", err)
+		}
+	}
+	return r.loadDeliveries(ctx)
+}
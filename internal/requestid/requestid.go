@@ -0,0 +1,92 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	Header      = "X-Request-ID"
+	MetadataKey = "x-request-id"
+
+	maxLength = 128
+)
+
+type ctxKey struct{}
+
+func New() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// sanitize принимает идентификатор клиента, только если он разумной длины
+// и состоит из печатных символов; иначе генерируется новый.
+func sanitize(id string) string {
+	if id == "" || len(id) > maxLength {
+		return New()
+	}
+	for _, r := range id {
+		if r < 0x21 || r > 0x7e {
+			return New()
+		}
+	}
+	return id
+}
+
+// Middleware берёт X-Request-ID из запроса (или генерирует новый),
+// кладёт его в контекст и возвращает в ответе.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		id := sanitize(req.Header.Get(Header))
+		res.Header().Set(Header, id)
+		next.ServeHTTP(res, req.WithContext(WithID(req.Context(), id)))
+	})
+}
+
+func fromIncoming(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(MetadataKey); len(values) > 0 {
+		return sanitize(values[0])
+	}
+	return New()
+}
+
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		id := fromIncoming(ctx)
+		grpc.SetHeader(ctx, metadata.Pairs(MetadataKey, id))
+		return handler(WithID(ctx, id), req)
+	}
+}
+
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		id := fromIncoming(ss.Context())
+		ss.SetHeader(metadata.Pairs(MetadataKey, id))
+		return handler(srv, &idStream{ServerStream: ss, ctx: WithID(ss.Context(), id)})
+	}
+}
+
+type idStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *idStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"currency-converter/internal/metrics"
+	"currency-converter/internal/model"
+	"currency-converter/internal/repository"
+	"currency-converter/internal/tracing"
+	"currency-converter/internal/validation"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	ErrInvalidAlert  = errors.New("invalid alert rule")
+	ErrAlertNotFound = repository.ErrAlertNotFound
+)
+
+// newID - случайный идентификатор из n байт в hex.
+func newID(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validWebhookURL проверяет, что адрес получателя - абсолютный http(s) URL.
+func validWebhookURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func validateAlert(req *model.AlertRequest) error {
+	var errs validation.Errors
+	if err := model.ValidateCurrencyCode(req.Base); err != nil {
+		errs.Add("base", "%v", err)
+	}
+	if err := model.ValidateCurrencyCode(req.Quote); err != nil {
+		errs.Add("quote", "%v", err)
+	} else if req.Base == req.Quote {
+		errs.Add("quote", "base and quote must differ")
+	}
+	if !model.ValidAlertCondition(req.Condition) {
+		errs.Add("condition", "must be one of above, below, change_pct")
+	}
+	if req.Threshold <= 0 {
+		errs.Add("threshold", "must be greater than zero")
+	}
+	if req.CooldownSeconds < 0 {
+		errs.Add("cooldown_seconds", "cannot be negative")
+	}
+	if !validWebhookURL(req.WebhookURL) {
+		errs.Add("webhook_url", "must be an absolute http(s) URL")
+	}
+	return errs.Err(ErrInvalidAlert)
+}
+
+func (s *service) CreateAlert(ctx context.Context, req *model.AlertRequest) (_ *model.AlertRule, err error) {
+	ctx, span := tracer.Start(ctx, "service.CreateAlert")
+	defer func() { tracing.End(span, err) }()
+
+	if err := validateAlert(req); err != nil {
+		return nil, err
+	}
+	rule := &model.AlertRule{
+		ID:              newID(8),
+		Base:            req.Base,
+		Quote:           req.Quote,
+		Condition:       req.Condition,
+		Threshold:       req.Threshold,
+		CooldownSeconds: req.CooldownSeconds,
+		WebhookURL:      req.WebhookURL,
+		CreatedAt:       time.Now(),
+		LastRate:        s.currentPairRate(ctx, req.Base, req.Quote),
+	}
+	if err := s.repo.SaveAlert(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to save alert rule: %w", err)
+	}
+
+	slog.InfoContext(ctx, "alert rule created", "id", rule.ID, "pair", rule.Pair(), "condition", rule.Condition, "threshold", rule.Threshold)
+	return rule, nil
+}
+
+// UpdateAlert заменяет параметры правила; время последнего срабатывания сохраняется,
+// а пересечение порога отсчитывается заново от текущего курса.
+func (s *service) UpdateAlert(ctx context.Context, id string, req *model.AlertRequest) (_ *model.AlertRule, err error) {
+	ctx, span := tracer.Start(ctx, "service.UpdateAlert", trace.WithAttributes(attribute.String("alert.id", id)))
+	defer func() { tracing.End(span, err) }()
+
+	if err := validateAlert(req); err != nil {
+		return nil, err
+	}
+	existing, err := s.GetAlert(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := *existing
+	rule.Base = req.Base
+	rule.Quote = req.Quote
+	rule.Condition = req.Condition
+	rule.Threshold = req.Threshold
+	rule.CooldownSeconds = req.CooldownSeconds
+	rule.WebhookURL = req.WebhookURL
+	rule.LastRate = s.currentPairRate(ctx, req.Base, req.Quote)
+	if err := s.repo.SaveAlert(ctx, &rule); err != nil {
+		return nil, fmt.Errorf("failed to save alert rule: %w", err)
+	}
+
+	slog.InfoContext(ctx, "alert rule updated", "id", id, "pair", rule.Pair())
+	return &rule, nil
+}
+
+func (s *service) GetAlert(ctx context.Context, id string) (_ *model.AlertRule, err error) {
+	ctx, span := tracer.Start(ctx, "service.GetAlert", trace.WithAttributes(attribute.String("alert.id", id)))
+	defer func() { tracing.End(span, err) }()
+
+	alerts, err := s.repo.GetAlerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rule, ok := alerts[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrAlertNotFound, id)
+	}
+	return rule, nil
+}
+
+func (s *service) ListAlerts(ctx context.Context) (_ []*model.AlertRule, err error) {
+	ctx, span := tracer.Start(ctx, "service.ListAlerts")
+	defer func() { tracing.End(span, err) }()
+
+	alerts, err := s.repo.GetAlerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.AlertRule, 0, len(alerts))
+	for _, rule := range alerts {
+		result = append(result, rule)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result, nil
+}
+
+func (s *service) DeleteAlert(ctx context.Context, id string) (err error) {
+	ctx, span := tracer.Start(ctx, "service.DeleteAlert", trace.WithAttributes(attribute.String("alert.id", id)))
+	defer func() { tracing.End(span, err) }()
+
+	if err := s.repo.DeleteAlert(ctx, id); err != nil {
+		if errors.Is(err, ErrAlertNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to delete alert rule '%s': %w", id, err)
+	}
+	slog.InfoContext(ctx, "alert rule deleted", "id", id)
+	return nil
+}
+
+// pairRate - курс base в quote по курсам к рублю; ноль, если курс неизвестен.
+func pairRate(rates map[string]*model.Currency, base, quote string) float64 {
+	b, q := rates[base], rates[quote]
+	if b == nil || q == nil || q.Rate <= 0 {
+		return 0
+	}
+	return b.Rate / q.Rate
+}
+
+// currentPairRate - курс пары по сохранённым валютам: от него новое правило
+// отсчитывает пересечение порога.
+func (s *service) currentPairRate(ctx context.Context, base, quote string) float64 {
+	currencies, err := s.repo.GetCurrencies(ctx)
+	if err != nil {
+		return 0
+	}
+	return pairRate(currencies, base, quote)
+}
+
+// evaluateAlerts проверяет правила после загрузки курсов. rates - курсы к рублю после
+// применения переопределений, previous - вчерашние курсы ЦБ РФ к рублю для тех валют,
+// курс которых не переопределён. Курс каждой проверки запоминается в правиле, поэтому
+// повторные синхронизации с теми же курсами правило не запускают.
+// Уведомления доставляют воркеры webhook, чтобы медленный получатель не задерживал
+// загрузку курсов. Курс недоставленного уведомления не запоминается, поэтому оно
+// повторится при следующей синхронизации.
+func (s *service) evaluateAlerts(ctx context.Context, rates map[string]*model.Currency, previous map[string]float64, now time.Time) {
+	alerts, err := s.repo.GetAlerts(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to read alert rules", "error", err)
+		return
+	}
+
+	for _, rule := range alerts {
+		if s.webhooks.alertPending(rule.ID) {
+			continue
+		}
+		rate := pairRate(rates, rule.Base, rule.Quote)
+		if rate <= 0 {
+			continue
+		}
+		var prevRate float64
+		if prevBase, prevQuote := previous[rule.Base], previous[rule.Quote]; prevBase > 0 && prevQuote > 0 {
+			prevRate = prevBase / prevQuote
+		}
+
+		event, fired := rule.Evaluate(rate, prevRate, now)
+		if fired && !rule.CoolingDown(now) {
+			if s.webhooks.pushAlert(webhookJob{ctx: context.WithoutCancel(ctx), alert: event}) {
+				continue
+			}
+			metrics.AlertNotifications.WithLabelValues("dropped").Inc()
+			slog.WarnContext(ctx, "alert dropped: webhook queue is full or stopped", "id", rule.ID, "pair", rule.Pair())
+			continue
+		}
+		// Пересечение во время cooldown тоже запоминается: после него правило ждёт нового.
+		if rule.LastRate != rate {
+			updated := *rule
+			updated.LastRate = rate
+			if err := s.repo.SaveAlert(ctx, &updated); err != nil {
+				slog.ErrorContext(ctx, "failed to record alert rate", "id", rule.ID, "error", err)
+			}
+		}
+	}
+}
+
+// deliverAlert отправляет уведомление и при успехе запоминает курс и запускает cooldown правила.
+func (s *service) deliverAlert(ctx context.Context, event *model.AlertEvent) {
+	rule := event.Rule
+	defer s.webhooks.alertDone(rule.ID)
+
+	ctx, span := tracer.Start(ctx, "service.deliverAlert", trace.WithAttributes(attribute.String("alert.id", rule.ID)))
+	var err error
+	defer func() { tracing.End(span, err) }()
+
+	if err = s.notifier.Send(ctx, rule.WebhookURL, event); err != nil {
+		metrics.AlertNotifications.WithLabelValues("failed").Inc()
+		slog.ErrorContext(ctx, "failed to deliver alert", "id", rule.ID, "pair", rule.Pair(), "error", err)
+		return
+	}
+	metrics.AlertNotifications.WithLabelValues("delivered").Inc()
+	slog.InfoContext(ctx, "alert fired", "id", rule.ID, "message", event.Message)
+
+	// Правило перечитывается: пока шла доставка, его могли изменить или удалить.
+	current, err := s.repo.GetAlerts(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record alert firing", "id", rule.ID, "error", err)
+		return
+	}
+	stored, ok := current[rule.ID]
+	if !ok {
+		return
+	}
+	updated := *stored
+	updated.LastFiredAt = &event.FiredAt
+	updated.LastRate = event.Rate
+	if err = s.repo.SaveAlert(ctx, &updated); err != nil {
+		slog.ErrorContext(ctx, "failed to record alert firing", "id", rule.ID, "error", err)
+	}
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"currency-converter/internal/model"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// alertReceiver принимает уведомления; пока block не закрыт, ответ задерживается.
+type alertReceiver struct {
+	*httptest.Server
+	events chan model.AlertEvent
+}
+
+func newAlertReceiver(t *testing.T, block <-chan struct{}) *alertReceiver {
+	t.Helper()
+	r := &alertReceiver{events: make(chan model.AlertEvent, 16)}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		var event model.AlertEvent
+		if err := json.NewDecoder(req.Body).Decode(&event); err != nil {
+			t.Errorf("decode alert event: %v", err)
+		}
+		if block != nil {
+			<-block
+		}
+		r.events <- event
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+// next ждёт уведомление; ok = false, если за wait его не было.
+func (r *alertReceiver) next(wait time.Duration) (model.AlertEvent, bool) {
+	select {
+	case event := <-r.events:
+		return event, true
+	case <-time.After(wait):
+		return model.AlertEvent{}, false
+	}
+}
+
+func startDispatcher(t *testing.T, s *service) {
+	t.Helper()
+	dispatcher := s.WebhookDispatcher()
+	if err := dispatcher.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dispatcher.Stop(context.Background()) })
+}
+
+// syncRates загружает курсы и ждёт, пока воркеры разошлют уведомления.
+func syncRates(t *testing.T, s *service) {
+	t.Helper()
+	if err := s.loadCBRData(context.Background()); err != nil {
+		t.Fatalf("loadCBRData: %v", err)
+	}
+	waitFor(t, func() bool {
+		s.webhooks.mu.Lock()
+		defer s.webhooks.mu.Unlock()
+		return len(s.webhooks.alerts) == 0
+	})
+}
+
+func createAlert(t *testing.T, s *service, req model.AlertRequest) *model.AlertRule {
+	t.Helper()
+	rule, err := s.CreateAlert(context.Background(), &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rule
+}
+
+func TestAlertFiresOncePerCrossing(t *testing.T) {
+	cbrServer := newFakeCBR(t, map[string][2]float64{"USD": {94, 93}})
+	s := newTestService(t, cbrServer.URL, Options{WriteMode: WriteSync})
+	receiver := newAlertReceiver(t, nil)
+	startDispatcher(t, s)
+	syncRates(t, s)
+
+	// cooldown 0: повторы сдерживает только запомненный курс.
+	crossed := createAlert(t, s, model.AlertRequest{Base: "USD", Quote: "RUB", Condition: model.AlertAbove, Threshold: 95, WebhookURL: receiver.URL})
+	// Курс уже выше 90 - пересечения нет.
+	createAlert(t, s, model.AlertRequest{Base: "USD", Quote: "RUB", Condition: model.AlertAbove, Threshold: 90, WebhookURL: receiver.URL})
+	if crossed.LastRate != 94 {
+		t.Fatalf("LastRate of a new rule = %v, want the current rate 94", crossed.LastRate)
+	}
+
+	cbrServer.set("USD", 96, 94)
+	syncRates(t, s)
+	event, ok := receiver.next(2 * time.Second)
+	if !ok || event.Rule.ID != crossed.ID || event.Rate != 96 {
+		t.Fatalf("event = %+v, %v; want rule %s at 96", event, ok, crossed.ID)
+	}
+	waitFor(t, func() bool {
+		rule, err := s.GetAlert(context.Background(), crossed.ID)
+		return err == nil && rule.LastRate == 96
+	})
+
+	// ЦБ РФ публикует курсы раз в день: следующие синхронизации видят те же курсы.
+	syncRates(t, s)
+	syncRates(t, s)
+	if event, ok := receiver.next(100 * time.Millisecond); ok {
+		t.Fatalf("unexpected second event on the same rates: %+v", event)
+	}
+
+	// Курс вернулся под порог и снова пересёк его - новое уведомление.
+	cbrServer.set("USD", 94, 96)
+	syncRates(t, s)
+	cbrServer.set("USD", 97, 94)
+	syncRates(t, s)
+	if event, ok := receiver.next(2 * time.Second); !ok || event.Rule.ID != crossed.ID || event.Rate != 97 {
+		t.Fatalf("event = %+v, %v; want rule %s at 97", event, ok, crossed.ID)
+	}
+}
+
+func TestOverrideDoesNotLookLikeDailyChange(t *testing.T) {
+	cbrServer := newFakeCBR(t, map[string][2]float64{"USD": {90, 90}})
+	s := newTestService(t, cbrServer.URL, Options{WriteMode: WriteSync})
+	receiver := newAlertReceiver(t, nil)
+	startDispatcher(t, s)
+	syncRates(t, s)
+
+	createAlert(t, s, model.AlertRequest{Base: "USD", Quote: "RUB", Condition: model.AlertChangePct, Threshold: 5, WebhookURL: receiver.URL})
+	// 100 против вчерашних 90 у ЦБ РФ - это не дневное изменение курса.
+	if _, err := s.SetOverride(context.Background(), &model.RateOverride{Code: "USD", Rate: 100, Reason: "test"}); err != nil {
+		t.Fatal(err)
+	}
+	syncRates(t, s)
+	syncRates(t, s)
+	if event, ok := receiver.next(100 * time.Millisecond); ok {
+		t.Fatalf("override fired a change_pct alert: %+v", event)
+	}
+}
+
+func TestAlertDeliveredInBackground(t *testing.T) {
+	cbrServer := newFakeCBR(t, map[string][2]float64{"USD": {94, 93}})
+	s := newTestService(t, cbrServer.URL, Options{WriteMode: WriteSync})
+	release := make(chan struct{})
+	receiver := newAlertReceiver(t, release)
+	defer close(release)
+	startDispatcher(t, s)
+	syncRates(t, s)
+
+	rule := createAlert(t, s, model.AlertRequest{Base: "USD", Quote: "RUB", Condition: model.AlertAbove, Threshold: 95, CooldownSeconds: 3600, WebhookURL: receiver.URL})
+	cbrServer.set("USD", 96, 94)
+
+	// Получатель не отвечает, но загрузка курсов его не ждёт.
+	loaded := make(chan error, 1)
+	go func() { loaded <- s.loadCBRData(context.Background()) }()
+	select {
+	case err := <-loaded:
+		if err != nil {
+			t.Fatalf("loadCBRData: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("loadCBRData waits for the alert receiver")
+	}
+
+	release <- struct{}{}
+	if event, ok := receiver.next(2 * time.Second); !ok || event.Rule.ID != rule.ID {
+		t.Fatalf("event = %+v, %v; want rule %s", event, ok, rule.ID)
+	}
+	waitFor(t, func() bool {
+		rule, err := s.GetAlert(context.Background(), rule.ID)
+		return err == nil && rule.LastFiredAt != nil
+	})
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"currency-converter/internal/lifecycle"
+	"currency-converter/internal/model"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// PersistenceWorker - компонент, который сохраняет сущности из очереди записи.
+// При остановке дожидается записи всего, что уже попало в очередь.
+func (s *service) PersistenceWorker() lifecycle.Component {
+	return &persistenceWorker{s: s}
+}
+
+type persistenceWorker struct {
+	s *service
+}
+
+func (w *persistenceWorker) Name() string { return "persistence worker" }
+
+func (w *persistenceWorker) Start(ctx context.Context) error {
+	go w.s.processEntities()
+	return nil
+}
+
+func (w *persistenceWorker) Stop(ctx context.Context) error {
+	return w.s.Shutdown(ctx)
+}
+
+// SyncLoop - компонент периодической загрузки курсов ЦБ РФ и мониторинга новых валют.
+func (s *service) SyncLoop() lifecycle.Component {
+	return &syncLoop{s: s}
+}
+
+type syncLoop struct {
+	s      *service
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func (l *syncLoop) Name() string { return "CBR sync loop" }
+
+func (l *syncLoop) Start(ctx context.Context) error {
+	// Цикл живёт до явного Stop, а не до отмены контекста запуска,
+	// чтобы менеджер мог останавливать компоненты в нужном порядке.
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	l.cancel = cancel
+
+	l.wg.Add(2)
+	go func() {
+		defer l.wg.Done()
+		l.s.syncCBRData(runCtx, &l.wg)
+	}()
+	go func() {
+		defer l.wg.Done()
+		l.s.startLogging(runCtx)
+	}()
+
+	slog.InfoContext(ctx, "CBR sync loop started")
+	return nil
+}
+
+func (l *syncLoop) Stop(ctx context.Context) error {
+	l.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		l.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("sync loop did not stop in time: %w", ctx.Err())
+	}
+}
+
+// WebhookDispatcher - компонент фоновой доставки webhook и уведомлений алертов.
+// При запуске продолжает доставки, прерванные прошлой остановкой; при остановке
+// ждёт текущих попыток.
+func (s *service) WebhookDispatcher() lifecycle.Component {
+	return &webhookDispatcher{s: s}
+}
+
+type webhookDispatcher struct {
+	s *service
+}
+
+func (d *webhookDispatcher) Name() string { return "webhook dispatcher" }
+
+func (d *webhookDispatcher) Start(ctx context.Context) error {
+	for range d.s.opts.WebhookWorkers {
+		d.s.webhooks.workers.Add(1)
+		go d.s.processWebhooks()
+	}
+
+	deliveries, err := d.s.repo.GetDeliveries(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	resumed := 0
+	for _, v := range deliveries {
+		if v.Status != model.DeliveryPending {
+			continue
+		}
+		var delay time.Duration
+		if v.NextAttemptAt != nil {
+			delay = max(v.NextAttemptAt.Sub(now), 0)
+		}
+		d.s.webhooks.schedule(ctx, v.ID, delay)
+		resumed++
+	}
+	if resumed > 0 {
+		slog.InfoContext(ctx, "pending webhook deliveries resumed", "count", resumed)
+	}
+	return nil
+}
+
+func (d *webhookDispatcher) Stop(ctx context.Context) error {
+	d.s.webhooks.close()
+
+	done := make(chan struct{})
+	go func() {
+		d.s.webhooks.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("webhook dispatcher did not stop in time: %w", ctx.Err())
+	}
+}
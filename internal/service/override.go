@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"currency-converter/internal/audit"
+	"currency-converter/internal/model"
+	"currency-converter/internal/repository"
+	"currency-converter/internal/tracing"
+	"currency-converter/internal/validation"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RatePolicy определяет, чей курс главнее при синхронизации с ЦБ РФ.
+type RatePolicy string
+
+const (
+	// PolicyManual - действующее ручное переопределение сохраняется при синхронизации до истечения срока.
+	PolicyManual RatePolicy = "manual"
+	// PolicyProvider - курс ЦБ РФ всегда побеждает, синхронизация снимает переопределения.
+	PolicyProvider RatePolicy = "provider"
+)
+
+var (
+	ErrCurrencyNotFound = errors.New("currency not found")
+	ErrInvalidOverride  = errors.New("invalid rate override")
+	ErrOverrideNotFound = repository.ErrOverrideNotFound
+)
+
+// SetOverride выставляет курс вручную и запоминает его, чтобы синхронизация с ЦБ РФ
+// не перезаписала значение до истечения срока.
+func (s *service) SetOverride(ctx context.Context, o *model.RateOverride) (_ *model.RateOverride, err error) {
+	ctx, span := tracer.Start(ctx, "service.SetOverride", trace.WithAttributes(attribute.String("currency.code", o.Code)))
+	defer func() { tracing.End(span, err) }()
+
+	now := time.Now()
+	var errs validation.Errors
+	if o.Code == "" {
+		errs.Add("code", "currency code is required")
+	}
+	if o.Rate <= 0 {
+		errs.Add("rate", "must be greater than zero")
+	}
+	if o.Reason == "" {
+		errs.Add("reason", "is required")
+	}
+	if o.ExpiresAt != nil && !o.ExpiresAt.After(now) {
+		errs.Add("expires_at", "must be in the future")
+	}
+	if err := errs.Err(ErrInvalidOverride); err != nil {
+		return nil, err
+	}
+
+	currencies, err := s.repo.GetCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cur, ok := currencies[o.Code]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCurrencyNotFound, o.Code)
+	}
+
+	override, err := s.saveOverride(ctx, cur, &model.RateOverride{
+		Code:      o.Code,
+		Rate:      o.Rate,
+		Reason:    o.Reason,
+		CreatedAt: now,
+		ExpiresAt: o.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyRate(audit.WithReason(ctx, "override: "+o.Reason), cur, override.Rate); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "rate override set", "code", o.Code, "rate", o.Rate, "reason", o.Reason)
+	return override, nil
+}
+
+// saveOverride запоминает переопределение курса cur. Курс поставщика берётся из
+// текущего значения, а при повторном переопределении - из предыдущего.
+func (s *service) saveOverride(ctx context.Context, cur *model.Currency, override *model.RateOverride) (*model.RateOverride, error) {
+	override.ProviderRate = cur.Rate
+	overrides, err := s.repo.GetOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if prev, exists := overrides[override.Code]; exists {
+		override.ProviderRate = prev.ProviderRate
+	}
+
+	if err := s.repo.SetOverride(ctx, override); err != nil {
+		return nil, fmt.Errorf("failed to save override for '%s': %w", override.Code, err)
+	}
+	return override, nil
+}
+
+// restoreOverride возвращает переопределение prev (nil - его не было) после неудачного
+// обновления валюты; запрос к этому моменту может быть уже отменён.
+func (s *service) restoreOverride(ctx context.Context, code string, prev *model.RateOverride) {
+	cleanupCtx := context.WithoutCancel(ctx)
+	var err error
+	if prev != nil {
+		err = s.repo.SetOverride(cleanupCtx, prev)
+	} else {
+		err = s.repo.DeleteOverride(cleanupCtx, code)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to restore override after failed currency update", "code", code, "error", err)
+	}
+}
+
+func (s *service) ListOverrides(ctx context.Context) (_ []*model.RateOverride, err error) {
+	ctx, span := tracer.Start(ctx, "service.ListOverrides")
+	defer func() { tracing.End(span, err) }()
+
+	overrides, err := s.repo.GetOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.RateOverride, 0, len(overrides))
+	for _, o := range overrides {
+		result = append(result, o)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Code < result[j].Code })
+	return result, nil
+}
+
+// ClearOverride снимает переопределение и возвращает последний известный курс поставщика.
+func (s *service) ClearOverride(ctx context.Context, code string) (err error) {
+	ctx, span := tracer.Start(ctx, "service.ClearOverride", trace.WithAttributes(attribute.String("currency.code", code)))
+	defer func() { tracing.End(span, err) }()
+
+	overrides, err := s.repo.GetOverrides(ctx)
+	if err != nil {
+		return err
+	}
+	override, ok := overrides[code]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrOverrideNotFound, code)
+	}
+	if err := s.repo.DeleteOverride(ctx, code); err != nil {
+		return fmt.Errorf("failed to clear override for '%s': %w", code, err)
+	}
+	// Переопределение уже снято - курс поставщика возвращается и после отмены запроса.
+	s.restoreProviderRate(audit.WithReason(context.WithoutCancel(ctx), "override cleared"), override)
+
+	slog.InfoContext(ctx, "rate override cleared", "code", code)
+	return nil
+}
+
+// expireOverrides снимает переопределения, срок которых истёк.
+func (s *service) expireOverrides(ctx context.Context, now time.Time) {
+	ctx = audit.WithMeta(ctx, audit.Meta{
+		Actor:  "system",
+		Source: audit.SourceSystem,
+		Reason: "override expired",
+	})
+	overrides, err := s.repo.GetOverrides(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to read overrides", "error", err)
+		return
+	}
+	for code, override := range overrides {
+		if !override.Expired(now) {
+			continue
+		}
+		if err := s.repo.DeleteOverride(ctx, code); err != nil {
+			slog.ErrorContext(ctx, "failed to remove expired override", "code", code, "error", err)
+			continue
+		}
+		s.restoreProviderRate(ctx, override)
+		slog.InfoContext(ctx, "rate override expired", "code", code)
+	}
+}
+
+// applyOverrides подменяет курсы поставщика действующими переопределениями согласно политике.
+func (s *service) applyOverrides(ctx context.Context, rates map[string]*model.Currency, now time.Time) {
+	overrides, err := s.repo.GetOverrides(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to read overrides", "error", err)
+		return
+	}
+	for code, override := range overrides {
+		cur, ok := rates[code]
+		if !ok {
+			continue
+		}
+		if s.opts.RatePolicy == PolicyProvider || override.Expired(now) {
+			if err := s.repo.DeleteOverride(ctx, code); err != nil {
+				slog.ErrorContext(ctx, "failed to remove override", "code", code, "error", err)
+			}
+			continue
+		}
+
+		updated := *override
+		updated.ProviderRate = cur.Rate
+		if err := s.repo.SetOverride(ctx, &updated); err != nil {
+			slog.ErrorContext(ctx, "failed to update provider rate of override", "code", code, "error", err)
+		}
+		cur.Rate = override.Rate
+	}
+}
+
+func (s *service) restoreProviderRate(ctx context.Context, override *model.RateOverride) {
+	if override.ProviderRate <= 0 {
+		return
+	}
+	currencies, err := s.repo.GetCurrencies(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to restore provider rate", "code", override.Code, "error", err)
+		return
+	}
+	cur, ok := currencies[override.Code]
+	if !ok {
+		return
+	}
+	if err := s.applyRate(ctx, cur, override.ProviderRate); err != nil {
+		slog.ErrorContext(ctx, "failed to restore provider rate", "code", override.Code, "error", err)
+	}
+}
+
+func (s *service) applyRate(ctx context.Context, cur *model.Currency, rate float64) error {
+	updated := *cur
+	updated.Rate = rate
+	updated.SetPrevious(cur.PreviousRate)
+	if err := s.repo.UpdateCurrency(ctx, &updated); err != nil {
+		return fmt.Errorf("failed to apply rate for '%s': %w", cur.Code, err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"currency-converter/internal/model"
+	"currency-converter/internal/repository"
+	"errors"
+	"testing"
+)
+
+func TestUpdateCurrencySurvivesSync(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RatePolicy
+		want   float64
+	}{
+		{name: "manual policy keeps the rate", policy: PolicyManual, want: 100},
+		{name: "provider policy restores CBR rate", policy: PolicyProvider, want: 91},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cbr := newFakeCBR(t, map[string][2]float64{"USD": {90, 89}})
+			s := newTestService(t, cbr.URL, Options{RatePolicy: tt.policy})
+			ctx := context.Background()
+			if err := s.loadCBRData(ctx); err != nil {
+				t.Fatalf("initial sync: %v", err)
+			}
+
+			if _, err := s.UpdateCurrency(ctx, &model.Currency{Code: "USD", Rate: 100, Name: "Доллар США", Symbol: "$"}); err != nil {
+				t.Fatalf("UpdateCurrency: %v", err)
+			}
+			overrides, _ := s.ListOverrides(ctx)
+			if len(overrides) != 1 || overrides[0].Rate != 100 || overrides[0].ProviderRate != 90 {
+				t.Fatalf("override after update = %+v, want rate 100 over provider rate 90", overrides)
+			}
+
+			cbr.set("USD", 91, 90)
+			if err := s.loadCBRData(ctx); err != nil {
+				t.Fatalf("second sync: %v", err)
+			}
+			cur, err := s.GetCurrency(ctx, "USD")
+			if err != nil {
+				t.Fatalf("GetCurrency: %v", err)
+			}
+			if cur.Rate != tt.want {
+				t.Errorf("rate after sync = %v, want %v", cur.Rate, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateCurrencyWithSameRateDoesNotPin(t *testing.T) {
+	cbr := newFakeCBR(t, map[string][2]float64{"USD": {90, 89}})
+	s := newTestService(t, cbr.URL, Options{})
+	ctx := context.Background()
+	if err := s.loadCBRData(ctx); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+
+	if _, err := s.UpdateCurrency(ctx, &model.Currency{Code: "USD", Rate: 90, Name: "US dollar", Symbol: "$"}); err != nil {
+		t.Fatalf("UpdateCurrency: %v", err)
+	}
+	if overrides, _ := s.ListOverrides(ctx); len(overrides) != 0 {
+		t.Errorf("overrides = %+v, want none when the rate is unchanged", overrides)
+	}
+}
+
+// failingUpdateRepo не даёт обновить валюту; остальное делает настоящее хранилище.
+type failingUpdateRepo struct {
+	repository.Repository
+}
+
+func (failingUpdateRepo) UpdateCurrency(context.Context, *model.Currency) error {
+	return errors.New("disk full")
+}
+
+func TestFailedUpdateRestoresOverride(t *testing.T) {
+	tests := []struct {
+		name string
+		prev *model.RateOverride // переопределение до обновления
+	}{
+		{name: "no previous override"},
+		{name: "previous override", prev: &model.RateOverride{Code: "USD", Rate: 95, Reason: "treasury"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cbr := newFakeCBR(t, map[string][2]float64{"USD": {90, 89}})
+			s := newTestService(t, cbr.URL, Options{})
+			ctx := context.Background()
+			if err := s.loadCBRData(ctx); err != nil {
+				t.Fatalf("sync: %v", err)
+			}
+			if tt.prev != nil {
+				if _, err := s.SetOverride(ctx, tt.prev); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			s.repo = failingUpdateRepo{s.repo}
+			if _, err := s.UpdateCurrency(ctx, &model.Currency{Code: "USD", Rate: 100, Name: "US dollar", Symbol: "$"}); err == nil {
+				t.Fatal("UpdateCurrency succeeded with a failing repository")
+			}
+
+			overrides, err := s.ListOverrides(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch {
+			case tt.prev == nil && len(overrides) != 0:
+				t.Errorf("overrides = %+v, want none", overrides)
+			case tt.prev != nil && (len(overrides) != 1 || overrides[0].Rate != 95 || overrides[0].Reason != "treasury"):
+				t.Errorf("overrides = %+v, want the previous override at 95", overrides)
+			}
+			if cur, _ := s.GetCurrency(ctx, "USD"); tt.prev == nil && cur.Rate != 90 {
+				t.Errorf("rate = %v, want the provider rate 90", cur.Rate)
+			}
+		})
+	}
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"currency-converter/internal/metrics"
+	"currency-converter/internal/model"
+	"currency-converter/internal/repository"
+	"currency-converter/internal/tracing"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WriteMode определяет, ждёт ли сервис подтверждения записи в хранилище.
+type WriteMode string
+
+const (
+	// WriteSync - вызов возвращается только после сохранения сущности (или по таймауту).
+	WriteSync WriteMode = "sync"
+	// WriteAsync - сущность ставится в очередь, запись выполняется в фоне.
+	WriteAsync WriteMode = "async"
+)
+
+var (
+	ErrQueueFull      = errors.New("entity queue is full")
+	ErrStoreTimeout   = errors.New("timed out waiting for entity to be persisted")
+	ErrStoreFailed    = errors.New("failed to persist entity")
+	ErrServiceStopped = errors.New("service is shutting down")
+
+	ErrInvalidCurrency   = errors.New("invalid currency data")
+	ErrCurrencyExists    = repository.ErrCurrencyExists
+	ErrInvalidConversion = errors.New("invalid conversion")
+)
+
+type Options struct {
+	WriteMode    WriteMode
+	QueueSize    int
+	StoreTimeout time.Duration
+	RatePolicy   RatePolicy
+
+	// Доставка webhook: таймаут запроса, число воркеров, попыток и начальная пауза между ними
+	WebhookTimeout      time.Duration
+	WebhookWorkers      int
+	WebhookMaxAttempts  int
+	WebhookRetryBackoff time.Duration
+}
+
+func DefaultOptions() Options {
+	return Options{
+		WriteMode:    WriteSync,
+		QueueSize:    56,
+		StoreTimeout: 5 * time.Second,
+		RatePolicy:   PolicyManual,
+
+		WebhookTimeout:      5 * time.Second,
+		WebhookWorkers:      4,
+		WebhookMaxAttempts:  6,
+		WebhookRetryBackoff: 5 * time.Second,
+	}
+}
+
+// QueueStats - срез состояния очереди записи для мониторинга backpressure.
+type QueueStats struct {
+	Mode      WriteMode `json:"mode"`
+	Depth     int       `json:"depth"`
+	Capacity  int       `json:"capacity"`
+	Enqueued  uint64    `json:"enqueued"`
+	Rejected  uint64    `json:"rejected"`
+	Stored    uint64    `json:"stored"`
+	Failed    uint64    `json:"failed"`
+	TimedOut  uint64    `json:"timed_out"`
+	Cancelled uint64    `json:"cancelled"`
+}
+
+type storeJob struct {
+	ctx        context.Context // данные для аудита; в синхронном режиме ещё и отмена запроса
+	entity     model.Entity
+	insertOnly bool       // валюта сохраняется, только если её ещё нет
+	done       chan error // nil для асинхронной записи
+}
+
+type writeQueue struct {
+	mu     sync.Mutex
+	closed bool
+	// closing закрывается первым, чтобы ждущие места в очереди сразу вышли;
+	// jobs закрывается, только когда отправителей не осталось.
+	closing chan struct{}
+	senders sync.WaitGroup
+	jobs    chan storeJob
+	drained chan struct{}
+
+	enqueued  atomic.Uint64
+	rejected  atomic.Uint64
+	stored    atomic.Uint64
+	failed    atomic.Uint64
+	timedOut  atomic.Uint64
+	cancelled atomic.Uint64
+}
+
+func newWriteQueue(size int) *writeQueue {
+	if size <= 0 {
+		size = DefaultOptions().QueueSize
+	}
+	metrics.QueueCapacity.Set(float64(size))
+	return &writeQueue{
+		closing: make(chan struct{}),
+		jobs:    make(chan storeJob, size),
+		drained: make(chan struct{}),
+	}
+}
+
+// enter регистрирует отправителя; false - очередь уже закрыта. Блокировка
+// держится только на время проверки, а не на время ожидания места.
+func (q *writeQueue) enter() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return false
+	}
+	q.senders.Add(1)
+	return true
+}
+
+// tryEnqueue ставит задачу в очередь без ожидания.
+func (q *writeQueue) tryEnqueue(job storeJob) error {
+	if !q.enter() {
+		return ErrServiceStopped
+	}
+	defer q.senders.Done()
+
+	select {
+	case <-q.closing:
+		return ErrServiceStopped
+	case q.jobs <- job:
+		q.markEnqueued()
+		return nil
+	default:
+		q.markRejected()
+		return ErrQueueFull
+	}
+}
+
+// enqueueWait ждёт свободного места в очереди не дольше timeout и пока ctx не отменён.
+func (q *writeQueue) enqueueWait(ctx context.Context, job storeJob, timeout <-chan time.Time) error {
+	if !q.enter() {
+		return ErrServiceStopped
+	}
+	defer q.senders.Done()
+
+	select {
+	case <-q.closing:
+		return ErrServiceStopped
+	case q.jobs <- job:
+		q.markEnqueued()
+		return nil
+	case <-timeout:
+		q.markRejected()
+		return ErrQueueFull
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close запрещает новые записи и будит ждущих отправителей; воркер дочитает
+// то, что уже в очереди.
+func (q *writeQueue) close() {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.closed = true
+	close(q.closing)
+	q.mu.Unlock()
+
+	q.senders.Wait()
+	close(q.jobs)
+}
+
+func (q *writeQueue) markEnqueued() {
+	q.enqueued.Add(1)
+	metrics.QueueEvents.WithLabelValues("enqueued").Inc()
+	metrics.QueueDepth.Set(float64(len(q.jobs)))
+}
+
+func (q *writeQueue) markRejected() {
+	q.rejected.Add(1)
+	metrics.QueueEvents.WithLabelValues("rejected").Inc()
+}
+
+func (q *writeQueue) markProcessed(err error) {
+	switch {
+	case isContextError(err):
+		q.cancelled.Add(1)
+		metrics.QueueEvents.WithLabelValues("cancelled").Inc()
+	case err != nil:
+		q.failed.Add(1)
+		metrics.QueueEvents.WithLabelValues("failed").Inc()
+	default:
+		q.stored.Add(1)
+		metrics.QueueEvents.WithLabelValues("stored").Inc()
+	}
+	metrics.QueueDepth.Set(float64(len(q.jobs)))
+}
+
+func (q *writeQueue) markTimedOut() {
+	q.timedOut.Add(1)
+	metrics.QueueEvents.WithLabelValues("timed_out").Inc()
+}
+
+func (q *writeQueue) stats(mode WriteMode) QueueStats {
+	return QueueStats{
+		Mode:      mode,
+		Depth:     len(q.jobs),
+		Capacity:  cap(q.jobs),
+		Enqueued:  q.enqueued.Load(),
+		Rejected:  q.rejected.Load(),
+		Stored:    q.stored.Load(),
+		Failed:    q.failed.Load(),
+		TimedOut:  q.timedOut.Load(),
+		Cancelled: q.cancelled.Load(),
+	}
+}
+
+func (s *service) processEntities() {
+	defer close(s.queue.drained)
+
+	for job := range s.queue.jobs {
+		err := s.storeEntity(job)
+		s.queue.markProcessed(err)
+		switch {
+		case isContextError(err):
+			slog.DebugContext(job.ctx, "entity store skipped: request cancelled", "error", err, "cause", context.Cause(job.ctx))
+		case err != nil:
+			slog.ErrorContext(job.ctx, "failed to store entity", "error", err)
+		}
+		if job.done != nil {
+			job.done <- err
+		}
+	}
+	slog.Info("entity queue drained")
+}
+
+func (s *service) storeEntity(job storeJob) (err error) {
+	ctx, span := tracer.Start(job.ctx, "service.storeEntity", trace.WithAttributes(
+		attribute.String("entity.type", fmt.Sprintf("%T", job.entity)),
+		attribute.Bool("insert_only", job.insertOnly),
+	))
+	defer func() { tracing.End(span, err) }()
+	job.ctx = ctx
+
+	if cur, ok := job.entity.(*model.Currency); ok && job.insertOnly {
+		return s.repo.InsertCurrency(job.ctx, cur)
+	}
+	return s.repo.Store(job.ctx, job.entity)
+}
+
+// persist сохраняет сущность согласно настроенному режиму записи.
+// В обоих режимах вызывающий ждёт места в очереди не дольше StoreTimeout;
+// в синхронном режиме дополнительно возвращается ошибка хранилища.
+func (s *service) persist(ctx context.Context, entity model.Entity) error {
+	return s.enqueue(ctx, storeJob{entity: entity})
+}
+
+// persistNew - как persist, но существующая валюта не перезаписывается.
+func (s *service) persistNew(ctx context.Context, cur *model.Currency) error {
+	return s.enqueue(ctx, storeJob{entity: cur, insertOnly: true})
+}
+
+func (s *service) enqueue(ctx context.Context, job storeJob) error {
+	if job.entity == nil {
+		return fmt.Errorf("cannot add nil entity")
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	timeout := time.NewTimer(s.opts.StoreTimeout)
+	defer timeout.Stop()
+
+	if s.opts.WriteMode == WriteAsync {
+		// Асинхронная запись переживает запрос, поэтому отмена не наследуется, а значения - да.
+		job.ctx = context.WithoutCancel(ctx)
+		return s.queue.enqueueWait(ctx, job, timeout.C)
+	}
+
+	// В синхронном режиме воркер пропустит задачу, если запрос отменят или истечёт
+	// StoreTimeout раньше, чем до неё дойдёт очередь: вызывающий уже получил ошибку.
+	jobCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	job.ctx = jobCtx
+	job.done = make(chan error, 1)
+	if err := s.queue.enqueueWait(ctx, job, timeout.C); err != nil {
+		return err
+	}
+
+	select {
+	case err := <-job.done:
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrStoreFailed, err)
+		}
+		return nil
+	case <-timeout.C:
+		cancel(ErrStoreTimeout)
+		s.queue.markTimedOut()
+		return ErrStoreTimeout
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func (s *service) QueueStats() QueueStats {
+	return s.queue.stats(s.opts.WriteMode)
+}
+
+// Shutdown закрывает очередь записи и ждёт, пока воркер сохранит оставшиеся сущности.
+func (s *service) Shutdown(ctx context.Context) error {
+	s.queue.close()
+
+	select {
+	case <-s.queue.drained:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("entity queue not drained, %d pending: %w", len(s.queue.jobs), ctx.Err())
+	}
+}
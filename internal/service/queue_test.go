@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"currency-converter/internal/model"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWriteQueueCloseWakesBlockedSenders(t *testing.T) {
+	q := newWriteQueue(1)
+	if err := q.tryEnqueue(storeJob{ctx: context.Background(), entity: &model.Currency{Code: "USD"}}); err != nil {
+		t.Fatalf("tryEnqueue: %v", err)
+	}
+
+	// Очередь полна и не читается: отправитель ждёт места до таймаута.
+	errc := make(chan error, 1)
+	go func() {
+		errc <- q.enqueueWait(context.Background(), storeJob{ctx: context.Background(), entity: &model.Currency{Code: "EUR"}}, nil)
+	}()
+	// Даём отправителю дойти до select; если он не успеет, close всё равно должен вернуться.
+	time.Sleep(20 * time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		q.close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("close is blocked by a waiting sender")
+	}
+	if err := <-errc; !errors.Is(err, ErrServiceStopped) {
+		t.Fatalf("blocked sender got %v, want ErrServiceStopped", err)
+	}
+	if err := q.tryEnqueue(storeJob{ctx: context.Background(), entity: &model.Currency{Code: "GBP"}}); !errors.Is(err, ErrServiceStopped) {
+		t.Fatalf("tryEnqueue after close got %v, want ErrServiceStopped", err)
+	}
+	// Задача, принятая до закрытия, остаётся воркеру.
+	if job, ok := <-q.jobs; !ok || job.entity.(*model.Currency).Code != "USD" {
+		t.Fatalf("queued job lost after close")
+	}
+}
+
+func TestCancelledWritesAreSkipped(t *testing.T) {
+	tests := []struct {
+		name string
+		// enqueue ставит валюту в очередь и отменяет запрос; возвращает ошибку вызывающего.
+		enqueue func(s *service, cur *model.Currency) error
+		// enqueued - дошла ли задача до очереди (иначе воркеру нечего пропускать).
+		enqueued bool
+	}{
+		{
+			name: "cancelled before enqueue",
+			enqueue: func(s *service, cur *model.Currency) error {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return s.persist(ctx, cur)
+			},
+		},
+		{
+			name: "cancelled while waiting in the queue",
+			enqueue: func(s *service, cur *model.Currency) error {
+				ctx, cancel := context.WithCancel(context.Background())
+				errc := make(chan error, 1)
+				go func() { errc <- s.persist(ctx, cur) }()
+				waitFor(t, func() bool { return s.QueueStats().Enqueued == 1 })
+				cancel()
+				return <-errc
+			},
+			enqueued: true,
+		},
+		{
+			name: "deadline while waiting in the queue",
+			enqueue: func(s *service, cur *model.Currency) error {
+				ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+				defer cancel()
+				return s.persist(ctx, cur)
+			},
+			enqueued: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newIdleService(t, "", Options{WriteMode: WriteSync, StoreTimeout: time.Minute})
+			err := tt.enqueue(s, model.NewCurrency("USD", 90, "US dollar", "$"))
+			if !isContextError(err) {
+				t.Fatalf("persist() error = %v, want a context error", err)
+			}
+
+			// Воркер стартует после отмены и должен пропустить задачу, а не записать её.
+			go s.processEntities()
+			if err := s.Shutdown(context.Background()); err != nil {
+				t.Fatalf("Shutdown: %v", err)
+			}
+			stats := s.QueueStats()
+			wantCancelled := uint64(0)
+			if tt.enqueued {
+				wantCancelled = 1
+			}
+			if stats.Cancelled != wantCancelled || stats.Stored != 0 {
+				t.Errorf("stats = %+v, want cancelled %d and nothing stored", stats, wantCancelled)
+			}
+			if curs, _ := s.repo.GetCurrencies(context.Background()); len(curs) != 0 {
+				t.Errorf("currencies = %v, want none", curs)
+			}
+		})
+	}
+}
+
+func TestTimedOutWritesAreSkipped(t *testing.T) {
+	s := newIdleService(t, "", Options{WriteMode: WriteSync, StoreTimeout: 20 * time.Millisecond})
+	if err := s.persist(context.Background(), model.NewCurrency("USD", 90, "US dollar", "$")); !errors.Is(err, ErrStoreTimeout) {
+		t.Fatalf("persist() error = %v, want %v", err, ErrStoreTimeout)
+	}
+
+	// Вызывающий уже получил таймаут, поэтому запоздавший воркер запись не выполняет.
+	go s.processEntities()
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	stats := s.QueueStats()
+	if stats.TimedOut != 1 || stats.Cancelled != 1 || stats.Stored != 0 {
+		t.Errorf("stats = %+v, want one timed out and cancelled job and nothing stored", stats)
+	}
+	if curs, _ := s.repo.GetCurrencies(context.Background()); len(curs) != 0 {
+		t.Errorf("currencies = %v, want none", curs)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
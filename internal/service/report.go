@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"currency-converter/internal/model"
+	"currency-converter/internal/tracing"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// defaultTopPairs - сколько пар в TopPairs, если лимит не задан.
+const defaultTopPairs = 5
+
+// ConversionReport сводит историю конвертаций за [from, to): оборот по парам,
+// число конвертаций по дням и самые популярные пары. Нулевые границы не
+// ограничивают период; конвертации без даты учитываются, только если период не задан.
+func (s *service) ConversionReport(ctx context.Context, from, to time.Time, top int) (_ *model.ConversionReport, err error) {
+	ctx, span := tracer.Start(ctx, "service.ConversionReport")
+	defer func() { tracing.End(span, err) }()
+
+	if top <= 0 {
+		top = defaultTopPairs
+	}
+	conversions, err := s.repo.GetConversions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.ConversionReport{From: from, To: to}
+	bounded := !from.IsZero() || !to.IsZero()
+	pairs := make(map[[2]string]*model.PairStats)
+	daily := make(map[time.Time]*model.DailyConversions)
+	var firstDay, lastDay time.Time
+
+	for _, conv := range conversions {
+		if conv.From == nil || conv.To == nil {
+			continue
+		}
+		if conv.CreatedAt.IsZero() {
+			if bounded {
+				continue
+			}
+			report.Undated++
+		} else if (!from.IsZero() && conv.CreatedAt.Before(from)) || (!to.IsZero() && !conv.CreatedAt.Before(to)) {
+			continue
+		}
+
+		volumeRUB := conv.Amount * conv.From.Rate
+		report.Count++
+		report.VolumeRUB += volumeRUB
+
+		key := [2]string{conv.From.Code, conv.To.Code}
+		p, ok := pairs[key]
+		if !ok {
+			p = &model.PairStats{From: key[0], To: key[1]}
+			pairs[key] = p
+		}
+		p.Count++
+		p.Volume += conv.Amount
+		p.Converted += conv.Result
+		p.VolumeRUB += volumeRUB
+
+		if conv.CreatedAt.IsZero() {
+			continue
+		}
+		d := conv.CreatedAt.UTC().Truncate(24 * time.Hour)
+		day, ok := daily[d]
+		if !ok {
+			day = &model.DailyConversions{Date: d}
+			daily[d] = day
+		}
+		day.Count++
+		day.VolumeRUB += volumeRUB
+		if firstDay.IsZero() || d.Before(firstDay) {
+			firstDay = d
+		}
+		if d.After(lastDay) {
+			lastDay = d
+		}
+	}
+	if report.Count > 0 {
+		report.AverageAmountRUB = report.VolumeRUB / float64(report.Count)
+	}
+
+	report.Pairs = make([]model.PairStats, 0, len(pairs))
+	for _, p := range pairs {
+		p.AverageAmount = p.Volume / float64(p.Count)
+		report.Pairs = append(report.Pairs, *p)
+	}
+	sort.Slice(report.Pairs, func(i, j int) bool {
+		a, b := report.Pairs[i], report.Pairs[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+
+	report.TopPairs = make([]model.PairStats, len(report.Pairs))
+	copy(report.TopPairs, report.Pairs)
+	sort.SliceStable(report.TopPairs, func(i, j int) bool {
+		a, b := report.TopPairs[i], report.TopPairs[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.VolumeRUB > b.VolumeRUB
+	})
+	report.TopPairs = report.TopPairs[:min(top, len(report.TopPairs))]
+
+	// Дни без конвертаций тоже попадают в ряд, чтобы его можно было сразу строить на графике.
+	report.Daily = []model.DailyConversions{}
+	for d := firstDay; !firstDay.IsZero() && !d.After(lastDay); d = d.AddDate(0, 0, 1) {
+		if day, ok := daily[d]; ok {
+			report.Daily = append(report.Daily, *day)
+		} else {
+			report.Daily = append(report.Daily, model.DailyConversions{Date: d})
+		}
+	}
+
+	slog.DebugContext(ctx, "conversion report built", "count", report.Count, "pairs", len(report.Pairs))
+	return report, nil
+}
@@ -3,68 +3,126 @@ package service
 import (
 	"context"
 	"currency-converter/internal/api/cbr"
+	"currency-converter/internal/archive"
+	"currency-converter/internal/audit"
+	"currency-converter/internal/metrics"
 	"currency-converter/internal/model"
+	"currency-converter/internal/notify"
 	"currency-converter/internal/repository"
+	"currency-converter/internal/requestid"
+	"currency-converter/internal/tracing"
+	"currency-converter/internal/validation"
 	"fmt"
-	"log"
+	"log/slog"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-type Service interface {
-	AddEntity(e model.Entity) error
+var tracer = otel.Tracer("currency-converter/internal/service")
 
-	CreateCurrency(*model.Currency) (*model.Currency, error)
-	ListCurrencies() (map[string]*model.Currency, error)
-	GetCurrency(code string) (*model.Currency, error)
-	UpdateCurrency(cur *model.Currency) (*model.Currency, error)
+// defaultMoversLimit - сколько валют в каждом списке ListMovers, если лимит не задан.
+const defaultMoversLimit = 5
 
-	ListConversions() ([]*model.Conversion, error)
-	CreateConversion(amount float64, fromCode, toCode string) (*model.Conversion, error)
+type Service interface {
+	AddEntity(ctx context.Context, e model.Entity) error
+
+	CreateCurrency(ctx context.Context, cur *model.Currency) (*model.Currency, error)
+	UpsertCurrency(ctx context.Context, cur *model.Currency) (*model.Currency, error)
+	ListCurrencies(ctx context.Context) (map[string]*model.Currency, error)
+	ListMovers(ctx context.Context, limit int) (*model.Movers, error)
+	GetCurrency(ctx context.Context, code string) (*model.Currency, error)
+	UpdateCurrency(ctx context.Context, cur *model.Currency) (*model.Currency, error)
+	DeleteCurrency(ctx context.Context, code string) error
+
+	SetOverride(ctx context.Context, o *model.RateOverride) (*model.RateOverride, error)
+	ListOverrides(ctx context.Context) ([]*model.RateOverride, error)
+	ClearOverride(ctx context.Context, code string) error
+
+	ListConversions(ctx context.Context) ([]*model.Conversion, error)
+	CreateConversion(ctx context.Context, amount float64, fromCode, toCode string) (*model.Conversion, error)
+	ConversionReport(ctx context.Context, from, to time.Time, top int) (*model.ConversionReport, error)
+
+	Export(ctx context.Context) (*archive.Archive, error)
+	Import(ctx context.Context, a *archive.Archive, opts model.ImportOptions) (*model.ImportReport, error)
+
+	CreateAlert(ctx context.Context, req *model.AlertRequest) (*model.AlertRule, error)
+	UpdateAlert(ctx context.Context, id string, req *model.AlertRequest) (*model.AlertRule, error)
+	GetAlert(ctx context.Context, id string) (*model.AlertRule, error)
+	ListAlerts(ctx context.Context) ([]*model.AlertRule, error)
+	DeleteAlert(ctx context.Context, id string) error
+
+	CreateWebhook(ctx context.Context, req *model.WebhookSubscriptionRequest) (*model.WebhookSubscription, error)
+	GetWebhook(ctx context.Context, id string) (*model.WebhookSubscription, error)
+	ListWebhooks(ctx context.Context) ([]*model.WebhookSubscription, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	ListWebhookDeliveries(ctx context.Context, id string) ([]*model.WebhookDelivery, error)
+	ListDeadLetters(ctx context.Context) ([]*model.WebhookDelivery, error)
+	RetryDelivery(ctx context.Context, id string) (*model.WebhookDelivery, error)
 }
 
 type service struct {
-	repo       repository.Repository
-	entityChan chan model.Entity
-	cbrClient  *cbr.CBRClient
+	repo      repository.Repository
+	queue     *writeQueue
+	cbrClient *cbr.CBRClient
+	notifier  *notify.Webhook
+	webhooks  *webhookQueue
+	opts      Options
+	lastSync  atomic.Int64 // unix nano последней успешной загрузки курсов ЦБ РФ
 }
 
-func NewService(repo repository.Repository) *service {
-	return &service{
-		repo:       repo,
-		entityChan: make(chan model.Entity, 56),
-		cbrClient:  cbr.NewCBRClient(),
+func NewService(repo repository.Repository, cbrClient *cbr.CBRClient, opts Options) *service {
+	if cbrClient == nil {
+		cbrClient = cbr.NewCBRClient()
 	}
-}
-
-func (s *service) processEntities(ctx context.Context) {
-	for {
-		select {
-		case <-ctx.Done():
-			log.Println("Entity processing stopped: context cancelled")
-			return
-		case entity, ok := <-s.entityChan:
-			if !ok {
-				log.Println("Entity channel closed")
-				return
-			}
-			if entity != nil {
-				err := s.repo.Store(entity)
-				if err != nil {
-					log.Printf("Failed to store entity: %v", err)
-				}
-			}
-		}
+	if opts.WriteMode == "" {
+		opts.WriteMode = DefaultOptions().WriteMode
+	}
+	if opts.StoreTimeout <= 0 {
+		opts.StoreTimeout = DefaultOptions().StoreTimeout
+	}
+	if opts.RatePolicy == "" {
+		opts.RatePolicy = DefaultOptions().RatePolicy
+	}
+	if opts.WebhookTimeout <= 0 {
+		opts.WebhookTimeout = DefaultOptions().WebhookTimeout
+	}
+	if opts.WebhookWorkers <= 0 {
+		opts.WebhookWorkers = DefaultOptions().WebhookWorkers
+	}
+	if opts.WebhookMaxAttempts <= 0 {
+		opts.WebhookMaxAttempts = DefaultOptions().WebhookMaxAttempts
+	}
+	if opts.WebhookRetryBackoff <= 0 {
+		opts.WebhookRetryBackoff = DefaultOptions().WebhookRetryBackoff
+	}
+	return &service{
+		repo:      repo,
+		queue:     newWriteQueue(opts.QueueSize),
+		cbrClient: cbrClient,
+		notifier:  notify.NewWebhook(opts.WebhookTimeout),
+		webhooks:  newWebhookQueue(opts.QueueSize),
+		opts:      opts,
 	}
 }
 
-func (s *service) syncCBRData(ctx context.Context) {
+func (s *service) syncCBRData(ctx context.Context, loads *sync.WaitGroup) {
 	ticker := time.NewTicker(time.Hour)
 	defer ticker.Stop()
 
+	expiryTicker := time.NewTicker(time.Minute)
+	defer expiryTicker.Stop()
+
 	semaphore := make(chan struct{}, 3)
 
-	if err := s.loadCBRData(ctx); err != nil {
-		log.Printf("Failed to load initial ЦБ РФ data: %v", err)
+	loadCtx := withSyncID(ctx)
+	if err := s.loadCBRData(loadCtx); err != nil {
+		slog.ErrorContext(loadCtx, "initial CBR rates load failed", "error", err)
 	}
 
 	for {
@@ -72,31 +130,44 @@ func (s *service) syncCBRData(ctx context.Context) {
 		case <-ticker.C:
 			select {
 			case semaphore <- struct{}{}:
+				loads.Add(1)
 				go func(ctx context.Context) {
 					defer func() {
+						loads.Done()
 						<-semaphore
 						if r := recover(); r != nil {
-							log.Printf("Panic recovered in data ЦБ РФ %v", r)
+							slog.ErrorContext(ctx, "panic recovered in CBR sync", "panic", r)
 						}
 					}()
 					if err := s.loadCBRData(ctx); err != nil {
-						log.Printf("Failed to sync ЦБ РФ data: %v", err)
+						slog.ErrorContext(ctx, "CBR rates sync failed", "error", err)
 					}
-				}(ctx)
+				}(withSyncID(ctx))
 			default:
-				log.Println("ЦБ РФ sync skipped: too many concurrent requests")
+				slog.WarnContext(ctx, "CBR rates sync skipped: too many concurrent loads")
 			}
+		case now := <-expiryTicker.C:
+			s.expireOverrides(ctx, now)
 		case <-ctx.Done():
-			log.Println("ЦБ РФ data sync stopped: context cancelled")
+			slog.InfoContext(ctx, "CBR rates sync stopped")
 			return
 		}
 	}
 }
 
-func (s *service) loadCBRData(ctx context.Context) error {
+// withSyncID выдаёт каждой загрузке курсов свой request ID,
+// чтобы связать её логи, записи аудита и запрос к ЦБ РФ.
+func withSyncID(ctx context.Context) context.Context {
+	return requestid.WithID(ctx, requestid.New())
+}
+
+func (s *service) loadCBRData(ctx context.Context) (err error) {
+	ctx, span := tracer.Start(ctx, "service.loadCBRData")
+	defer func() { tracing.End(span, err) }()
+
 	rates, err := s.cbrClient.GetDailyRates(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get ЦБ РФ rates: %w", err)
+		return fmt.Errorf("failed to get CBR rates: %w", err)
 	}
 
 	// ---The Russian ruble is the base currency---
@@ -108,6 +179,8 @@ func (s *service) loadCBRData(ctx context.Context) error {
 		Symbol: "₽",
 	}
 
+	previousRates := map[string]float64{"RUB": 1.0}
+
 	for code, rate := range rates.Valute {
 		rates := rate.Value / rate.Nominal
 		baseRates[code] = &model.Currency{
@@ -116,19 +189,69 @@ func (s *service) loadCBRData(ctx context.Context) error {
 			Name:   rate.Name,
 			Symbol: getCurrencySymbol(code),
 		}
+		previousRates[code] = rate.Previous / rate.Nominal
+	}
+
+	providerRates := make(map[string]float64, len(baseRates))
+	for code, cur := range baseRates {
+		providerRates[code] = cur.Rate
+	}
+	now := time.Now()
+	s.applyOverrides(ctx, baseRates, now)
+	// Вчерашний курс ЦБ РФ несопоставим с переопределённым, поэтому алерты change_pct
+	// для таких валют его не получают.
+	alertPrevious := make(map[string]float64, len(previousRates))
+	for code, cur := range baseRates {
+		cur.SetPrevious(previousRates[code])
+		if cur.Rate == providerRates[code] {
+			alertPrevious[code] = previousRates[code]
+		}
 	}
 
+	// Снимок до записи нужен, чтобы сообщить подписчикам только об изменившихся курсах.
+	stored, _ := s.repo.GetCurrencies(ctx)
+	changes := rateChanges(stored, baseRates)
+
+	ctx = audit.WithMeta(ctx, audit.Meta{Actor: "cbr", Source: audit.SourceCBRSync})
 	for _, currency := range baseRates {
-		if err := s.AddEntity(currency); err != nil {
-			log.Printf("Failed to store currency %s: %v", currency.Code, err)
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("CBR rates load interrupted: %w", err)
+		}
+		if err := s.persist(ctx, currency); err != nil {
+			slog.ErrorContext(ctx, "failed to store currency", "code", currency.Code, "error", err)
 		}
 	}
 
 	time.Sleep(time.Millisecond)
-	log.Printf("Loaded %d currencies from ЦБ РФ", len(baseRates))
+	s.lastSync.Store(time.Now().UnixNano())
+	metrics.CBRLastSuccessfulSync.SetToCurrentTime()
+	slog.InfoContext(ctx, "CBR rates loaded", "currencies", len(baseRates))
+
+	s.evaluateAlerts(ctx, baseRates, alertPrevious, now)
+	if len(changes) > 0 {
+		s.publish(ctx, model.EventRatesUpdated, model.RatesUpdated{SyncedAt: now, Changes: changes})
+	}
 	return nil
 }
 
+// rateChanges сравнивает загруженные курсы с сохранёнными; результат упорядочен по коду.
+func rateChanges(stored, loaded map[string]*model.Currency) []model.RateChange {
+	var changes []model.RateChange
+	for code, cur := range loaded {
+		change := model.RateChange{Code: code, NewRate: cur.Rate}
+		if old, ok := stored[code]; ok {
+			if old.Rate == cur.Rate {
+				continue
+			}
+			oldRate := old.Rate
+			change.OldRate = &oldRate
+		}
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Code < changes[j].Code })
+	return changes
+}
+
 func getCurrencySymbol(code string) string {
 	symbols := map[string]string{
 		"AUD": "A$",     // Австралийский доллар
@@ -196,20 +319,24 @@ func getCurrencySymbol(code string) string {
 
 func (s *service) startLogging(ctx context.Context) {
 	seen := make(map[string]bool)
-	for code := range s.repo.GetCurrencies() {
+	initial, _ := s.repo.GetCurrencies(ctx)
+	for code := range initial {
 		seen[code] = true
 	}
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("Currency monitoring stopped: context cancelled")
+			slog.InfoContext(ctx, "currency monitoring stopped")
 			return
 		case <-time.After(200 * time.Millisecond):
-			currenciesData := s.repo.GetCurrencies()
+			currenciesData, err := s.repo.GetCurrencies(ctx)
+			if err != nil {
+				continue
+			}
 			for _, cur := range currenciesData {
 				if !seen[cur.Code] {
-					log.Printf("New currency detected: %s - %s (Rate: %.2f)", cur.Code, cur.Name, cur.Rate)
+					slog.InfoContext(ctx, "new currency detected", "code", cur.Code, "name", cur.Name, "rate", cur.Rate)
 					seen[cur.Code] = true
 				}
 			}
@@ -217,103 +344,311 @@ func (s *service) startLogging(ctx context.Context) {
 	}
 }
 
-func InitService(ctx context.Context, repo repository.Repository, cbrClient *cbr.CBRClient) *service {
-	s := NewService(repo)
-
-	go s.processEntities(ctx)
-	go s.syncCBRData(ctx)
-	go s.startLogging(ctx)
-
-	log.Println("Currency converter service initialized successfully")
-	return s
+// RatesStatus сообщает число известных курсов и время последней успешной синхронизации
+// (нулевое, если сервис работает только на кэшированных данных).
+func (s *service) RatesStatus() (int, time.Time) {
+	var lastSync time.Time
+	if ns := s.lastSync.Load(); ns != 0 {
+		lastSync = time.Unix(0, ns)
+	}
+	currencies, _ := s.repo.GetCurrencies(context.Background())
+	return len(currencies), lastSync
 }
 
-func (s *service) AddEntity(entity model.Entity) error {
+// AddEntity ставит сущность в очередь записи без ожидания результата.
+func (s *service) AddEntity(ctx context.Context, entity model.Entity) error {
 	if entity == nil {
 		return fmt.Errorf("cannot add nil entity")
 	}
-	select {
-	case s.entityChan <- entity:
-		return nil
-	default:
-		return fmt.Errorf("entity channel is full - cannot process request")
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.queue.tryEnqueue(storeJob{ctx: context.WithoutCancel(ctx), entity: entity})
+}
+
+// currencyErrors проверяет, что валюта заполнена целиком: и REST, и gRPC
+// передают её полностью, частичное обновление не поддерживается. С checkCode
+// код дополнительно сверяется со справочником ISO 4217.
+func currencyErrors(cur *model.Currency, checkCode bool) validation.Errors {
+	var errs validation.Errors
+	if cur.Code == "" {
+		errs.Add("code", "currency code is required")
+	} else if checkCode {
+		if err := model.ValidateCurrencyCode(cur.Code); err != nil {
+			errs.Add("code", "%v", err)
+		}
+	}
+	if cur.Rate <= 0 {
+		errs.Add("rate", "must be greater than zero")
+	}
+	if cur.Name == "" {
+		errs.Add("name", "is required")
+	}
+	if cur.Symbol == "" {
+		errs.Add("symbol", "is required")
+	}
+	return errs
+}
+
+func validateFields(cur *model.Currency) error {
+	return currencyErrors(cur, false).Err(ErrInvalidCurrency)
+}
+
+func validateCurrency(cur *model.Currency) error {
+	return currencyErrors(cur, true).Err(ErrInvalidCurrency)
+}
+
+// CreateCurrency добавляет новую валюту; существующий код не перезаписывается.
+func (s *service) CreateCurrency(ctx context.Context, cur *model.Currency) (_ *model.Currency, err error) {
+	ctx, span := tracer.Start(ctx, "service.CreateCurrency", trace.WithAttributes(attribute.String("currency.code", cur.Code)))
+	defer func() { tracing.End(span, err) }()
+
+	if err := validateCurrency(cur); err != nil {
+		return nil, err
+	}
+	currencies, err := s.repo.GetCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := currencies[cur.Code]; exists {
+		return nil, fmt.Errorf("%w: %s", ErrCurrencyExists, cur.Code)
+	}
+	// Изменение за день считает сервис, значения клиента не принимаются.
+	cur.SetPrevious(0)
+
+	if err := s.persistNew(ctx, cur); err != nil {
+		return nil, fmt.Errorf("failed to create currency: %w", err)
 	}
+
+	slog.InfoContext(ctx, "currency created", "code", cur.Code, "name", cur.Name)
+	s.publish(ctx, model.EventCurrencyCreated, cur)
+	return cur, nil
 }
 
-func (s *service) CreateCurrency(cur *model.Currency) (*model.Currency, error) {
-	if cur.Code == "" || cur.Rate <= 0 || cur.Name == "" || cur.Symbol == "" {
-		return nil, fmt.Errorf("invalid currency data: all fields must be provided and rate must be positive")
+// UpsertCurrency создаёт валюту или перезаписывает существующую.
+func (s *service) UpsertCurrency(ctx context.Context, cur *model.Currency) (_ *model.Currency, err error) {
+	ctx, span := tracer.Start(ctx, "service.UpsertCurrency", trace.WithAttributes(attribute.String("currency.code", cur.Code)))
+	defer func() { tracing.End(span, err) }()
+
+	if err := validateCurrency(cur); err != nil {
+		return nil, err
+	}
+	currencies, err := s.repo.GetCurrencies(ctx)
+	if err != nil {
+		return nil, err
 	}
-  
-	if err := s.AddEntity(cur); err != nil {
-		return nil, fmt.Errorf("failed to create currency: %v", err)
+	cur.SetPrevious(previousRate(currencies[cur.Code]))
+
+	if err := s.persist(ctx, cur); err != nil {
+		return nil, fmt.Errorf("failed to upsert currency: %w", err)
 	}
 
-	log.Printf("Currency created successfully: %s (%s)", cur.Code, cur.Name)
+	slog.InfoContext(ctx, "currency upserted", "code", cur.Code, "name", cur.Name)
+	s.publish(ctx, model.EventCurrencyUpdated, cur)
 	return cur, nil
 }
 
-func (s *service) ListCurrencies() (map[string]*model.Currency, error) {
-	currencies := s.repo.GetCurrencies()
-	log.Printf("Retrieved %d currencies from repository", len(currencies))
+func (s *service) ListCurrencies(ctx context.Context) (_ map[string]*model.Currency, err error) {
+	ctx, span := tracer.Start(ctx, "service.ListCurrencies")
+	defer func() { tracing.End(span, err) }()
+
+	currencies, err := s.repo.GetCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "currencies listed", "count", len(currencies))
 	return currencies, nil
 }
 
-func (s *service) GetCurrency(code string) (*model.Currency, error) {
+// previousRate - предыдущий курс ЦБ РФ сохранённой валюты, 0 для новой.
+// Ручное изменение курса сравнивается с ним же, как и курс поставщика.
+func previousRate(cur *model.Currency) float64 {
+	if cur == nil {
+		return 0
+	}
+	return cur.PreviousRate
+}
+
+// ListMovers возвращает до limit валют с наибольшим ростом и падением курса за день.
+// Валюты без известного предыдущего курса и сам рубль не учитываются.
+func (s *service) ListMovers(ctx context.Context, limit int) (_ *model.Movers, err error) {
+	ctx, span := tracer.Start(ctx, "service.ListMovers")
+	defer func() { tracing.End(span, err) }()
+
+	if limit <= 0 {
+		limit = defaultMoversLimit
+	}
+	currencies, err := s.repo.GetCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	movers := &model.Movers{Gainers: []*model.Currency{}, Losers: []*model.Currency{}}
+	for _, cur := range currencies {
+		switch {
+		case cur.Code == "RUB" || cur.PreviousRate <= 0:
+		case cur.ChangePercent > 0:
+			movers.Gainers = append(movers.Gainers, cur)
+		case cur.ChangePercent < 0:
+			movers.Losers = append(movers.Losers, cur)
+		}
+	}
+	sort.Slice(movers.Gainers, func(i, j int) bool { return movers.Gainers[i].ChangePercent > movers.Gainers[j].ChangePercent })
+	sort.Slice(movers.Losers, func(i, j int) bool { return movers.Losers[i].ChangePercent < movers.Losers[j].ChangePercent })
+	movers.Gainers = movers.Gainers[:min(limit, len(movers.Gainers))]
+	movers.Losers = movers.Losers[:min(limit, len(movers.Losers))]
+	return movers, nil
+}
+
+func (s *service) GetCurrency(ctx context.Context, code string) (_ *model.Currency, err error) {
+	ctx, span := tracer.Start(ctx, "service.GetCurrency", trace.WithAttributes(attribute.String("currency.code", code)))
+	defer func() { tracing.End(span, err) }()
+
 	if code == "" {
-		return nil, fmt.Errorf("currency code cannot be empty")
+		return nil, fmt.Errorf("%w: currency code is required", ErrInvalidCurrency)
 	}
 
-	data := s.repo.GetCurrencies()
+	data, err := s.repo.GetCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
 	if cur, ok := data[code]; ok {
-		log.Printf("Currency found: %s", code)
+		slog.DebugContext(ctx, "currency found", "code", code)
 		return cur, nil
 	}
 
-	return nil, fmt.Errorf("currency '%s' not found in the system", code)
+	return nil, fmt.Errorf("%w: %s", ErrCurrencyNotFound, code)
 }
 
-func (s *service) UpdateCurrency(cur *model.Currency) (*model.Currency, error) {
-	if cur.Code == "" {
-		return nil, fmt.Errorf("currency code is required for update")
-	}
+// manualUpdateReason - причина переопределения, которое создаёт UpdateCurrency.
+const manualUpdateReason = "rate set via currency update"
+
+// UpdateCurrency заменяет данные валюты. Изменённый курс закрепляется как ручное
+// переопределение (см. SetOverride), снять его можно через ClearOverride.
+func (s *service) UpdateCurrency(ctx context.Context, cur *model.Currency) (_ *model.Currency, err error) {
+	ctx, span := tracer.Start(ctx, "service.UpdateCurrency", trace.WithAttributes(attribute.String("currency.code", cur.Code)))
+	defer func() { tracing.End(span, err) }()
 
-	err := s.repo.UpdateCurrency(cur)
+	if err := validateFields(cur); err != nil {
+		return nil, err
+	}
+	currencies, err := s.repo.GetCurrencies(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update currency '%s': %v", cur.Code, err)
+		return nil, err
+	}
+	existing, ok := currencies[cur.Code]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCurrencyNotFound, cur.Code)
+	}
+	// Новый курс записывается ручным переопределением без срока, иначе следующая
+	// синхронизация с ЦБ РФ его перезапишет. С PolicyProvider синхронизация снимает
+	// его, как и любое другое переопределение. Переопределение сохраняется до валюты,
+	// чтобы синхронизация между двумя записями не вернула курс ЦБ РФ, и откатывается,
+	// если валюту обновить не удалось.
+	restore := func() {}
+	if cur.Rate != existing.Rate {
+		overrides, err := s.repo.GetOverrides(ctx)
+		if err != nil {
+			return nil, err
+		}
+		prev := overrides[cur.Code]
+		override := &model.RateOverride{Code: cur.Code, Rate: cur.Rate, Reason: manualUpdateReason, CreatedAt: time.Now()}
+		if _, err := s.saveOverride(ctx, existing, override); err != nil {
+			return nil, err
+		}
+		restore = func() { s.restoreOverride(ctx, cur.Code, prev) }
+		ctx = audit.WithReason(ctx, "override: "+manualUpdateReason)
+	}
+	cur.SetPrevious(existing.PreviousRate)
+
+	if err := s.repo.UpdateCurrency(ctx, cur); err != nil {
+		restore()
+		return nil, fmt.Errorf("failed to update currency '%s': %w", cur.Code, err)
 	}
 
-	log.Printf("Currency updated successfully: %s", cur.Code)
+	slog.InfoContext(ctx, "currency updated", "code", cur.Code)
+	s.publish(ctx, model.EventCurrencyUpdated, cur)
 	return cur, nil
 }
 
-func (s *service) ListConversions() ([]*model.Conversion, error) {
-	conversions := s.repo.GetConversions()
-	log.Printf("Retrieved %d conversion records", len(conversions))
+// DeleteCurrency удаляет валюту вместе с её ручным переопределением курса.
+func (s *service) DeleteCurrency(ctx context.Context, code string) (err error) {
+	ctx, span := tracer.Start(ctx, "service.DeleteCurrency", trace.WithAttributes(attribute.String("currency.code", code)))
+	defer func() { tracing.End(span, err) }()
+
+	if code == "" {
+		return fmt.Errorf("%w: currency code is required", ErrInvalidCurrency)
+	}
+	currencies, err := s.repo.GetCurrencies(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := currencies[code]; !ok {
+		return fmt.Errorf("%w: %s", ErrCurrencyNotFound, code)
+	}
+
+	if err := s.repo.DeleteCurrency(ctx, code); err != nil {
+		return fmt.Errorf("failed to delete currency '%s': %w", code, err)
+	}
+	// Валюта уже удалена, поэтому её переопределение снимается и после отмены запроса.
+	cleanupCtx := context.WithoutCancel(ctx)
+	if overrides, _ := s.repo.GetOverrides(cleanupCtx); overrides[code] != nil {
+		if err := s.repo.DeleteOverride(cleanupCtx, code); err != nil {
+			slog.ErrorContext(ctx, "failed to remove override of deleted currency", "code", code, "error", err)
+		}
+	}
+
+	slog.InfoContext(ctx, "currency deleted", "code", code)
+	s.publish(ctx, model.EventCurrencyDeleted, map[string]string{"code": code})
+	return nil
+}
+
+func (s *service) ListConversions(ctx context.Context) (_ []*model.Conversion, err error) {
+	ctx, span := tracer.Start(ctx, "service.ListConversions")
+	defer func() { tracing.End(span, err) }()
+
+	conversions, err := s.repo.GetConversions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "conversions listed", "count", len(conversions))
 	return conversions, nil
 }
 
-func (s *service) CreateConversion(nominal float64, fromCode, toCode string) (*model.Conversion, error) {
+func (s *service) CreateConversion(ctx context.Context, nominal float64, fromCode, toCode string) (_ *model.Conversion, err error) {
+	ctx, span := tracer.Start(ctx, "service.CreateConversion", trace.WithAttributes(attribute.String("conversion.from", fromCode), attribute.String("conversion.to", toCode)))
+	defer func() { tracing.End(span, err) }()
+
+	var errs validation.Errors
 	if nominal <= 0 {
-		return nil, fmt.Errorf("conversion amount must be greater than zero")
+		errs.Add("amount", "must be greater than zero")
+	}
+	if fromCode == "" {
+		errs.Add("from", "source currency code is required")
+	}
+	if toCode == "" {
+		errs.Add("to", "target currency code is required")
 	}
-	if fromCode == "" || toCode == "" {
-		return nil, fmt.Errorf("source and target currency codes are required")
+	if err := errs.Err(ErrInvalidConversion); err != nil {
+		return nil, err
 	}
 
-	curs := s.repo.GetCurrencies()
+	curs, err := s.repo.GetCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
 	from, ok1 := curs[fromCode]
 	if !ok1 {
-		return nil, fmt.Errorf("source currency '%s' not found", fromCode)
+		return nil, fmt.Errorf("%w: source currency %s", ErrCurrencyNotFound, fromCode)
 	} else if from.Rate <= 0 {
-		return nil, fmt.Errorf("invalid exchange rates - both must be positive values")
+		return nil, fmt.Errorf("%w: exchange rate of %s is not positive", ErrInvalidConversion, fromCode)
 	}
-  
+
 	to, ok2 := curs[toCode]
 	if !ok2 {
-		return nil, fmt.Errorf("target currency '%s' not found", toCode)
+		return nil, fmt.Errorf("%w: target currency %s", ErrCurrencyNotFound, toCode)
 	} else if to.Rate <= 0 {
-		return nil, fmt.Errorf("invalid exchange rates - both must be positive values")
+		return nil, fmt.Errorf("%w: exchange rate of %s is not positive", ErrInvalidConversion, toCode)
 	}
 
 	nominalInRubles := nominal * from.Rate
@@ -321,10 +656,12 @@ func (s *service) CreateConversion(nominal float64, fromCode, toCode string) (*m
 
 	conv := model.NewConversion(nominal, from, to, result)
 
-	if err := s.AddEntity(conv); err != nil {
-		return nil, fmt.Errorf("failed to save conversion: %v", err)
+	if err := s.persist(ctx, conv); err != nil {
+		return nil, fmt.Errorf("failed to save conversion: %w", err)
 	}
+	metrics.Conversions.WithLabelValues(fromCode, toCode).Inc()
 
-	log.Printf("Conversion completed: %.2f %s → %.2f %s", amount, fromCode, result, toCode)
+	slog.InfoContext(ctx, "conversion completed", "amount", nominal, "from", fromCode, "to", toCode, "result", result)
+	s.publish(ctx, model.EventConversionCreated, conv)
 	return conv, nil
 }
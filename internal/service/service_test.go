@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"currency-converter/internal/api/cbr"
+	"currency-converter/internal/model"
+	"currency-converter/internal/repository"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// newTestService создаёт сервис с хранилищем во временном каталоге и запущенным
+// воркером записи; cbrURL - адрес поддельного ЦБ РФ (пустой - недоступный).
+func newTestService(t *testing.T, cbrURL string, opts Options) *service {
+	t.Helper()
+	s := newIdleService(t, cbrURL, opts)
+	go s.processEntities()
+	t.Cleanup(func() { s.Shutdown(context.Background()) })
+	return s
+}
+
+// newIdleService - как newTestService, но воркер записи не запущен: очередь
+// заполняется, пока тест сам не вызовет processEntities.
+func newIdleService(t *testing.T, cbrURL string, opts Options) *service {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("data", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if cbrURL == "" {
+		cbrURL = "http://127.0.0.1:1"
+	}
+	return NewService(repository.NewRepository(nil, nil), cbr.NewCBRClientWithURL(cbrURL), opts)
+}
+
+// fakeCBR отдаёт курсы в формате daily_json.js; курсы можно менять между загрузками.
+type fakeCBR struct {
+	*httptest.Server
+	mu    sync.Mutex
+	rates map[string][2]float64 // код -> текущий и предыдущий курс
+	// traceparent - заголовок последнего запроса.
+	traceparent string
+}
+
+func newFakeCBR(t *testing.T, rates map[string][2]float64) *fakeCBR {
+	t.Helper()
+	f := &fakeCBR{rates: rates}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.traceparent = req.Header.Get("traceparent")
+
+		resp := cbr.CBRResponse{Valute: map[string]*cbr.CurrencyRespose{}}
+		for code, r := range f.rates {
+			resp.Valute[code] = &cbr.CurrencyRespose{CharCode: code, Nominal: 1, Name: code, Value: r[0], Previous: r[1]}
+		}
+		json.NewEncoder(res).Encode(resp)
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeCBR) set(code string, rate, previous float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rates[code] = [2]float64{rate, previous}
+}
+
+func codesOf(list []*model.Currency) []string {
+	result := make([]string, 0, len(list))
+	for _, cur := range list {
+		result = append(result, cur.Code)
+	}
+	return result
+}
+
+func TestListMovers(t *testing.T) {
+	cbr := newFakeCBR(t, map[string][2]float64{
+		"USD": {90, 85},    // +5.9%
+		"EUR": {100, 98},   // +2.0%
+		"CNY": {12.1, 12},  // +0.8%
+		"GBP": {110, 115},  // -4.3%
+		"JPY": {0.6, 0.61}, // -1.6%
+		"CHF": {105, 105},  // без изменения
+		"KZT": {0.2, 0},    // предыдущий курс неизвестен
+	})
+	s := newTestService(t, cbr.URL, Options{})
+	ctx := context.Background()
+	if err := s.loadCBRData(ctx); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		limit           int
+		gainers, losers []string
+	}{
+		{name: "default limit", gainers: []string{"USD", "EUR", "CNY"}, losers: []string{"GBP", "JPY"}},
+		{name: "limit", limit: 2, gainers: []string{"USD", "EUR"}, losers: []string{"GBP", "JPY"}},
+		{name: "limit of one", limit: 1, gainers: []string{"USD"}, losers: []string{"GBP"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			movers, err := s.ListMovers(ctx, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := codesOf(movers.Gainers); !slices.Equal(got, tt.gainers) {
+				t.Errorf("gainers = %v, want %v", got, tt.gainers)
+			}
+			if got := codesOf(movers.Losers); !slices.Equal(got, tt.losers) {
+				t.Errorf("losers = %v, want %v", got, tt.losers)
+			}
+		})
+	}
+
+	// Нулевое изменение отличается от неизвестного: change выводится, previous_rate - только если известен.
+	encoded := []struct {
+		code string
+		want map[string]any
+	}{
+		{code: "CHF", want: map[string]any{"previous_rate": 105.0, "change": 0.0, "change_percent": 0.0}},
+		{code: "KZT", want: map[string]any{"change": 0.0, "change_percent": 0.0}},
+	}
+	for _, tt := range encoded {
+		cur, err := s.GetCurrency(ctx, tt.code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := json.Marshal(cur)
+		var got map[string]any
+		json.Unmarshal(data, &got)
+		for _, key := range []string{"previous_rate", "change", "change_percent"} {
+			if got[key] != tt.want[key] {
+				t.Errorf("%s: %s = %v, want %v", tt.code, key, got[key], tt.want[key])
+			}
+		}
+	}
+}
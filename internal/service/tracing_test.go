@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"currency-converter/internal/model"
+	"currency-converter/internal/tracing"
+	"strings"
+	"sync"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanExporter регистрирует провайдер один раз: глобальные трейсеры пакетов
+// привязываются к первому установленному провайдеру.
+var spanExporter = sync.OnceValue(func() *tracetest.InMemoryExporter {
+	exp := tracetest.NewInMemoryExporter()
+	tracing.NewProvider(sdktrace.WithSyncer(exp))
+	return exp
+})
+
+// startTrace сбрасывает экспортёр и открывает корневой спан теста.
+func startTrace(t *testing.T) (context.Context, trace.Span, *tracetest.InMemoryExporter) {
+	t.Helper()
+	exp := spanExporter()
+	exp.Reset()
+	ctx, root := tracer.Start(context.Background(), "test")
+	return ctx, root, exp
+}
+
+// childOf находит спан с именем name, чьим родителем является parent.
+func childOf(t *testing.T, exp *tracetest.InMemoryExporter, parent trace.SpanContext, name string) tracetest.SpanStub {
+	t.Helper()
+	var names []string
+	for _, s := range exp.GetSpans() {
+		if s.Name == name && s.Parent.SpanID() == parent.SpanID() && s.SpanContext.TraceID() == parent.TraceID() {
+			return s
+		}
+		names = append(names, s.Name)
+	}
+	t.Fatalf("no span %q under %s; recorded: %s", name, parent.SpanID(), strings.Join(names, ", "))
+	return tracetest.SpanStub{}
+}
+
+func TestCreateCurrencySpans(t *testing.T) {
+	s := newTestService(t, "", Options{WriteMode: WriteSync})
+	ctx, root, exp := startTrace(t)
+	if _, err := s.CreateCurrency(ctx, model.NewCurrency("USD", 90, "US dollar", "$")); err != nil {
+		t.Fatalf("CreateCurrency: %v", err)
+	}
+	root.End()
+
+	create := childOf(t, exp, root.SpanContext(), "service.CreateCurrency")
+	store := childOf(t, exp, create.SpanContext, "service.storeEntity")
+	write := childOf(t, exp, store.SpanContext, "repository.writeFile")
+	for _, kv := range write.Attributes {
+		if kv.Key == "file" && kv.Value.AsString() != "currency.json" {
+			t.Errorf("repository.writeFile file = %q, want currency.json", kv.Value.AsString())
+		}
+	}
+}
+
+func TestLoadCBRDataSpans(t *testing.T) {
+	cbrServer := newFakeCBR(t, map[string][2]float64{"USD": {90, 89}})
+	s := newTestService(t, cbrServer.URL, Options{WriteMode: WriteSync})
+	ctx, root, exp := startTrace(t)
+	if err := s.loadCBRData(ctx); err != nil {
+		t.Fatalf("loadCBRData: %v", err)
+	}
+	root.End()
+
+	load := childOf(t, exp, root.SpanContext(), "service.loadCBRData")
+	fetch := childOf(t, exp, load.SpanContext, "cbr.GetDailyRates")
+
+	var outbound *tracetest.SpanStub
+	for _, span := range exp.GetSpans() {
+		if span.SpanKind == trace.SpanKindClient && span.Parent.SpanID() == fetch.SpanContext.SpanID() {
+			outbound = &span
+		}
+	}
+	if outbound == nil {
+		t.Fatal("no client HTTP span under cbr.GetDailyRates")
+	}
+
+	// ЦБ РФ получает traceparent исходящего запроса: тот же трейс, родитель - клиентский спан.
+	cbrServer.mu.Lock()
+	got := cbrServer.traceparent
+	cbrServer.mu.Unlock()
+	want := "00-" + root.SpanContext().TraceID().String() + "-" + outbound.SpanContext.SpanID().String() + "-01"
+	if got != want {
+		t.Errorf("traceparent = %q, want %q", got, want)
+	}
+}
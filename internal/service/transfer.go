@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"currency-converter/internal/archive"
+	"currency-converter/internal/audit"
+	"currency-converter/internal/history"
+	"currency-converter/internal/model"
+	"currency-converter/internal/repository"
+	"currency-converter/internal/tracing"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	ErrInvalidArchive = archive.ErrInvalid
+	ErrInvalidImport  = errors.New("invalid import")
+	ErrImportConflict = errors.New("import conflicts with existing data")
+)
+
+// Export собирает валюты, историю курсов и конвертации для переноса в другое окружение.
+func (s *service) Export(ctx context.Context) (_ *archive.Archive, err error) {
+	ctx, span := tracer.Start(ctx, "service.Export")
+	defer func() { tracing.End(span, err) }()
+
+	currencies, err := s.repo.GetCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	observations, err := s.repo.GetRateHistory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conversions, err := s.repo.GetConversions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &archive.Archive{
+		Currencies:  make([]*model.Currency, 0, len(currencies)),
+		RateHistory: observations,
+		Conversions: conversions,
+	}
+	for _, cur := range currencies {
+		a.Currencies = append(a.Currencies, cur)
+	}
+	sort.Slice(a.Currencies, func(i, j int) bool { return a.Currencies[i].Code < a.Currencies[j].Code })
+
+	slog.InfoContext(ctx, "data exported", "currencies", len(a.Currencies), "rate_history", len(a.RateHistory), "conversions", len(a.Conversions))
+	return a, nil
+}
+
+// conversionKey - конвертация без указателей, чтобы находить уже импортированные.
+type conversionKey struct {
+	createdAt        int64
+	amount, result   float64
+	from, to         string
+	fromRate, toRate float64
+}
+
+func keyOf(conv *model.Conversion) conversionKey {
+	return conversionKey{
+		createdAt: conv.CreatedAt.UnixNano(),
+		amount:    conv.Amount,
+		result:    conv.Result,
+		from:      conv.From.Code,
+		to:        conv.To.Code,
+		fromRate:  conv.From.Rate,
+		toRate:    conv.To.Rate,
+	}
+}
+
+type observationKey struct {
+	code string
+	time int64
+	rate float64
+}
+
+// Import переносит архив в хранилище. Сначала проверяются все записи и ищутся
+// конфликты; при ошибках, а с ConflictFail и при конфликтах, ничего не меняется
+// и вместе с ошибкой возвращается отчёт. Записи применяются одной операцией
+// хранилища, поэтому и сбой записи не оставляет архив применённым частично.
+// История курсов и конвертации только дополняются: записи, которые уже есть, пропускаются.
+func (s *service) Import(ctx context.Context, a *archive.Archive, opts model.ImportOptions) (_ *model.ImportReport, err error) {
+	ctx, span := tracer.Start(ctx, "service.Import", trace.WithAttributes(
+		attribute.Bool("import.dry_run", opts.DryRun),
+		attribute.String("import.on_conflict", string(opts.OnConflict)),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	if opts.OnConflict == "" {
+		opts.OnConflict = model.ConflictFail
+	}
+	switch opts.OnConflict {
+	case model.ConflictFail, model.ConflictSkip, model.ConflictOverwrite:
+	default:
+		return nil, fmt.Errorf("%w: on_conflict must be one of fail, skip, overwrite", ErrInvalidImport)
+	}
+
+	report := &model.ImportReport{
+		Version:     a.Manifest.Version,
+		Format:      string(a.Manifest.Format),
+		DryRun:      opts.DryRun,
+		OnConflict:  opts.OnConflict,
+		Currencies:  model.ImportCounts{Total: len(a.Currencies)},
+		RateHistory: model.ImportCounts{Total: len(a.RateHistory)},
+		Conversions: model.ImportCounts{Total: len(a.Conversions)},
+		Conflicts:   []model.ImportIssue{},
+		Errors:      validateArchive(a),
+	}
+	if len(report.Errors) > 0 {
+		return report, fmt.Errorf("%w: %d invalid records", ErrInvalidImport, len(report.Errors))
+	}
+
+	existing, err := s.repo.GetCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var currencies []*model.Currency
+	for i, cur := range a.Currencies {
+		prev, ok := existing[cur.Code]
+		switch {
+		case !ok:
+			report.Currencies.Created++
+			currencies = append(currencies, cur)
+		case prev.Rate == cur.Rate && prev.Name == cur.Name && prev.Symbol == cur.Symbol:
+			report.Currencies.Unchanged++
+		default:
+			report.Conflicts = append(report.Conflicts, model.ImportIssue{
+				Section: "currencies",
+				Record:  i + 1,
+				Key:     cur.Code,
+				Message: fmt.Sprintf("exists as %s %q (%s) with rate %g, archive has %q (%s) with rate %g",
+					prev.Code, prev.Name, prev.Symbol, prev.Rate, cur.Name, cur.Symbol, cur.Rate),
+			})
+			if opts.OnConflict == model.ConflictOverwrite {
+				report.Currencies.Updated++
+				currencies = append(currencies, cur)
+			} else {
+				report.Currencies.Skipped++
+			}
+		}
+	}
+
+	stored, err := s.repo.GetRateHistory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seenObservations := make(map[observationKey]bool, len(stored))
+	for _, o := range stored {
+		seenObservations[observationKey{o.Code, o.Time.UnixNano(), o.Rate}] = true
+	}
+	var observations []history.Observation
+	for _, o := range a.RateHistory {
+		key := observationKey{o.Code, o.Time.UnixNano(), o.Rate}
+		if seenObservations[key] {
+			report.RateHistory.Unchanged++
+			continue
+		}
+		seenObservations[key] = true
+		report.RateHistory.Created++
+		observations = append(observations, o)
+	}
+
+	storedConversions, err := s.repo.GetConversions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seenConversions := make(map[conversionKey]bool, len(storedConversions))
+	for _, conv := range storedConversions {
+		if conv.From != nil && conv.To != nil {
+			seenConversions[keyOf(conv)] = true
+		}
+	}
+	var conversions []*model.Conversion
+	for _, conv := range a.Conversions {
+		key := keyOf(conv)
+		if seenConversions[key] {
+			report.Conversions.Unchanged++
+			continue
+		}
+		seenConversions[key] = true
+		report.Conversions.Created++
+		conversions = append(conversions, conv)
+	}
+
+	if len(report.Conflicts) > 0 && opts.OnConflict == model.ConflictFail {
+		return report, fmt.Errorf("%w: %d currencies differ", ErrImportConflict, len(report.Conflicts))
+	}
+	if opts.DryRun {
+		return report, nil
+	}
+
+	ctx = audit.WithMeta(ctx, audit.Meta{Source: audit.SourceImport, Reason: "import"})
+	for _, cur := range currencies {
+		cur.SetPrevious(cur.PreviousRate)
+	}
+	batch := repository.ImportBatch{Currencies: currencies, RateHistory: observations, Conversions: conversions}
+	if err := s.repo.Import(ctx, batch); err != nil {
+		return nil, err
+	}
+	report.Applied = true
+
+	for _, cur := range currencies {
+		event := model.EventCurrencyUpdated
+		if _, ok := existing[cur.Code]; !ok {
+			event = model.EventCurrencyCreated
+		}
+		s.publish(ctx, event, cur)
+	}
+	slog.InfoContext(ctx, "data imported",
+		"currencies_created", report.Currencies.Created, "currencies_updated", report.Currencies.Updated,
+		"rate_history", report.RateHistory.Created, "conversions", report.Conversions.Created)
+	return report, nil
+}
+
+// validateArchive проверяет записи архива теми же правилами, что и запросы API.
+func validateArchive(a *archive.Archive) []model.ImportIssue {
+	issues := []model.ImportIssue{}
+	add := func(section string, record int, key string, err error) {
+		issues = append(issues, model.ImportIssue{Section: section, Record: record, Key: key, Message: err.Error()})
+	}
+
+	codes := make(map[string]bool, len(a.Currencies))
+	for i, cur := range a.Currencies {
+		if err := validateCurrency(cur); err != nil {
+			add("currencies", i+1, cur.Code, err)
+		} else if codes[cur.Code] {
+			add("currencies", i+1, cur.Code, errors.New("duplicate currency code"))
+		}
+		codes[cur.Code] = true
+	}
+
+	for i, o := range a.RateHistory {
+		switch {
+		case o.Code == "":
+			add("rate_history", i+1, "", errors.New("currency code is required"))
+		case o.Rate <= 0:
+			add("rate_history", i+1, o.Code, errors.New("rate must be greater than zero"))
+		case o.Time.IsZero():
+			add("rate_history", i+1, o.Code, errors.New("time is required"))
+		}
+	}
+
+	for i, conv := range a.Conversions {
+		switch {
+		case conv.From == nil || conv.To == nil || conv.From.Code == "" || conv.To.Code == "":
+			add("conversions", i+1, "", errors.New("source and target currencies are required"))
+		case conv.Amount <= 0:
+			add("conversions", i+1, conv.From.Code+"/"+conv.To.Code, errors.New("amount must be greater than zero"))
+		case conv.From.Rate <= 0 || conv.To.Rate <= 0:
+			add("conversions", i+1, conv.From.Code+"/"+conv.To.Code, errors.New("exchange rates must be greater than zero"))
+		case conv.Result < 0:
+			add("conversions", i+1, conv.From.Code+"/"+conv.To.Code, errors.New("result cannot be negative"))
+		}
+	}
+	return issues
+}
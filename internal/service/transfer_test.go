@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"currency-converter/internal/api/cbr"
+	"currency-converter/internal/archive"
+	"currency-converter/internal/history"
+	"currency-converter/internal/model"
+	"currency-converter/internal/repository"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+var (
+	day1 = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 = day1.AddDate(0, 0, 1)
+)
+
+// newTransferService - сервис с историей курсов, в котором уже есть USD по 90,
+// наблюдение USD за day1 и одна конвертация; её же возвращает вторым значением.
+func newTransferService(t *testing.T) (*service, *model.Conversion) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("data", 0755); err != nil {
+		t.Fatal(err)
+	}
+	rateHistory, err := history.Open("data/rate_history.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { rateHistory.Close() })
+
+	repo := repository.NewRepository(nil, rateHistory)
+	ctx := context.Background()
+	usd := model.NewCurrency("USD", 90, "US dollar", "$")
+	conv := model.NewConversion(1, usd, usd, 1)
+	if err := repo.Import(ctx, repository.ImportBatch{
+		Currencies:  []*model.Currency{usd},
+		RateHistory: []history.Observation{{Time: day1, Code: "USD", Rate: 89}},
+		Conversions: []*model.Conversion{conv},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewService(repo, cbr.NewCBRClientWithURL("http://127.0.0.1:1"), Options{WriteMode: WriteSync})
+	go s.processEntities()
+	t.Cleanup(func() { s.Shutdown(context.Background()) })
+	return s, conv
+}
+
+// state - число валют, наблюдений и конвертаций в хранилище и курс USD.
+func state(t *testing.T, s *service) (currencies, observations, conversions int, usd float64) {
+	t.Helper()
+	ctx := context.Background()
+	curs, err := s.repo.GetCurrencies(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obs, err := s.repo.GetRateHistory(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	convs, err := s.repo.GetConversions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(curs), len(obs), len(convs), curs["USD"].Rate
+}
+
+func TestImportDryRun(t *testing.T) {
+	s, _ := newTransferService(t)
+	eur := model.NewCurrency("EUR", 100, "Euro", "€")
+	a := &archive.Archive{
+		Currencies:  []*model.Currency{eur},
+		RateHistory: []history.Observation{{Time: day2, Code: "EUR", Rate: 99}},
+		Conversions: []*model.Conversion{model.NewConversion(2, eur, eur, 2)},
+	}
+	curs, obs, convs, _ := state(t, s)
+
+	report, err := s.Import(context.Background(), a, model.ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	want := model.ImportCounts{Total: 1, Created: 1}
+	if report.Applied || report.Currencies != want || report.RateHistory != want || report.Conversions != want {
+		t.Errorf("report = %+v, want one record to create in each section and nothing applied", report)
+	}
+	if c, o, v, _ := state(t, s); c != curs || o != obs || v != convs {
+		t.Errorf("dry run changed the store: %d currencies, %d observations, %d conversions", c, o, v)
+	}
+}
+
+func TestImportConflicts(t *testing.T) {
+	tests := []struct {
+		policy  model.ConflictPolicy
+		wantErr error
+		want    model.ImportCounts
+		wantUSD float64
+	}{
+		{policy: model.ConflictFail, wantErr: ErrImportConflict, want: model.ImportCounts{Total: 3, Created: 1, Unchanged: 1, Skipped: 1}, wantUSD: 90},
+		{policy: model.ConflictSkip, want: model.ImportCounts{Total: 3, Created: 1, Unchanged: 1, Skipped: 1}, wantUSD: 90},
+		{policy: model.ConflictOverwrite, want: model.ImportCounts{Total: 3, Created: 1, Updated: 1, Unchanged: 1}, wantUSD: 95},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			s, _ := newTransferService(t)
+			if err := s.repo.Store(context.Background(), model.NewCurrency("GBP", 110, "Pound sterling", "£")); err != nil {
+				t.Fatal(err)
+			}
+			a := &archive.Archive{Currencies: []*model.Currency{
+				model.NewCurrency("USD", 95, "US dollar", "$"),
+				model.NewCurrency("EUR", 100, "Euro", "€"),
+				model.NewCurrency("GBP", 110, "Pound sterling", "£"),
+			}}
+
+			report, err := s.Import(context.Background(), a, model.ImportOptions{OnConflict: tt.policy})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Import() error = %v, want %v", err, tt.wantErr)
+			}
+			if report.Currencies != tt.want {
+				t.Errorf("currencies = %+v, want %+v", report.Currencies, tt.want)
+			}
+			if len(report.Conflicts) != 1 || report.Conflicts[0].Key != "USD" || report.Conflicts[0].Record != 1 {
+				t.Errorf("conflicts = %+v, want USD in record 1", report.Conflicts)
+			}
+
+			wantCurrencies := 3 // USD, GBP и EUR из архива
+			if tt.wantErr != nil {
+				wantCurrencies = 2
+			}
+			if curs, _, _, usd := state(t, s); curs != wantCurrencies || usd != tt.wantUSD || report.Applied != (tt.wantErr == nil) {
+				t.Errorf("%d currencies, USD at %v, applied %v; want %d, %v, %v", curs, usd, report.Applied, wantCurrencies, tt.wantUSD, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestImportDeduplicates(t *testing.T) {
+	s, stored := newTransferService(t)
+	eur := model.NewCurrency("EUR", 100, "Euro", "€")
+	fresh := model.NewConversion(2, eur, eur, 2)
+	freshCopy := *fresh
+	a := &archive.Archive{
+		RateHistory: []history.Observation{
+			{Time: day1, Code: "USD", Rate: 89}, // уже есть в истории
+			{Time: day2, Code: "USD", Rate: 91},
+			{Time: day2, Code: "USD", Rate: 91}, // повтор внутри архива
+			{Time: day2, Code: "USD", Rate: 92}, // то же время, другой курс
+		},
+		Conversions: []*model.Conversion{stored, fresh, &freshCopy},
+	}
+	_, obs, convs, _ := state(t, s)
+
+	report, err := s.Import(context.Background(), a, model.ImportOptions{})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if want := (model.ImportCounts{Total: 4, Created: 2, Unchanged: 2}); report.RateHistory != want {
+		t.Errorf("rate history = %+v, want %+v", report.RateHistory, want)
+	}
+	if want := (model.ImportCounts{Total: 3, Created: 1, Unchanged: 2}); report.Conversions != want {
+		t.Errorf("conversions = %+v, want %+v", report.Conversions, want)
+	}
+	if _, o, v, _ := state(t, s); o != obs+2 || v != convs+1 {
+		t.Errorf("store has %d observations and %d conversions, want %d and %d", o, v, obs+2, convs+1)
+	}
+
+	// Повторный импорт того же архива ничего не добавляет.
+	report, err = s.Import(context.Background(), a, model.ImportOptions{})
+	if err != nil {
+		t.Fatalf("second Import: %v", err)
+	}
+	if report.RateHistory.Created != 0 || report.Conversions.Created != 0 {
+		t.Errorf("second import created %d observations and %d conversions, want none",
+			report.RateHistory.Created, report.Conversions.Created)
+	}
+}
@@ -0,0 +1,444 @@
+package service
+
+import (
+	"context"
+	"currency-converter/internal/metrics"
+	"currency-converter/internal/model"
+	"currency-converter/internal/notify"
+	"currency-converter/internal/repository"
+	"currency-converter/internal/tracing"
+	"currency-converter/internal/validation"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxWebhookBackoff ограничивает экспоненциальную паузу между попытками доставки.
+const maxWebhookBackoff = 10 * time.Minute
+
+var (
+	ErrInvalidWebhook       = errors.New("invalid webhook subscription")
+	ErrWebhookNotFound      = repository.ErrWebhookNotFound
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrDeliveryNotRetryable = errors.New("only dead webhook deliveries can be retried")
+)
+
+func validateWebhook(req *model.WebhookSubscriptionRequest) error {
+	var errs validation.Errors
+	if !validWebhookURL(req.URL) {
+		errs.Add("url", "must be an absolute http(s) URL")
+	}
+	for i, e := range req.Events {
+		if !model.ValidWebhookEvent(e) {
+			errs.Add(fmt.Sprintf("events[%d]", i), "unknown event %q", e)
+		}
+	}
+	if req.Secret != "" && len(req.Secret) < 16 {
+		errs.Add("secret", "must be at least 16 characters")
+	}
+	return errs.Err(ErrInvalidWebhook)
+}
+
+// CreateWebhook регистрирует подписку. Секрет возвращается только в ответе на этот вызов.
+func (s *service) CreateWebhook(ctx context.Context, req *model.WebhookSubscriptionRequest) (_ *model.WebhookSubscription, err error) {
+	ctx, span := tracer.Start(ctx, "service.CreateWebhook")
+	defer func() { tracing.End(span, err) }()
+
+	if err := validateWebhook(req); err != nil {
+		return nil, err
+	}
+	sub := &model.WebhookSubscription{
+		ID:        newID(8),
+		URL:       req.URL,
+		Events:    slices.Compact(slices.Sorted(slices.Values(req.Events))),
+		Secret:    req.Secret,
+		CreatedAt: time.Now(),
+	}
+	if sub.Events == nil {
+		sub.Events = []model.WebhookEvent{}
+	}
+	if sub.Secret == "" {
+		sub.Secret = newID(32)
+	}
+	if err := s.repo.SaveWebhook(ctx, sub); err != nil {
+		return nil, fmt.Errorf("failed to save webhook subscription: %w", err)
+	}
+
+	slog.InfoContext(ctx, "webhook subscription created", "id", sub.ID, "url", sub.URL, "events", sub.Events)
+	return sub, nil
+}
+
+func (s *service) GetWebhook(ctx context.Context, id string) (_ *model.WebhookSubscription, err error) {
+	ctx, span := tracer.Start(ctx, "service.GetWebhook", trace.WithAttributes(attribute.String("webhook.id", id)))
+	defer func() { tracing.End(span, err) }()
+
+	subs, err := s.repo.GetWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sub, ok := subs[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrWebhookNotFound, id)
+	}
+	return sub.Redacted(), nil
+}
+
+func (s *service) ListWebhooks(ctx context.Context) (_ []*model.WebhookSubscription, err error) {
+	ctx, span := tracer.Start(ctx, "service.ListWebhooks")
+	defer func() { tracing.End(span, err) }()
+
+	subs, err := s.repo.GetWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.WebhookSubscription, 0, len(subs))
+	for _, sub := range subs {
+		result = append(result, sub.Redacted())
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result, nil
+}
+
+// DeleteWebhook удаляет подписку; незавершённые доставки ей больше не отправляются.
+func (s *service) DeleteWebhook(ctx context.Context, id string) (err error) {
+	ctx, span := tracer.Start(ctx, "service.DeleteWebhook", trace.WithAttributes(attribute.String("webhook.id", id)))
+	defer func() { tracing.End(span, err) }()
+
+	if err := s.repo.DeleteWebhook(ctx, id); err != nil {
+		if errors.Is(err, ErrWebhookNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to delete webhook subscription '%s': %w", id, err)
+	}
+	slog.InfoContext(ctx, "webhook subscription deleted", "id", id)
+	return nil
+}
+
+// ListWebhookDeliveries возвращает историю доставок подписки, начиная с новых.
+func (s *service) ListWebhookDeliveries(ctx context.Context, id string) (_ []*model.WebhookDelivery, err error) {
+	ctx, span := tracer.Start(ctx, "service.ListWebhookDeliveries", trace.WithAttributes(attribute.String("webhook.id", id)))
+	defer func() { tracing.End(span, err) }()
+
+	subs, err := s.repo.GetWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := subs[id]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrWebhookNotFound, id)
+	}
+	return s.filterDeliveries(ctx, func(d *model.WebhookDelivery) bool { return d.SubscriptionID == id })
+}
+
+// ListDeadLetters возвращает доставки, для которых исчерпаны все попытки, начиная с новых.
+func (s *service) ListDeadLetters(ctx context.Context) (_ []*model.WebhookDelivery, err error) {
+	ctx, span := tracer.Start(ctx, "service.ListDeadLetters")
+	defer func() { tracing.End(span, err) }()
+
+	return s.filterDeliveries(ctx, func(d *model.WebhookDelivery) bool { return d.Status == model.DeliveryDead })
+}
+
+func (s *service) filterDeliveries(ctx context.Context, keep func(*model.WebhookDelivery) bool) ([]*model.WebhookDelivery, error) {
+	deliveries, err := s.repo.GetDeliveries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.WebhookDelivery, 0)
+	for i := len(deliveries) - 1; i >= 0; i-- {
+		if keep(deliveries[i]) {
+			result = append(result, deliveries[i])
+		}
+	}
+	return result, nil
+}
+
+func (s *service) getDelivery(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	deliveries, err := s.repo.GetDeliveries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range deliveries {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrDeliveryNotFound, id)
+}
+
+// RetryDelivery заново запускает недоставленное событие с полным набором попыток.
+func (s *service) RetryDelivery(ctx context.Context, id string) (_ *model.WebhookDelivery, err error) {
+	ctx, span := tracer.Start(ctx, "service.RetryDelivery", trace.WithAttributes(attribute.String("delivery.id", id)))
+	defer func() { tracing.End(span, err) }()
+
+	existing, err := s.getDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status != model.DeliveryDead {
+		return nil, fmt.Errorf("%w: delivery %s is %s", ErrDeliveryNotRetryable, id, existing.Status)
+	}
+
+	d := *existing
+	d.Status = model.DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = nil
+	if err := s.repo.SaveDelivery(ctx, &d); err != nil {
+		return nil, fmt.Errorf("failed to save webhook delivery: %w", err)
+	}
+	s.webhooks.schedule(ctx, d.ID, 0)
+
+	slog.InfoContext(ctx, "webhook delivery requeued", "id", id, "event", d.Event)
+	return &d, nil
+}
+
+// *********************************Dispatcher*****************************************
+
+// webhookJob - рассылка нового события подписчикам, очередная попытка доставки
+// или уведомление по правилу алерта.
+type webhookJob struct {
+	ctx        context.Context
+	event      model.WebhookEvent
+	data       any
+	at         time.Time
+	deliveryID string
+	alert      *model.AlertEvent
+}
+
+// webhookQueue передаёт события воркерам доставки. Повторные попытки ждут
+// на таймерах; при остановке таймеры сбрасываются, а доставки остаются в
+// статусе pending и продолжаются после перезапуска.
+type webhookQueue struct {
+	mu      sync.Mutex
+	closed  bool
+	jobs    chan webhookJob
+	timers  map[string]*time.Timer
+	alerts  map[string]struct{} // правила, уведомление по которым ещё не обработано
+	workers sync.WaitGroup
+}
+
+func newWebhookQueue(size int) *webhookQueue {
+	if size <= 0 {
+		size = DefaultOptions().QueueSize
+	}
+	return &webhookQueue{
+		jobs:   make(chan webhookJob, size),
+		timers: make(map[string]*time.Timer),
+		alerts: make(map[string]struct{}),
+	}
+}
+
+func (q *webhookQueue) push(job webhookJob) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return false
+	}
+	select {
+	case q.jobs <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+// pushAlert ставит уведомление в очередь, если по тому же правилу нет необработанного.
+func (q *webhookQueue) pushAlert(job webhookJob) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.alerts[job.alert.Rule.ID]; q.closed || ok {
+		return false
+	}
+	select {
+	case q.jobs <- job:
+		q.alerts[job.alert.Rule.ID] = struct{}{}
+		return true
+	default:
+		return false
+	}
+}
+
+func (q *webhookQueue) alertPending(ruleID string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	_, ok := q.alerts[ruleID]
+	return ok
+}
+
+func (q *webhookQueue) alertDone(ruleID string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.alerts, ruleID)
+}
+
+// schedule ставит попытку доставки в очередь через delay.
+func (q *webhookQueue) schedule(ctx context.Context, deliveryID string, delay time.Duration) {
+	ctx = context.WithoutCancel(ctx)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return
+	}
+	if t, ok := q.timers[deliveryID]; ok {
+		t.Stop()
+	}
+	q.timers[deliveryID] = time.AfterFunc(delay, func() {
+		q.mu.Lock()
+		delete(q.timers, deliveryID)
+		q.mu.Unlock()
+
+		if !q.push(webhookJob{ctx: ctx, deliveryID: deliveryID}) {
+			// Очередь переполнена: попытка откладывается, а не теряется.
+			q.schedule(ctx, deliveryID, time.Second)
+		}
+	})
+}
+
+func (q *webhookQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return
+	}
+	q.closed = true
+	for id, t := range q.timers {
+		t.Stop()
+		delete(q.timers, id)
+	}
+	close(q.jobs)
+}
+
+// publish рассылает событие подписчикам в фоне и не задерживает запрос.
+func (s *service) publish(ctx context.Context, event model.WebhookEvent, data any) {
+	job := webhookJob{ctx: context.WithoutCancel(ctx), event: event, data: data, at: time.Now()}
+	if !s.webhooks.push(job) {
+		metrics.WebhookDeliveries.WithLabelValues(string(event), "dropped").Inc()
+		slog.WarnContext(ctx, "webhook event dropped: queue is full or stopped", "event", event)
+	}
+}
+
+func (s *service) processWebhooks() {
+	defer s.webhooks.workers.Done()
+
+	for job := range s.webhooks.jobs {
+		switch {
+		case job.alert != nil:
+			s.deliverAlert(job.ctx, job.alert)
+		case job.deliveryID != "":
+			s.attemptDelivery(job.ctx, job.deliveryID)
+		default:
+			s.fanOut(job)
+		}
+	}
+}
+
+// fanOut создаёт по доставке на каждую подходящую подписку.
+func (s *service) fanOut(job webhookJob) {
+	ctx := job.ctx
+	subs, err := s.repo.GetWebhooks(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to read webhook subscriptions", "error", err)
+		return
+	}
+
+	payload := model.WebhookPayload{ID: newID(8), Event: job.event, CreatedAt: job.at, Data: job.data}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to marshal webhook payload", "event", job.event, "error", err)
+		return
+	}
+
+	for _, sub := range subs {
+		if !sub.Wants(job.event) {
+			continue
+		}
+		d := &model.WebhookDelivery{
+			ID:             newID(8),
+			EventID:        payload.ID,
+			SubscriptionID: sub.ID,
+			URL:            sub.URL,
+			Event:          job.event,
+			Payload:        body,
+			Status:         model.DeliveryPending,
+			CreatedAt:      job.at,
+		}
+		if err := s.repo.SaveDelivery(ctx, d); err != nil {
+			slog.ErrorContext(ctx, "failed to record webhook delivery", "subscription", sub.ID, "event", job.event, "error", err)
+			continue
+		}
+		s.webhooks.schedule(ctx, d.ID, 0)
+	}
+}
+
+func (s *service) attemptDelivery(ctx context.Context, id string) {
+	ctx, span := tracer.Start(ctx, "service.attemptDelivery", trace.WithAttributes(attribute.String("delivery.id", id)))
+	var err error
+	defer func() { tracing.End(span, err) }()
+
+	existing, err := s.getDelivery(ctx, id)
+	if err != nil || existing.Status != model.DeliveryPending {
+		return
+	}
+	d := *existing
+	now := time.Now()
+	d.Attempts++
+	d.LastAttemptAt = &now
+	d.NextAttemptAt = nil
+
+	subs, _ := s.repo.GetWebhooks(ctx)
+	sub, ok := subs[d.SubscriptionID]
+	if ok {
+		header := notify.SignedHeader(d.EventID, string(d.Event), sub.Secret, d.Payload, now)
+		d.ResponseCode, err = s.notifier.Post(ctx, d.URL, d.Payload, header)
+	} else {
+		err = ErrWebhookNotFound
+	}
+
+	var delay time.Duration
+	switch {
+	case err == nil:
+		d.Status = model.DeliveryDelivered
+		d.LastError = ""
+		metrics.WebhookDeliveries.WithLabelValues(string(d.Event), "delivered").Inc()
+		slog.InfoContext(ctx, "webhook delivered", "id", d.ID, "event", d.Event, "url", d.URL, "attempts", d.Attempts)
+	case !ok || d.Attempts >= s.opts.WebhookMaxAttempts:
+		d.Status = model.DeliveryDead
+		d.LastError = err.Error()
+		metrics.WebhookDeliveries.WithLabelValues(string(d.Event), "dead").Inc()
+		slog.ErrorContext(ctx, "webhook delivery failed permanently", "id", d.ID, "event", d.Event, "url", d.URL, "attempts", d.Attempts, "error", err)
+	default:
+		delay = s.webhookBackoff(d.Attempts)
+		next := now.Add(delay)
+		d.NextAttemptAt = &next
+		d.LastError = err.Error()
+		metrics.WebhookDeliveries.WithLabelValues(string(d.Event), "retried").Inc()
+		slog.WarnContext(ctx, "webhook delivery failed, will retry", "id", d.ID, "event", d.Event, "url", d.URL, "attempts", d.Attempts, "retry_in", delay, "error", err)
+	}
+
+	if saveErr := s.repo.SaveDelivery(context.WithoutCancel(ctx), &d); saveErr != nil {
+		slog.ErrorContext(ctx, "failed to record webhook delivery attempt", "id", d.ID, "error", saveErr)
+	}
+	if d.Status == model.DeliveryPending {
+		s.webhooks.schedule(ctx, d.ID, delay)
+	}
+}
+
+// webhookBackoff удваивает паузу после каждой неудачной попытки.
+func (s *service) webhookBackoff(attempts int) time.Duration {
+	delay := s.opts.WebhookRetryBackoff
+	for i := 1; i < attempts && delay < maxWebhookBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxWebhookBackoff)
+}
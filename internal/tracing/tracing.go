@@ -0,0 +1,116 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/stats"
+)
+
+type Options struct {
+	// Endpoint - адрес OTLP/gRPC коллектора (например, http://localhost:4317).
+	// Если пуст, спаны не экспортируются, но контекст трассировки по-прежнему передаётся дальше.
+	Endpoint    string
+	ServiceName string
+	SampleRatio float64
+}
+
+// Provider - компонент, владеющий TracerProvider; при остановке досылает накопленные спаны.
+type Provider struct {
+	tp *sdktrace.TracerProvider
+}
+
+// Setup регистрирует глобальные TracerProvider и W3C-пропагаторы (traceparent, baggage).
+func Setup(ctx context.Context, opts Options) (*Provider, error) {
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(semconv.ServiceName(opts.ServiceName)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build tracing resource: %w", err)
+	}
+
+	tpOpts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	}
+	if opts.Endpoint != "" {
+		exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpointURL(opts.Endpoint))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		tpOpts = append(tpOpts, sdktrace.WithBatcher(exporter))
+	}
+
+	return NewProvider(tpOpts...), nil
+}
+
+// NewProvider регистрирует TracerProvider с заданными опциями и W3C-пропагаторы как глобальные;
+// в тестах сюда передаётся sdktrace.WithSyncer(tracetest.NewInMemoryExporter()).
+func NewProvider(opts ...sdktrace.TracerProviderOption) *Provider {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+	return &Provider{tp: tp}
+}
+
+func (p *Provider) Name() string { return "tracing" }
+
+func (p *Provider) Start(ctx context.Context) error { return nil }
+
+func (p *Provider) Stop(ctx context.Context) error {
+	return p.tp.Shutdown(ctx)
+}
+
+// End завершает спан, отмечая в нём ошибку, если она есть.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// HTTPMiddleware открывает серверный спан на каждый запрос, извлекая родительский
+// контекст из заголовков traceparent. Имя спана - шаблон маршрута из ServeMux
+// (otelhttp переименовывает спан, когда маршрут уже найден).
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "http.request",
+		otelhttp.WithSpanNameFormatter(func(operation string, req *http.Request) string {
+			if req.Pattern != "" {
+				return req.Pattern
+			}
+			return req.Method + " " + operation
+		}),
+	)
+}
+
+// Transport оборачивает исходящие HTTP-запросы клиентскими спанами и добавляет traceparent.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(base)
+}
+
+// GRPCServerHandler открывает спан на каждый gRPC-метод; контекст берётся из метаданных.
+func GRPCServerHandler() stats.Handler {
+	return otelgrpc.NewServerHandler()
+}
+
+// GRPCClientHandler передаёт контекст трассировки в метаданных исходящих вызовов.
+func GRPCClientHandler() stats.Handler {
+	return otelgrpc.NewClientHandler()
+}
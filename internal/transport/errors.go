@@ -0,0 +1,105 @@
+package transport
+
+import (
+	"context"
+	"currency-converter/internal/history"
+	"currency-converter/internal/service"
+	"errors"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+)
+
+// StatusClientClosedRequest - клиент отключился, не дождавшись ответа (как в nginx).
+const StatusClientClosedRequest = 499
+
+// Kind - класс ошибки сервиса, общий для REST и gRPC. Каждый транспорт переводит его
+// в свой код, поэтому одна и та же ошибка даёт согласованные ответы в обоих API.
+type Kind int
+
+const (
+	Internal Kind = iota
+	InvalidArgument
+	NotFound
+	AlreadyExists
+	Unprocessable
+	Unavailable
+	Timeout
+	Canceled
+)
+
+// Classify определяет класс ошибки по sentinel-ошибкам сервиса, отмене и дедлайну;
+// нераспознанные ошибки, в том числе ErrStoreFailed без доменной причины, считаются
+// внутренними. Порядок case важен: ошибка может оборачивать несколько sentinel-ошибок сразу.
+func Classify(err error) Kind {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return Canceled
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, service.ErrStoreTimeout):
+		return Timeout
+	case errors.Is(err, service.ErrQueueFull), errors.Is(err, service.ErrServiceStopped):
+		return Unavailable
+	case errors.Is(err, service.ErrCurrencyExists), errors.Is(err, service.ErrImportConflict):
+		return AlreadyExists
+	case errors.Is(err, service.ErrCurrencyNotFound), errors.Is(err, service.ErrOverrideNotFound),
+		errors.Is(err, service.ErrAlertNotFound), errors.Is(err, service.ErrWebhookNotFound),
+		errors.Is(err, service.ErrDeliveryNotFound), errors.Is(err, history.ErrNoData):
+		return NotFound
+	case errors.Is(err, service.ErrInvalidConversion), errors.Is(err, service.ErrDeliveryNotRetryable),
+		errors.Is(err, service.ErrInvalidImport):
+		return Unprocessable
+	case errors.Is(err, service.ErrInvalidCurrency), errors.Is(err, service.ErrInvalidOverride),
+		errors.Is(err, service.ErrInvalidAlert), errors.Is(err, service.ErrInvalidWebhook),
+		errors.Is(err, history.ErrInvalidCandles), errors.Is(err, service.ErrInvalidArchive):
+		return InvalidArgument
+	}
+	return Internal
+}
+
+func (k Kind) HTTPStatus() int {
+	switch k {
+	case InvalidArgument:
+		return http.StatusBadRequest
+	case NotFound:
+		return http.StatusNotFound
+	case AlreadyExists:
+		return http.StatusConflict
+	case Unprocessable:
+		return http.StatusUnprocessableEntity
+	case Unavailable:
+		return http.StatusServiceUnavailable
+	case Timeout:
+		return http.StatusGatewayTimeout
+	case Canceled:
+		return StatusClientClosedRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func (k Kind) GRPCCode() codes.Code {
+	switch k {
+	case InvalidArgument, Unprocessable:
+		return codes.InvalidArgument
+	case NotFound:
+		return codes.NotFound
+	case AlreadyExists:
+		return codes.AlreadyExists
+	case Unavailable:
+		return codes.Unavailable
+	case Timeout:
+		return codes.DeadlineExceeded
+	case Canceled:
+		return codes.Canceled
+	}
+	return codes.Internal
+}
+
+// HTTPStatus - HTTP-статус для ошибки сервиса.
+func HTTPStatus(err error) int {
+	return Classify(err).HTTPStatus()
+}
+
+// GRPCCode - gRPC-код для ошибки сервиса.
+func GRPCCode(err error) codes.Code {
+	return Classify(err).GRPCCode()
+}
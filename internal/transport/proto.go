@@ -0,0 +1,57 @@
+package transport
+
+import (
+	"currency-converter/internal/model"
+	"currency-converter/proto"
+	"sort"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Перевод model в proto нужен и gRPC-серверу, и REST-ответам в application/x-protobuf.
+
+func CurrencyToProto(cur *model.Currency) *proto.Currency {
+	if cur == nil {
+		return nil
+	}
+	return &proto.Currency{
+		Code:          cur.Code,
+		Rate:          cur.Rate,
+		Name:          cur.Name,
+		Symbol:        cur.Symbol,
+		PreviousRate:  cur.PreviousRate,
+		Change:        cur.Change,
+		ChangePercent: cur.ChangePercent,
+	}
+}
+
+// CurrenciesToProto возвращает валюты по возрастанию кода.
+func CurrenciesToProto(currencies map[string]*model.Currency) *proto.ListCurrenciesResponse {
+	result := make([]*proto.Currency, 0, len(currencies))
+	for _, cur := range currencies {
+		result = append(result, CurrencyToProto(cur))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Code < result[j].Code })
+	return &proto.ListCurrenciesResponse{Currencies: result}
+}
+
+func ConversionToProto(conv *model.Conversion) *proto.Conversion {
+	res := &proto.Conversion{
+		Amount: conv.Amount,
+		From:   CurrencyToProto(conv.From),
+		To:     CurrencyToProto(conv.To),
+		Result: conv.Result,
+	}
+	if !conv.CreatedAt.IsZero() {
+		res.CreatedAt = timestamppb.New(conv.CreatedAt)
+	}
+	return res
+}
+
+func ConversionsToProto(conversions []*model.Conversion) *proto.ListConversionsResponse {
+	result := make([]*proto.Conversion, 0, len(conversions))
+	for _, conv := range conversions {
+		result = append(result, ConversionToProto(conv))
+	}
+	return &proto.ListConversionsResponse{Conversions: result}
+}
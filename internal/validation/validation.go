@@ -0,0 +1,55 @@
+// Package validation собирает ошибки полей запроса, чтобы клиент получил их все
+// сразу, а не исправлял по одной.
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError - ошибка одного поля; Field - имя поля в JSON.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error - ошибка проверки запроса. Unwrap возвращает Kind, поэтому errors.Is
+// по-прежнему находит ошибку сервиса, например ErrInvalidCurrency.
+type Error struct {
+	Kind   error
+	Fields []FieldError
+}
+
+func (e *Error) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		if f.Field == "" {
+			parts = append(parts, f.Message)
+			continue
+		}
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+	msg := strings.Join(parts, "; ")
+	if e.Kind == nil {
+		return msg
+	}
+	return e.Kind.Error() + ": " + msg
+}
+
+func (e *Error) Unwrap() error { return e.Kind }
+
+// Errors накапливает ошибки полей в порядке проверки.
+type Errors []FieldError
+
+// Add добавляет ошибку поля; пустое field - ошибка запроса целиком.
+func (e *Errors) Add(field, format string, args ...any) {
+	*e = append(*e, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err возвращает *Error с kind или nil, если ошибок нет.
+func (e Errors) Err(kind error) error {
+	if len(e) == 0 {
+		return nil
+	}
+	return &Error{Kind: kind, Fields: e}
+}
@@ -0,0 +1,220 @@
+// Package client - Go SDK конвертера валют. Скрывает транспорт (gRPC или REST),
+// повторяет запросы при перегрузке сервера, добавляет дедлайны и учётные данные,
+// возвращает типизированные ошибки и умеет конвертировать офлайн по локальному снимку курсов.
+//
+//	c, err := client.New(client.Options{Transport: client.GRPC, Addr: "localhost:9090", APIKey: key})
+//	if err != nil { ... }
+//	defer c.Close()
+//	conv, err := c.Convert(ctx, 100, "USD", "EUR")
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type Transport string
+
+const (
+	GRPC Transport = "grpc"
+	REST Transport = "rest"
+)
+
+type Currency struct {
+	Code   string  `json:"code"`
+	Rate   float64 `json:"rate"`
+	Name   string  `json:"name"`
+	Symbol string  `json:"symbol"`
+
+	// Изменение за день к предыдущему курсу ЦБ РФ; сервер игнорирует их в SetRate.
+	PreviousRate  float64 `json:"previous_rate,omitempty"`
+	Change        float64 `json:"change,omitempty"`
+	ChangePercent float64 `json:"change_percent,omitempty"`
+}
+
+type Conversion struct {
+	Amount float64   `json:"amount"`
+	From   *Currency `json:"from"`
+	To     *Currency `json:"to"`
+	Result float64   `json:"result"`
+	// Нулевое у конвертаций, записанных до появления времени на сервере.
+	CreatedAt time.Time `json:"created_at,omitzero"`
+}
+
+type Options struct {
+	// Transport - GRPC (по умолчанию) или REST.
+	Transport Transport
+	// Addr - host:port сервера; для REST можно указать полный URL.
+	Addr string
+
+	// APIKey и Token (JWT) передаются с каждым запросом, если заданы.
+	APIKey string
+	Token  string
+
+	// TLSConfig включает TLS; nil - соединение без шифрования.
+	TLSConfig *tls.Config
+	// HTTPClient для REST; если задан, TLSConfig к нему не применяется.
+	HTTPClient *http.Client
+
+	// Timeout ограничивает одну попытку, если у контекста нет своего дедлайна. По умолчанию 10s.
+	Timeout time.Duration
+	// MaxRetries - сколько раз повторить запрос, отклонённый из-за перегрузки. По умолчанию 3, -1 отключает.
+	MaxRetries int
+	// RetryBackoff - пауза перед первым повтором, дальше удваивается. По умолчанию 200ms.
+	RetryBackoff time.Duration
+}
+
+// transport - вызовы сервера, одинаковые для gRPC и REST.
+type transport interface {
+	convert(ctx context.Context, amount float64, from, to string) (*Conversion, error)
+	listRates(ctx context.Context) ([]*Currency, error)
+	getRate(ctx context.Context, code string) (*Currency, error)
+	upsertRate(ctx context.Context, cur *Currency) (*Currency, error)
+	history(ctx context.Context) ([]*Conversion, error)
+	close() error
+}
+
+type Client struct {
+	transport transport
+	opts      Options
+
+	mu       sync.RWMutex
+	snapshot *Snapshot
+}
+
+func New(opts Options) (*Client, error) {
+	if opts.Addr == "" {
+		return nil, errors.New("client: address is required")
+	}
+	if opts.Transport == "" {
+		opts.Transport = GRPC
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = 3
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = 200 * time.Millisecond
+	}
+
+	c := &Client{opts: opts}
+	switch opts.Transport {
+	case GRPC:
+		t, err := newGRPCTransport(opts)
+		if err != nil {
+			return nil, err
+		}
+		c.transport = t
+	case REST:
+		c.transport = newRESTTransport(opts)
+	default:
+		return nil, fmt.Errorf("client: unknown transport %q", opts.Transport)
+	}
+	return c, nil
+}
+
+func (c *Client) Close() error {
+	return c.transport.close()
+}
+
+// Convert конвертирует сумму на сервере; конвертация попадает в историю.
+// Повторяется только при явном отказе сервера (перегрузка, лимит), чтобы не задвоить запись.
+func (c *Client) Convert(ctx context.Context, amount float64, from, to string) (*Conversion, error) {
+	var conv *Conversion
+	err := c.do(ctx, false, func(ctx context.Context) (err error) {
+		conv, err = c.transport.convert(ctx, amount, from, to)
+		return err
+	})
+	return conv, err
+}
+
+// ListRates возвращает все валюты, отсортированные по коду.
+func (c *Client) ListRates(ctx context.Context) ([]*Currency, error) {
+	var list []*Currency
+	err := c.do(ctx, true, func(ctx context.Context) (err error) {
+		list, err = c.transport.listRates(ctx)
+		return err
+	})
+	return list, err
+}
+
+func (c *Client) GetRate(ctx context.Context, code string) (*Currency, error) {
+	var cur *Currency
+	err := c.do(ctx, true, func(ctx context.Context) (err error) {
+		cur, err = c.transport.getRate(ctx, code)
+		return err
+	})
+	return cur, err
+}
+
+// SetRate создаёт валюту или перезаписывает существующую; нужна роль admin.
+func (c *Client) SetRate(ctx context.Context, cur *Currency) (*Currency, error) {
+	var saved *Currency
+	err := c.do(ctx, true, func(ctx context.Context) (err error) {
+		saved, err = c.transport.upsertRate(ctx, cur)
+		return err
+	})
+	return saved, err
+}
+
+func (c *Client) History(ctx context.Context) ([]*Conversion, error) {
+	var list []*Conversion
+	err := c.do(ctx, true, func(ctx context.Context) (err error) {
+		list, err = c.transport.history(ctx)
+		return err
+	})
+	return list, err
+}
+
+// do выполняет вызов с дедлайном и повторами. Перегрузку и лимит запросов можно повторять
+// всегда - сервер отклонил запрос до обработки; таймауты - только для идемпотентных вызовов.
+func (c *Client) do(ctx context.Context, idempotent bool, call func(ctx context.Context) error) error {
+	backoff := c.opts.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := c.attempt(ctx, call)
+		if err == nil || attempt >= c.opts.MaxRetries || !retryable(err, idempotent) {
+			return err
+		}
+
+		wait := backoff
+		var apiErr *Error
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > wait {
+			wait = apiErr.RetryAfter
+		}
+		backoff *= 2
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, call func(ctx context.Context) error) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.opts.Timeout)
+		defer cancel()
+	}
+	return call(ctx)
+}
+
+func retryable(err error, idempotent bool) bool {
+	switch {
+	case errors.Is(err, ErrUnavailable), errors.Is(err, ErrRateLimited):
+		return true
+	case errors.Is(err, ErrTimeout):
+		return idempotent
+	}
+	return false
+}
@@ -0,0 +1,490 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"currency-converter/proto"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// failure - отказ сервера в терминах обоих транспортов.
+type failure struct {
+	status     int
+	code       codes.Code
+	retryAfter int // секунды; только для перегрузки
+}
+
+var (
+	unavailable  = failure{status: http.StatusServiceUnavailable, code: codes.Unavailable}
+	rateLimited  = failure{status: http.StatusTooManyRequests, code: codes.ResourceExhausted}
+	timedOut     = failure{status: http.StatusGatewayTimeout, code: codes.DeadlineExceeded}
+	notFound     = failure{status: http.StatusNotFound, code: codes.NotFound}
+	invalid      = failure{status: http.StatusBadRequest, code: codes.InvalidArgument}
+	unauthorized = failure{status: http.StatusUnauthorized, code: codes.Unauthenticated}
+)
+
+// fakeServer - состояние сервера, общее для REST- и gRPC-обработчиков.
+type fakeServer struct {
+	mu        sync.Mutex
+	rates     map[string]float64
+	failures  []failure     // ответы на очередные вызовы; когда кончатся - успех
+	block     bool          // ждать отмены запроса вместо ответа
+	calls     int           // сколько вызовов дошло до сервера
+	remaining time.Duration // сколько оставалось до дедлайна в последнем вызове (только gRPC)
+}
+
+func newFakeServer(failures ...failure) *fakeServer {
+	return &fakeServer{rates: map[string]float64{"USD": 90, "EUR": 100}, failures: failures}
+}
+
+// next регистрирует вызов и возвращает отказ для него, если он запланирован.
+func (s *fakeServer) next(ctx context.Context) (failure, bool) {
+	s.mu.Lock()
+	s.calls++
+	if d, ok := ctx.Deadline(); ok {
+		s.remaining = time.Until(d)
+	}
+	block := s.block
+	var f failure
+	ok := len(s.failures) > 0
+	if ok {
+		f, s.failures = s.failures[0], s.failures[1:]
+	}
+	s.mu.Unlock()
+
+	if block {
+		<-ctx.Done()
+	}
+	return f, ok
+}
+
+func (s *fakeServer) setRate(code string, rate float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rates[code] = rate
+}
+
+func (s *fakeServer) currencies() []*Currency {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]*Currency, 0, len(s.rates))
+	for code, rate := range s.rates {
+		list = append(list, &Currency{Code: code, Rate: rate})
+	}
+	return list
+}
+
+func (s *fakeServer) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+func (s *fakeServer) convert(amount float64, from, to string) (*Currency, *Currency, float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	src, dst := s.rates[from], s.rates[to]
+	return &Currency{Code: from, Rate: src}, &Currency{Code: to, Rate: dst}, amount * src / dst
+}
+
+func (s *fakeServer) handler() http.Handler {
+	fail := func(w http.ResponseWriter, r *http.Request) bool {
+		f, ok := s.next(r.Context())
+		if !ok {
+			return false
+		}
+		if f.retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(f.retryAfter))
+		}
+		body := map[string]any{"error:": http.StatusText(f.status)}
+		if f.status == http.StatusBadRequest {
+			body["fields"] = []FieldError{{Field: "amount", Message: "must be greater than zero"}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(f.status)
+		json.NewEncoder(w).Encode(body)
+		return true
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /currencies", func(w http.ResponseWriter, r *http.Request) {
+		if fail(w, r) {
+			return
+		}
+		data := make(map[string]*Currency)
+		for _, cur := range s.currencies() {
+			data[cur.Code] = cur
+		}
+		json.NewEncoder(w).Encode(data)
+	})
+	mux.HandleFunc("POST /conversion", func(w http.ResponseWriter, r *http.Request) {
+		// Тело читаем до ответа: иначе сервер не заметит, что клиент бросил запрос.
+		var req struct {
+			Amount   float64 `json:"amount"`
+			From, To string
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if fail(w, r) {
+			return
+		}
+		from, to, result := s.convert(req.Amount, req.From, req.To)
+		json.NewEncoder(w).Encode(Conversion{Amount: req.Amount, From: from, To: to, Result: result})
+	})
+	return mux
+}
+
+type grpcServer struct {
+	proto.UnimplementedCurrencyServiceServer
+	proto.UnimplementedConversionServiceServer
+	*fakeServer
+}
+
+func (s grpcServer) fail(ctx context.Context) error {
+	f, ok := s.next(ctx)
+	if !ok {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		return nil
+	}
+	if f.retryAfter > 0 {
+		grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(f.retryAfter)))
+	}
+	st := status.New(f.code, f.code.String())
+	if f.code == codes.InvalidArgument {
+		st, _ = st.WithDetails(&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "amount", Description: "must be greater than zero"},
+		}})
+	}
+	return st.Err()
+}
+
+func (s grpcServer) ListCurrencies(ctx context.Context, _ *emptypb.Empty) (*proto.ListCurrenciesResponse, error) {
+	if err := s.fail(ctx); err != nil {
+		return nil, err
+	}
+	resp := &proto.ListCurrenciesResponse{}
+	for _, cur := range s.currencies() {
+		resp.Currencies = append(resp.Currencies, &proto.Currency{Code: cur.Code, Rate: cur.Rate})
+	}
+	return resp, nil
+}
+
+func (s grpcServer) CreateConversion(ctx context.Context, req *proto.CreateConversionRequest) (*proto.Conversion, error) {
+	if err := s.fail(ctx); err != nil {
+		return nil, err
+	}
+	from, to, result := s.convert(req.GetAmount(), req.GetFrom(), req.GetTo())
+	return &proto.Conversion{
+		Amount: req.GetAmount(),
+		From:   &proto.Currency{Code: from.Code, Rate: from.Rate},
+		To:     &proto.Currency{Code: to.Code, Rate: to.Rate},
+		Result: result,
+	}, nil
+}
+
+func newRESTClient(t *testing.T, s *fakeServer, opts Options) *Client {
+	t.Helper()
+	srv := httptest.NewServer(s.handler())
+	t.Cleanup(srv.Close)
+
+	opts.Transport, opts.Addr = REST, srv.URL
+	c, err := New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func newGRPCClient(t *testing.T, s *fakeServer, opts Options) *Client {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	proto.RegisterCurrencyServiceServer(srv, grpcServer{fakeServer: s})
+	proto.RegisterConversionServiceServer(srv, grpcServer{fakeServer: s})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	opts.Transport, opts.Addr = GRPC, "passthrough:///bufnet"
+	c, err := New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Соединение через bufconn вместо сети; New ещё не успел подключиться.
+	c.transport.close()
+	c.transport, err = newGRPCTransport(c.opts, grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+var transports = []struct {
+	name string
+	dial func(t *testing.T, s *fakeServer, opts Options) *Client
+}{
+	{name: "rest", dial: newRESTClient},
+	{name: "grpc", dial: newGRPCClient},
+}
+
+func TestRetries(t *testing.T) {
+	listRates := func(ctx context.Context, c *Client) error {
+		_, err := c.ListRates(ctx)
+		return err
+	}
+	convert := func(ctx context.Context, c *Client) error {
+		_, err := c.Convert(ctx, 90, "USD", "EUR")
+		return err
+	}
+
+	tests := []struct {
+		name      string
+		failures  []failure
+		call      func(ctx context.Context, c *Client) error
+		want      error
+		wantCalls int
+	}{
+		{name: "unavailable is retried", failures: []failure{unavailable}, call: listRates, wantCalls: 2},
+		{name: "retries run out", failures: []failure{unavailable, unavailable, unavailable}, call: listRates, want: ErrUnavailable, wantCalls: 3},
+		{name: "rate limit is retried for convert", failures: []failure{rateLimited}, call: convert, wantCalls: 2},
+		{name: "timeout is retried for reads", failures: []failure{timedOut}, call: listRates, wantCalls: 2},
+		{name: "timeout is not retried for convert", failures: []failure{timedOut}, call: convert, want: ErrTimeout, wantCalls: 1},
+		{name: "not found", failures: []failure{notFound}, call: listRates, want: ErrNotFound, wantCalls: 1},
+		{name: "invalid argument", failures: []failure{invalid}, call: convert, want: ErrInvalidArgument, wantCalls: 1},
+		{name: "unauthenticated", failures: []failure{unauthorized}, call: listRates, want: ErrUnauthenticated, wantCalls: 1},
+	}
+	for _, tr := range transports {
+		for _, tt := range tests {
+			t.Run(tr.name+"/"+tt.name, func(t *testing.T) {
+				s := newFakeServer(tt.failures...)
+				c := tr.dial(t, s, Options{MaxRetries: 2, RetryBackoff: time.Millisecond})
+
+				err := tt.call(context.Background(), c)
+				if tt.want == nil && err != nil {
+					t.Fatalf("error = %v, want success after a retry", err)
+				}
+				if tt.want != nil && !errors.Is(err, tt.want) {
+					t.Fatalf("error = %v, want %v", err, tt.want)
+				}
+				if got := s.callCount(); got != tt.wantCalls {
+					t.Errorf("server calls = %d, want %d", got, tt.wantCalls)
+				}
+			})
+		}
+	}
+}
+
+func TestErrorDetails(t *testing.T) {
+	for _, tr := range transports {
+		t.Run(tr.name, func(t *testing.T) {
+			t.Run("retry after", func(t *testing.T) {
+				s := newFakeServer(failure{status: http.StatusTooManyRequests, code: codes.ResourceExhausted, retryAfter: 7})
+				c := tr.dial(t, s, Options{MaxRetries: -1})
+
+				_, err := c.ListRates(context.Background())
+				var apiErr *Error
+				if !errors.As(err, &apiErr) || apiErr.Kind != ErrRateLimited || apiErr.RetryAfter != 7*time.Second {
+					t.Fatalf("error = %#v, want ErrRateLimited with RetryAfter 7s", err)
+				}
+			})
+			t.Run("field errors", func(t *testing.T) {
+				c := tr.dial(t, newFakeServer(invalid), Options{})
+
+				_, err := c.Convert(context.Background(), 0, "USD", "EUR")
+				var apiErr *Error
+				if !errors.As(err, &apiErr) || len(apiErr.Fields) != 1 || apiErr.Fields[0].Field != "amount" {
+					t.Fatalf("error = %#v, want a field error for amount", err)
+				}
+			})
+		})
+	}
+}
+
+func TestDeadlines(t *testing.T) {
+	for _, tr := range transports {
+		t.Run(tr.name, func(t *testing.T) {
+			t.Run("timeout bounds each attempt", func(t *testing.T) {
+				s := newFakeServer()
+				s.block = true
+				c := tr.dial(t, s, Options{Timeout: 50 * time.Millisecond, MaxRetries: 1, RetryBackoff: time.Millisecond})
+
+				if _, err := c.ListRates(context.Background()); !errors.Is(err, ErrTimeout) {
+					t.Fatalf("ListRates error = %v, want ErrTimeout", err)
+				}
+				if got := s.callCount(); got != 2 {
+					t.Errorf("server calls = %d, want 2: each attempt gets its own timeout", got)
+				}
+			})
+			t.Run("convert is not repeated after a timeout", func(t *testing.T) {
+				s := newFakeServer()
+				s.block = true
+				c := tr.dial(t, s, Options{Timeout: 50 * time.Millisecond, MaxRetries: 1, RetryBackoff: time.Millisecond})
+
+				if _, err := c.Convert(context.Background(), 1, "USD", "EUR"); !errors.Is(err, ErrTimeout) {
+					t.Fatalf("Convert error = %v, want ErrTimeout", err)
+				}
+				if got := s.callCount(); got != 1 {
+					t.Errorf("server calls = %d, want 1", got)
+				}
+			})
+		})
+	}
+
+	// До REST-сервера дедлайн не доходит, а gRPC передаёт его в grpc-timeout.
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		ctx     time.Duration // дедлайн вызывающего; 0 - нет
+		want    time.Duration
+	}{
+		{name: "client timeout", timeout: 2 * time.Second, want: 2 * time.Second},
+		{name: "caller deadline wins", timeout: 10 * time.Second, ctx: time.Second, want: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run("grpc/"+tt.name, func(t *testing.T) {
+			s := newFakeServer()
+			c := newGRPCClient(t, s, Options{Timeout: tt.timeout})
+
+			ctx := context.Background()
+			if tt.ctx > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.ctx)
+				defer cancel()
+			}
+			if _, err := c.ListRates(ctx); err != nil {
+				t.Fatal(err)
+			}
+			s.mu.Lock()
+			remaining := s.remaining
+			s.mu.Unlock()
+			if remaining <= 0 || remaining > tt.want || remaining < tt.want-time.Second/2 {
+				t.Errorf("deadline on the server = %v, want about %v", remaining, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	for _, tr := range transports {
+		t.Run(tr.name, func(t *testing.T) {
+			s := newFakeServer()
+			c := tr.dial(t, s, Options{MaxRetries: -1})
+			ctx := context.Background()
+
+			if _, err := c.ConvertOffline(90, "USD", "EUR"); !errors.Is(err, ErrNoSnapshot) {
+				t.Fatalf("ConvertOffline before Refresh error = %v, want ErrNoSnapshot", err)
+			}
+			first, err := c.Refresh(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := c.ConvertOffline(100, "USD", "EUR"); got != 90 {
+				t.Errorf("ConvertOffline = %v, want 90", got)
+			}
+
+			s.setRate("EUR", 90)
+			if got, _ := c.ConvertOffline(100, "USD", "EUR"); got != 90 {
+				t.Errorf("ConvertOffline before the next Refresh = %v, want the cached 90", got)
+			}
+			second, err := c.Refresh(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.Snapshot() != second || first.Rates["EUR"].Rate != 100 {
+				t.Error("Refresh must replace the snapshot without changing the previous one")
+			}
+			if got, _ := c.ConvertOffline(100, "USD", "EUR"); got != 100 {
+				t.Errorf("ConvertOffline after Refresh = %v, want 100", got)
+			}
+
+			// Неудачное обновление оставляет прежний снимок.
+			s.mu.Lock()
+			s.failures = []failure{unavailable}
+			s.mu.Unlock()
+			if _, err := c.Refresh(ctx); !errors.Is(err, ErrUnavailable) {
+				t.Fatalf("Refresh error = %v, want ErrUnavailable", err)
+			}
+			if c.Snapshot() != second {
+				t.Error("failed Refresh replaced the snapshot")
+			}
+		})
+	}
+}
+
+func TestWatchRates(t *testing.T) {
+	s := newFakeServer(unavailable)
+	c := newRESTClient(t, s, Options{MaxRetries: -1})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var errs, updates int
+	err := c.WatchRates(ctx, time.Millisecond,
+		func(snap *Snapshot) {
+			if updates++; updates == 2 {
+				cancel()
+			}
+		},
+		func(error) { errs++ },
+	)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("WatchRates error = %v, want context.Canceled", err)
+	}
+	if errs != 1 || updates != 2 {
+		t.Errorf("errors = %d, updates = %d; want 1 and 2", errs, updates)
+	}
+	if c.Snapshot() == nil {
+		t.Error("WatchRates did not store a snapshot")
+	}
+}
+
+func TestSnapshotConvert(t *testing.T) {
+	snap := &Snapshot{Rates: map[string]Currency{
+		"USD": {Code: "USD", Rate: 90},
+		"EUR": {Code: "EUR", Rate: 100},
+		"XXX": {Code: "XXX"},
+	}}
+	tests := []struct {
+		name     string
+		amount   float64
+		from, to string
+		want     float64
+		wantErr  error
+	}{
+		{name: "converts through the ruble", amount: 100, from: "USD", to: "EUR", want: 90},
+		{name: "missing source", amount: 1, from: "GBP", to: "EUR", wantErr: ErrNotFound},
+		{name: "missing target", amount: 1, from: "USD", to: "GBP", wantErr: ErrNotFound},
+		{name: "zero amount", amount: 0, from: "USD", to: "EUR", wantErr: ErrInvalidArgument},
+		{name: "zero rate", amount: 1, from: "XXX", to: "EUR", wantErr: ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := snap.Convert(tt.amount, tt.from, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Convert error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Convert = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Классы ошибок сервера; проверяются через errors.Is независимо от транспорта.
+var (
+	ErrInvalidArgument  = errors.New("invalid argument")
+	ErrNotFound         = errors.New("not found")
+	ErrAlreadyExists    = errors.New("already exists")
+	ErrUnauthenticated  = errors.New("unauthenticated")
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrRateLimited      = errors.New("rate limited")
+	ErrUnavailable      = errors.New("service unavailable")
+	ErrTimeout          = errors.New("timed out")
+	ErrInternal         = errors.New("internal server error")
+
+	// ErrNoSnapshot - офлайн-конвертация вызвана до первой загрузки курсов.
+	ErrNoSnapshot = errors.New("no cached rates, call Refresh or WatchRates first")
+)
+
+// FieldError - ошибка одного поля запроса; Field - имя поля в JSON.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error - ошибка, которую вернул сервер. Kind - один из Err*-классов выше.
+type Error struct {
+	Kind    error
+	Message string
+	// Fields - все ошибки проверки запроса по полям (для ErrInvalidArgument).
+	Fields []FieldError
+	// RetryAfter - через сколько сервер разрешает повторить запрос (для ErrRateLimited).
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return e.Kind.Error()
+	}
+	return e.Kind.Error() + ": " + e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func httpError(resp *http.Response, msg string, fields []FieldError) *Error {
+	e := &Error{Kind: ErrInternal, Message: msg, Fields: fields}
+	switch resp.StatusCode {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity,
+		http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType:
+		e.Kind = ErrInvalidArgument
+	case http.StatusUnauthorized:
+		e.Kind = ErrUnauthenticated
+	case http.StatusForbidden:
+		e.Kind = ErrPermissionDenied
+	case http.StatusNotFound:
+		e.Kind = ErrNotFound
+	case http.StatusConflict:
+		e.Kind = ErrAlreadyExists
+	case http.StatusTooManyRequests:
+		e.Kind = ErrRateLimited
+		e.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	case http.StatusServiceUnavailable, http.StatusBadGateway:
+		e.Kind = ErrUnavailable
+	case http.StatusGatewayTimeout:
+		e.Kind = ErrTimeout
+	}
+	return e
+}
+
+// grpcError переводит статус gRPC в *Error; ошибки без статуса возвращаются как есть.
+func grpcError(err error, header metadata.MD) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	e := &Error{Kind: ErrInternal, Message: st.Message()}
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				e.Fields = append(e.Fields, FieldError{Field: v.GetField(), Message: v.GetDescription()})
+			}
+		}
+	}
+	switch st.Code() {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		e.Kind = ErrInvalidArgument
+	case codes.Unauthenticated:
+		e.Kind = ErrUnauthenticated
+	case codes.PermissionDenied:
+		e.Kind = ErrPermissionDenied
+	case codes.NotFound:
+		e.Kind = ErrNotFound
+	case codes.AlreadyExists:
+		e.Kind = ErrAlreadyExists
+	case codes.ResourceExhausted:
+		e.Kind = ErrRateLimited
+		if values := header.Get("retry-after"); len(values) > 0 {
+			e.RetryAfter = parseRetryAfter(values[0])
+		}
+	case codes.Unavailable:
+		e.Kind = ErrUnavailable
+	case codes.DeadlineExceeded:
+		e.Kind = ErrTimeout
+	case codes.Canceled:
+		return err
+	}
+	return e
+}
+
+func parseRetryAfter(v string) time.Duration {
+	if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return 0
+}
@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"currency-converter/proto"
+	"sort"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type grpcTransport struct {
+	conn        *grpc.ClientConn
+	currencies  proto.CurrencyServiceClient
+	conversions proto.ConversionServiceClient
+	opts        Options
+}
+
+// newGRPCTransport подключается к opts.Addr; dialOpts дополняют стандартные параметры соединения.
+func newGRPCTransport(opts Options, dialOpts ...grpc.DialOption) (*grpcTransport, error) {
+	creds := insecure.NewCredentials()
+	if opts.TLSConfig != nil {
+		creds = credentials.NewTLS(opts.TLSConfig)
+	}
+	conn, err := grpc.NewClient(opts.Addr, append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, dialOpts...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &grpcTransport{
+		conn:        conn,
+		currencies:  proto.NewCurrencyServiceClient(conn),
+		conversions: proto.NewConversionServiceClient(conn),
+		opts:        opts,
+	}, nil
+}
+
+// call добавляет учётные данные в метаданные и переводит ошибку вызова в *Error.
+func (t *grpcTransport) call(ctx context.Context, fn func(ctx context.Context, opts ...grpc.CallOption) error) error {
+	if t.opts.APIKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", t.opts.APIKey)
+	}
+	if t.opts.Token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+t.opts.Token)
+	}
+	var header metadata.MD
+	if err := fn(ctx, grpc.Header(&header)); err != nil {
+		return grpcError(err, header)
+	}
+	return nil
+}
+
+func fromProtoCurrency(cur *proto.Currency) *Currency {
+	return &Currency{
+		Code:          cur.GetCode(),
+		Rate:          cur.GetRate(),
+		Name:          cur.GetName(),
+		Symbol:        cur.GetSymbol(),
+		PreviousRate:  cur.GetPreviousRate(),
+		Change:        cur.GetChange(),
+		ChangePercent: cur.GetChangePercent(),
+	}
+}
+
+func fromProtoConversion(conv *proto.Conversion) *Conversion {
+	res := &Conversion{
+		Amount: conv.GetAmount(),
+		From:   fromProtoCurrency(conv.GetFrom()),
+		To:     fromProtoCurrency(conv.GetTo()),
+		Result: conv.GetResult(),
+	}
+	if conv.GetCreatedAt() != nil {
+		res.CreatedAt = conv.GetCreatedAt().AsTime()
+	}
+	return res
+}
+
+func (t *grpcTransport) convert(ctx context.Context, amount float64, from, to string) (*Conversion, error) {
+	var conv *proto.Conversion
+	err := t.call(ctx, func(ctx context.Context, opts ...grpc.CallOption) (err error) {
+		conv, err = t.conversions.CreateConversion(ctx, &proto.CreateConversionRequest{Amount: amount, From: from, To: to}, opts...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoConversion(conv), nil
+}
+
+func (t *grpcTransport) listRates(ctx context.Context) ([]*Currency, error) {
+	var resp *proto.ListCurrenciesResponse
+	err := t.call(ctx, func(ctx context.Context, opts ...grpc.CallOption) (err error) {
+		resp, err = t.currencies.ListCurrencies(ctx, &emptypb.Empty{}, opts...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*Currency, 0, len(resp.GetCurrencies()))
+	for _, cur := range resp.GetCurrencies() {
+		list = append(list, fromProtoCurrency(cur))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+	return list, nil
+}
+
+func (t *grpcTransport) getRate(ctx context.Context, code string) (*Currency, error) {
+	var cur *proto.Currency
+	err := t.call(ctx, func(ctx context.Context, opts ...grpc.CallOption) (err error) {
+		cur, err = t.currencies.GetCurrency(ctx, &proto.Currency{Code: code}, opts...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoCurrency(cur), nil
+}
+
+func (t *grpcTransport) upsertRate(ctx context.Context, cur *Currency) (*Currency, error) {
+	req := &proto.CreateCurrencyRequest{
+		Currency: &proto.Currency{Code: cur.Code, Rate: cur.Rate, Name: cur.Name, Symbol: cur.Symbol},
+	}
+	var saved *proto.Currency
+	err := t.call(ctx, func(ctx context.Context, opts ...grpc.CallOption) (err error) {
+		saved, err = t.currencies.UpsertCurrency(ctx, req, opts...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoCurrency(saved), nil
+}
+
+func (t *grpcTransport) history(ctx context.Context) ([]*Conversion, error) {
+	var resp *proto.ListConversionsResponse
+	err := t.call(ctx, func(ctx context.Context, opts ...grpc.CallOption) (err error) {
+		resp, err = t.conversions.ListConversions(ctx, &emptypb.Empty{}, opts...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*Conversion, 0, len(resp.GetConversions()))
+	for _, conv := range resp.GetConversions() {
+		list = append(list, fromProtoConversion(conv))
+	}
+	return list, nil
+}
+
+func (t *grpcTransport) close() error {
+	return t.conn.Close()
+}
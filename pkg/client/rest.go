@@ -0,0 +1,157 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+type restTransport struct {
+	baseURL string
+	client  *http.Client
+	opts    Options
+}
+
+func newRESTTransport(opts Options) *restTransport {
+	baseURL := opts.Addr
+	if !strings.Contains(baseURL, "://") {
+		scheme := "http"
+		if opts.TLSConfig != nil {
+			scheme = "https"
+		}
+		baseURL = scheme + "://" + baseURL
+	}
+
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.TLSClientConfig = opts.TLSConfig
+		httpClient = &http.Client{Transport: t}
+	}
+	return &restTransport{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  httpClient,
+		opts:    opts,
+	}
+}
+
+func (t *restTransport) do(ctx context.Context, method, path string, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, t.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if t.opts.APIKey != "" {
+		req.Header.Set("X-API-Key", t.opts.APIKey)
+	}
+	if t.opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.opts.Token)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		switch {
+		case errors.Is(err, context.Canceled):
+			return err
+		case errors.Is(err, context.DeadlineExceeded):
+			return &Error{Kind: ErrTimeout, Message: err.Error()}
+		}
+		return &Error{Kind: ErrUnavailable, Message: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		msg, fields := errorMessage(resp.Body)
+		return httpError(resp, msg, fields)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("client: decode %s %s response: %w", method, path, err)
+	}
+	return nil
+}
+
+// errorMessage достаёт текст ошибки и ошибки полей из ответа сервера вида
+// {"error:": "...", "fields": [...]}.
+func errorMessage(r io.Reader) (string, []FieldError) {
+	data, _ := io.ReadAll(io.LimitReader(r, 64<<10))
+	var body struct {
+		Error  string       `json:"error:"`
+		Fields []FieldError `json:"fields"`
+	}
+	if json.Unmarshal(data, &body) == nil && body.Error != "" {
+		return body.Error, body.Fields
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func (t *restTransport) convert(ctx context.Context, amount float64, from, to string) (*Conversion, error) {
+	req := map[string]any{"amount": amount, "from": from, "to": to}
+	var conv Conversion
+	if err := t.do(ctx, http.MethodPost, "/conversion", req, &conv); err != nil {
+		return nil, err
+	}
+	return &conv, nil
+}
+
+func (t *restTransport) listRates(ctx context.Context) ([]*Currency, error) {
+	var data map[string]*Currency
+	if err := t.do(ctx, http.MethodGet, "/currencies", nil, &data); err != nil {
+		return nil, err
+	}
+	list := make([]*Currency, 0, len(data))
+	for _, cur := range data {
+		list = append(list, cur)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+	return list, nil
+}
+
+func (t *restTransport) getRate(ctx context.Context, code string) (*Currency, error) {
+	var cur Currency
+	if err := t.do(ctx, http.MethodGet, "/currency/"+url.PathEscape(code), nil, &cur); err != nil {
+		return nil, err
+	}
+	return &cur, nil
+}
+
+func (t *restTransport) upsertRate(ctx context.Context, cur *Currency) (*Currency, error) {
+	var saved Currency
+	if err := t.do(ctx, http.MethodPost, "/currency/upsert", cur, &saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+func (t *restTransport) history(ctx context.Context) ([]*Conversion, error) {
+	var list []*Conversion
+	if err := t.do(ctx, http.MethodGet, "/conversions", nil, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (t *restTransport) close() error {
+	t.client.CloseIdleConnections()
+	return nil
+}
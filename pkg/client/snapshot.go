@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Snapshot - курсы валют на момент загрузки. Не изменяется после создания.
+type Snapshot struct {
+	Rates     map[string]Currency
+	FetchedAt time.Time
+}
+
+// Convert пересчитывает сумму по курсам снимка так же, как сервер: через рубль.
+func (s *Snapshot) Convert(amount float64, from, to string) (float64, error) {
+	if amount <= 0 {
+		return 0, &Error{Kind: ErrInvalidArgument, Message: "amount must be greater than zero"}
+	}
+	src, ok := s.Rates[from]
+	if !ok {
+		return 0, &Error{Kind: ErrNotFound, Message: fmt.Sprintf("currency %s is not in the snapshot", from)}
+	}
+	dst, ok := s.Rates[to]
+	if !ok {
+		return 0, &Error{Kind: ErrNotFound, Message: fmt.Sprintf("currency %s is not in the snapshot", to)}
+	}
+	if src.Rate <= 0 || dst.Rate <= 0 {
+		return 0, &Error{Kind: ErrInvalidArgument, Message: "exchange rates must be positive"}
+	}
+	return amount * src.Rate / dst.Rate, nil
+}
+
+// Refresh загружает курсы с сервера и сохраняет их как текущий снимок.
+func (c *Client) Refresh(ctx context.Context) (*Snapshot, error) {
+	list, err := c.ListRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snap := &Snapshot{Rates: make(map[string]Currency, len(list)), FetchedAt: time.Now()}
+	for _, cur := range list {
+		snap.Rates[cur.Code] = *cur
+	}
+
+	c.mu.Lock()
+	c.snapshot = snap
+	c.mu.Unlock()
+	return snap, nil
+}
+
+// Snapshot возвращает последний загруженный снимок или nil, если курсов ещё нет.
+func (c *Client) Snapshot() *Snapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.snapshot
+}
+
+// ConvertOffline конвертирует по локальному снимку без обращения к серверу
+// и без записи в историю. Результат тем точнее, чем свежее снимок.
+func (c *Client) ConvertOffline(amount float64, from, to string) (float64, error) {
+	snap := c.Snapshot()
+	if snap == nil {
+		return 0, ErrNoSnapshot
+	}
+	return snap.Convert(amount, from, to)
+}
+
+// WatchRates обновляет снимок каждые interval, пока не отменён ctx. onUpdate (если задан)
+// вызывается после каждой успешной загрузки, onError - при ошибке; старый снимок при этом сохраняется.
+// Первая загрузка выполняется сразу.
+func (c *Client) WatchRates(ctx context.Context, interval time.Duration, onUpdate func(*Snapshot), onError func(error)) error {
+	if interval <= 0 {
+		return fmt.Errorf("client: watch interval must be positive")
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		snap, err := c.Refresh(ctx)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			if onError != nil {
+				onError(err)
+			}
+		case err == nil && onUpdate != nil:
+			onUpdate(snap)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -23,11 +24,15 @@ const (
 )
 
 type Currency struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
-	Rate          float64                `protobuf:"fixed64,2,opt,name=rate,proto3" json:"rate,omitempty"`
-	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
-	Symbol        string                 `protobuf:"bytes,4,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Code   string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Rate   float64                `protobuf:"fixed64,2,opt,name=rate,proto3" json:"rate,omitempty"`
+	Name   string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Symbol string                 `protobuf:"bytes,4,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	// Изменение за день к предыдущему курсу ЦБ РФ; только в ответах
+	PreviousRate  float64 `protobuf:"fixed64,5,opt,name=previous_rate,json=previousRate,proto3" json:"previous_rate,omitempty"`
+	Change        float64 `protobuf:"fixed64,6,opt,name=change,proto3" json:"change,omitempty"`
+	ChangePercent float64 `protobuf:"fixed64,7,opt,name=change_percent,json=changePercent,proto3" json:"change_percent,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -90,12 +95,34 @@ func (x *Currency) GetSymbol() string {
 	return ""
 }
 
+func (x *Currency) GetPreviousRate() float64 {
+	if x != nil {
+		return x.PreviousRate
+	}
+	return 0
+}
+
+func (x *Currency) GetChange() float64 {
+	if x != nil {
+		return x.Change
+	}
+	return 0
+}
+
+func (x *Currency) GetChangePercent() float64 {
+	if x != nil {
+		return x.ChangePercent
+	}
+	return 0
+}
+
 type Conversion struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Amount        float64                `protobuf:"fixed64,1,opt,name=amount,proto3" json:"amount,omitempty"`
 	From          *Currency              `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
 	To            *Currency              `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
 	Result        float64                `protobuf:"fixed64,4,opt,name=result,proto3" json:"result,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // не заполнено у старых записей
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -158,6 +185,13 @@ func (x *Conversion) GetResult() float64 {
 	return 0
 }
 
+func (x *Conversion) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
 type CreateCurrencyRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Currency      *Currency              `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
@@ -246,6 +280,102 @@ func (x *ListCurrenciesResponse) GetCurrencies() []*Currency {
 	return nil
 }
 
+type ListMoversRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"` // по умолчанию 5
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMoversRequest) Reset() {
+	*x = ListMoversRequest{}
+	mi := &file_proto_entities_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMoversRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMoversRequest) ProtoMessage() {}
+
+func (x *ListMoversRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_entities_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMoversRequest.ProtoReflect.Descriptor instead.
+func (*ListMoversRequest) Descriptor() ([]byte, []int) {
+	return file_proto_entities_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListMoversRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListMoversResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Gainers       []*Currency            `protobuf:"bytes,1,rep,name=gainers,proto3" json:"gainers,omitempty"`
+	Losers        []*Currency            `protobuf:"bytes,2,rep,name=losers,proto3" json:"losers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMoversResponse) Reset() {
+	*x = ListMoversResponse{}
+	mi := &file_proto_entities_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMoversResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMoversResponse) ProtoMessage() {}
+
+func (x *ListMoversResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_entities_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMoversResponse.ProtoReflect.Descriptor instead.
+func (*ListMoversResponse) Descriptor() ([]byte, []int) {
+	return file_proto_entities_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListMoversResponse) GetGainers() []*Currency {
+	if x != nil {
+		return x.Gainers
+	}
+	return nil
+}
+
+func (x *ListMoversResponse) GetLosers() []*Currency {
+	if x != nil {
+		return x.Losers
+	}
+	return nil
+}
+
 type CreateConversionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Amount        float64                `protobuf:"fixed64,1,opt,name=amount,proto3" json:"amount,omitempty"`
@@ -257,7 +387,7 @@ type CreateConversionRequest struct {
 
 func (x *CreateConversionRequest) Reset() {
 	*x = CreateConversionRequest{}
-	mi := &file_proto_entities_proto_msgTypes[4]
+	mi := &file_proto_entities_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -269,7 +399,7 @@ func (x *CreateConversionRequest) String() string {
 func (*CreateConversionRequest) ProtoMessage() {}
 
 func (x *CreateConversionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_entities_proto_msgTypes[4]
+	mi := &file_proto_entities_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -282,7 +412,7 @@ func (x *CreateConversionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateConversionRequest.ProtoReflect.Descriptor instead.
 func (*CreateConversionRequest) Descriptor() ([]byte, []int) {
-	return file_proto_entities_proto_rawDescGZIP(), []int{4}
+	return file_proto_entities_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *CreateConversionRequest) GetAmount() float64 {
@@ -315,7 +445,7 @@ type ListConversionsResponse struct {
 
 func (x *ListConversionsResponse) Reset() {
 	*x = ListConversionsResponse{}
-	mi := &file_proto_entities_proto_msgTypes[5]
+	mi := &file_proto_entities_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -327,7 +457,7 @@ func (x *ListConversionsResponse) String() string {
 func (*ListConversionsResponse) ProtoMessage() {}
 
 func (x *ListConversionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_entities_proto_msgTypes[5]
+	mi := &file_proto_entities_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -340,7 +470,7 @@ func (x *ListConversionsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListConversionsResponse.ProtoReflect.Descriptor instead.
 func (*ListConversionsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_entities_proto_rawDescGZIP(), []int{5}
+	return file_proto_entities_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *ListConversionsResponse) GetConversions() []*Conversion {
@@ -350,42 +480,1190 @@ func (x *ListConversionsResponse) GetConversions() []*Conversion {
 	return nil
 }
 
-var File_proto_entities_proto protoreflect.FileDescriptor
+type AlertRule struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Base            string                 `protobuf:"bytes,2,opt,name=base,proto3" json:"base,omitempty"`
+	Quote           string                 `protobuf:"bytes,3,opt,name=quote,proto3" json:"quote,omitempty"`
+	Condition       string                 `protobuf:"bytes,4,opt,name=condition,proto3" json:"condition,omitempty"` // above, below или change_pct
+	Threshold       float64                `protobuf:"fixed64,5,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	CooldownSeconds int64                  `protobuf:"varint,6,opt,name=cooldown_seconds,json=cooldownSeconds,proto3" json:"cooldown_seconds,omitempty"`
+	WebhookUrl      string                 `protobuf:"bytes,7,opt,name=webhook_url,json=webhookUrl,proto3" json:"webhook_url,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastFiredAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=last_fired_at,json=lastFiredAt,proto3" json:"last_fired_at,omitempty"`
+	LastRate        float64                `protobuf:"fixed64,10,opt,name=last_rate,json=lastRate,proto3" json:"last_rate,omitempty"` // курс пары при последней проверке
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
 
-const file_proto_entities_proto_rawDesc = "" +
-	"\n" +
-	"\x14proto/entities.proto\x12\x11CurrencyConverter\x1a\x1bgoogle/protobuf/empty.proto\"^\n" +
-	"\bCurrency\x12\x12\n" +
-	"\x04code\x18\x01 \x01(\tR\x04code\x12\x12\n" +
-	"\x04rate\x18\x02 \x01(\x01R\x04rate\x12\x12\n" +
-	"\x04name\x18\x03 \x01(\tR\x04name\x12\x16\n" +
-	"\x06symbol\x18\x04 \x01(\tR\x06symbol\"\x9a\x01\n" +
-	"\n" +
-	"Conversion\x12\x16\n" +
-	"\x06amount\x18\x01 \x01(\x01R\x06amount\x12/\n" +
-	"\x04from\x18\x02 \x01(\v2\x1b.CurrencyConverter.CurrencyR\x04from\x12+\n" +
-	"\x02to\x18\x03 \x01(\v2\x1b.CurrencyConverter.CurrencyR\x02to\x12\x16\n" +
-	"\x06result\x18\x04 \x01(\x01R\x06result\"P\n" +
-	"\x15CreateCurrencyRequest\x127\n" +
-	"\bcurrency\x18\x01 \x01(\v2\x1b.CurrencyConverter.CurrencyR\bcurrency\"U\n" +
-	"\x16ListCurrenciesResponse\x12;\n" +
-	"\n" +
-	"currencies\x18\x01 \x03(\v2\x1b.CurrencyConverter.CurrencyR\n" +
-	"currencies\"U\n" +
-	"\x17CreateConversionRequest\x12\x16\n" +
-	"\x06amount\x18\x01 \x01(\x01R\x06amount\x12\x12\n" +
-	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
-	"\x02to\x18\x03 \x01(\tR\x02to\"Z\n" +
-	"\x17ListConversionsResponse\x12?\n" +
-	"\vconversions\x18\x01 \x03(\v2\x1d.CurrencyConverter.ConversionR\vconversions2\xd4\x02\n" +
-	"\x0fCurrencyService\x12W\n" +
-	"\x0eCreateCurrency\x12(.CurrencyConverter.CreateCurrencyRequest\x1a\x1b.CurrencyConverter.Currency\x12G\n" +
-	"\vGetCurrency\x12\x1b.CurrencyConverter.Currency\x1a\x1b.CurrencyConverter.Currency\x12J\n" +
-	"\x0eUpdateCurrency\x12\x1b.CurrencyConverter.Currency\x1a\x1b.CurrencyConverter.Currency\x12S\n" +
-	"\x0eListCurrencies\x12\x16.google.protobuf.Empty\x1a).CurrencyConverter.ListCurrenciesResponse2\xc9\x01\n" +
-	"\x11ConversionService\x12]\n" +
-	"\x10CreateConversion\x12*.CurrencyConverter.CreateConversionRequest\x1a\x1d.CurrencyConverter.Conversion\x12U\n" +
-	"\x0fListConversions\x12\x16.google.protobuf.Empty\x1a*.CurrencyConverter.ListConversionsResponseB)Z'currency-converter/internal/proto;protob\x06proto3"
+func (x *AlertRule) Reset() {
+	*x = AlertRule{}
+	mi := &file_proto_entities_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertRule) ProtoMessage() {}
+
+func (x *AlertRule) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_entities_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertRule.ProtoReflect.Descriptor instead.
+func (*AlertRule) Descriptor() ([]byte, []int) {
+	return file_proto_entities_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AlertRule) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AlertRule) GetBase() string {
+	if x != nil {
+		return x.Base
+	}
+	return ""
+}
+
+func (x *AlertRule) GetQuote() string {
+	if x != nil {
+		return x.Quote
+	}
+	return ""
+}
+
+func (x *AlertRule) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+func (x *AlertRule) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *AlertRule) GetCooldownSeconds() int64 {
+	if x != nil {
+		return x.CooldownSeconds
+	}
+	return 0
+}
+
+func (x *AlertRule) GetWebhookUrl() string {
+	if x != nil {
+		return x.WebhookUrl
+	}
+	return ""
+}
+
+func (x *AlertRule) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *AlertRule) GetLastFiredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastFiredAt
+	}
+	return nil
+}
+
+func (x *AlertRule) GetLastRate() float64 {
+	if x != nil {
+		return x.LastRate
+	}
+	return 0
+}
+
+type AlertRuleRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Base            string                 `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	Quote           string                 `protobuf:"bytes,2,opt,name=quote,proto3" json:"quote,omitempty"`
+	Condition       string                 `protobuf:"bytes,3,opt,name=condition,proto3" json:"condition,omitempty"`
+	Threshold       float64                `protobuf:"fixed64,4,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	CooldownSeconds int64                  `protobuf:"varint,5,opt,name=cooldown_seconds,json=cooldownSeconds,proto3" json:"cooldown_seconds,omitempty"`
+	WebhookUrl      string                 `protobuf:"bytes,6,opt,name=webhook_url,json=webhookUrl,proto3" json:"webhook_url,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AlertRuleRequest) Reset() {
+	*x = AlertRuleRequest{}
+	mi := &file_proto_entities_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertRuleRequest) ProtoMessage() {}
+
+func (x *AlertRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_entities_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertRuleRequest.ProtoReflect.Descriptor instead.
+func (*AlertRuleRequest) Descriptor() ([]byte, []int) {
+	return file_proto_entities_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AlertRuleRequest) GetBase() string {
+	if x != nil {
+		return x.Base
+	}
+	return ""
+}
+
+func (x *AlertRuleRequest) GetQuote() string {
+	if x != nil {
+		return x.Quote
+	}
+	return ""
+}
+
+func (x *AlertRuleRequest) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+func (x *AlertRuleRequest) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *AlertRuleRequest) GetCooldownSeconds() int64 {
+	if x != nil {
+		return x.CooldownSeconds
+	}
+	return 0
+}
+
+func (x *AlertRuleRequest) GetWebhookUrl() string {
+	if x != nil {
+		return x.WebhookUrl
+	}
+	return ""
+}
+
+type UpdateAlertRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Rule          *AlertRuleRequest      `protobuf:"bytes,2,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateAlertRequest) Reset() {
+	*x = UpdateAlertRequest{}
+	mi := &file_proto_entities_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAlertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAlertRequest) ProtoMessage() {}
+
+func (x *UpdateAlertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_entities_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAlertRequest.ProtoReflect.Descriptor instead.
+func (*UpdateAlertRequest) Descriptor() ([]byte, []int) {
+	return file_proto_entities_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateAlertRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateAlertRequest) GetRule() *AlertRuleRequest {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type AlertID struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertID) Reset() {
+	*x = AlertID{}
+	mi := &file_proto_entities_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertID) ProtoMessage() {}
+
+func (x *AlertID) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_entities_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertID.ProtoReflect.Descriptor instead.
+func (*AlertID) Descriptor() ([]byte, []int) {
+	return file_proto_entities_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AlertID) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListAlertsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alerts        []*AlertRule           `protobuf:"bytes,1,rep,name=alerts,proto3" json:"alerts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlertsResponse) Reset() {
+	*x = ListAlertsResponse{}
+	mi := &file_proto_entities_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertsResponse) ProtoMessage() {}
+
+func (x *ListAlertsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_entities_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertsResponse.ProtoReflect.Descriptor instead.
+func (*ListAlertsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_entities_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListAlertsResponse) GetAlerts() []*AlertRule {
+	if x != nil {
+		return x.Alerts
+	}
+	return nil
+}
+
+type WebhookSubscription struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Events        []string               `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"` // пустой список - все события
+	Secret        string                 `protobuf:"bytes,4,opt,name=secret,proto3" json:"secret,omitempty"` // только в ответе CreateWebhook
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookSubscription) Reset() {
+	*x = WebhookSubscription{}
+	mi := &file_proto_entities_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookSubscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookSubscription) ProtoMessage() {}
+
+func (x *WebhookSubscription) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_entities_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookSubscription.ProtoReflect.Descriptor instead.
+func (*WebhookSubscription) Descriptor() ([]byte, []int) {
+	return file_proto_entities_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WebhookSubscription) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *WebhookSubscription) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *WebhookSubscription) GetEvents() []string {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *WebhookSubscription) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *WebhookSubscription) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type WebhookSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Events        []string               `protobuf:"bytes,2,rep,name=events,proto3" json:"events,omitempty"`
+	Secret        string                 `protobuf:"bytes,3,opt,name=secret,proto3" json:"secret,omitempty"` // если не задан, генерируется
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookSubscriptionRequest) Reset() {
+	*x = WebhookSubscriptionRequest{}
+	mi := &file_proto_entities_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookSubscriptionRequest) ProtoMessage() {}
+
+func (x *WebhookSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_entities_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*WebhookSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_entities_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *WebhookSubscriptionRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *WebhookSubscriptionRequest) GetEvents() []string {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *WebhookSubscriptionRequest) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type WebhookID struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookID) Reset() {
+	*x = WebhookID{}
+	mi := &file_proto_entities_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookID) ProtoMessage() {}
+
+func (x *WebhookID) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_entities_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookID.ProtoReflect.Descriptor instead.
+func (*WebhookID) Descriptor() ([]byte, []int) {
+	return file_proto_entities_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *WebhookID) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListWebhooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhooks      []*WebhookSubscription `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksResponse) Reset() {
+	*x = ListWebhooksResponse{}
+	mi := &file_proto_entities_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksResponse) ProtoMessage() {}
+
+func (x *ListWebhooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_entities_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhooksResponse) Descriptor() ([]byte, []int) {
+	return file_proto_entities_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListWebhooksResponse) GetWebhooks() []*WebhookSubscription {
+	if x != nil {
+		return x.Webhooks
+	}
+	return nil
+}
+
+type WebhookDelivery struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	EventId        string                 `protobuf:"bytes,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	SubscriptionId string                 `protobuf:"bytes,3,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	Url            string                 `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	Event          string                 `protobuf:"bytes,5,opt,name=event,proto3" json:"event,omitempty"`
+	Payload        string                 `protobuf:"bytes,6,opt,name=payload,proto3" json:"payload,omitempty"` // JSON, который отправляется подписчику
+	Status         string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`   // pending, delivered или dead
+	Attempts       int32                  `protobuf:"varint,8,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	ResponseCode   int32                  `protobuf:"varint,9,opt,name=response_code,json=responseCode,proto3" json:"response_code,omitempty"`
+	LastError      string                 `protobuf:"bytes,10,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastAttemptAt  *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=last_attempt_at,json=lastAttemptAt,proto3" json:"last_attempt_at,omitempty"`
+	NextAttemptAt  *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WebhookDelivery) Reset() {
+	*x = WebhookDelivery{}
+	mi := &file_proto_entities_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDelivery) ProtoMessage() {}
+
+func (x *WebhookDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_entities_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDelivery.ProtoReflect.Descriptor instead.
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return file_proto_entities_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *WebhookDelivery) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetResponseCode() int32 {
+	if x != nil {
+		return x.ResponseCode
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetLastAttemptAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastAttemptAt
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetNextAttemptAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextAttemptAt
+	}
+	return nil
+}
+
+type DeliveryID struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeliveryID) Reset() {
+	*x = DeliveryID{}
+	mi := &file_proto_entities_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliveryID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveryID) ProtoMessage() {}
+
+func (x *DeliveryID) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_entities_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveryID.ProtoReflect.Descriptor instead.
+func (*DeliveryID) Descriptor() ([]byte, []int) {
+	return file_proto_entities_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DeliveryID) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListDeliveriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deliveries    []*WebhookDelivery     `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeliveriesResponse) Reset() {
+	*x = ListDeliveriesResponse{}
+	mi := &file_proto_entities_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeliveriesResponse) ProtoMessage() {}
+
+func (x *ListDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_entities_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*ListDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_entities_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListDeliveriesResponse) GetDeliveries() []*WebhookDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+type CandlesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Base          string                 `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	Quote         string                 `protobuf:"bytes,2,opt,name=quote,proto3" json:"quote,omitempty"`       // по умолчанию RUB
+	Interval      string                 `protobuf:"bytes,3,opt,name=interval,proto3" json:"interval,omitempty"` // Nd, Nw, NM или Ny; по умолчанию 1d
+	From          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`         // включительно
+	To            *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=to,proto3" json:"to,omitempty"`             // не включительно
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CandlesRequest) Reset() {
+	*x = CandlesRequest{}
+	mi := &file_proto_entities_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CandlesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CandlesRequest) ProtoMessage() {}
+
+func (x *CandlesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_entities_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CandlesRequest.ProtoReflect.Descriptor instead.
+func (*CandlesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_entities_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CandlesRequest) GetBase() string {
+	if x != nil {
+		return x.Base
+	}
+	return ""
+}
+
+func (x *CandlesRequest) GetQuote() string {
+	if x != nil {
+		return x.Quote
+	}
+	return ""
+}
+
+func (x *CandlesRequest) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *CandlesRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *CandlesRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type Candle struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	Open          float64                `protobuf:"fixed64,3,opt,name=open,proto3" json:"open,omitempty"`
+	High          float64                `protobuf:"fixed64,4,opt,name=high,proto3" json:"high,omitempty"`
+	Low           float64                `protobuf:"fixed64,5,opt,name=low,proto3" json:"low,omitempty"`
+	Close         float64                `protobuf:"fixed64,6,opt,name=close,proto3" json:"close,omitempty"`
+	Days          int32                  `protobuf:"varint,7,opt,name=days,proto3" json:"days,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Candle) Reset() {
+	*x = Candle{}
+	mi := &file_proto_entities_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Candle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Candle) ProtoMessage() {}
+
+func (x *Candle) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_entities_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Candle.ProtoReflect.Descriptor instead.
+func (*Candle) Descriptor() ([]byte, []int) {
+	return file_proto_entities_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Candle) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Candle) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *Candle) GetOpen() float64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *Candle) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *Candle) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *Candle) GetClose() float64 {
+	if x != nil {
+		return x.Close
+	}
+	return 0
+}
+
+func (x *Candle) GetDays() int32 {
+	if x != nil {
+		return x.Days
+	}
+	return 0
+}
+
+type CandlesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Base          string                 `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	Quote         string                 `protobuf:"bytes,2,opt,name=quote,proto3" json:"quote,omitempty"`
+	Interval      string                 `protobuf:"bytes,3,opt,name=interval,proto3" json:"interval,omitempty"`
+	Candles       []*Candle              `protobuf:"bytes,4,rep,name=candles,proto3" json:"candles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CandlesResponse) Reset() {
+	*x = CandlesResponse{}
+	mi := &file_proto_entities_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CandlesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CandlesResponse) ProtoMessage() {}
+
+func (x *CandlesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_entities_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CandlesResponse.ProtoReflect.Descriptor instead.
+func (*CandlesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_entities_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *CandlesResponse) GetBase() string {
+	if x != nil {
+		return x.Base
+	}
+	return ""
+}
+
+func (x *CandlesResponse) GetQuote() string {
+	if x != nil {
+		return x.Quote
+	}
+	return ""
+}
+
+func (x *CandlesResponse) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *CandlesResponse) GetCandles() []*Candle {
+	if x != nil {
+		return x.Candles
+	}
+	return nil
+}
+
+var File_proto_entities_proto protoreflect.FileDescriptor
+
+const file_proto_entities_proto_rawDesc = "" +
+	"\n" +
+	"\x14proto/entities.proto\x12\x11CurrencyConverter\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc2\x01\n" +
+	"\bCurrency\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x12\n" +
+	"\x04rate\x18\x02 \x01(\x01R\x04rate\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x16\n" +
+	"\x06symbol\x18\x04 \x01(\tR\x06symbol\x12#\n" +
+	"\rprevious_rate\x18\x05 \x01(\x01R\fpreviousRate\x12\x16\n" +
+	"\x06change\x18\x06 \x01(\x01R\x06change\x12%\n" +
+	"\x0echange_percent\x18\a \x01(\x01R\rchangePercent\"\xd5\x01\n" +
+	"\n" +
+	"Conversion\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x01R\x06amount\x12/\n" +
+	"\x04from\x18\x02 \x01(\v2\x1b.CurrencyConverter.CurrencyR\x04from\x12+\n" +
+	"\x02to\x18\x03 \x01(\v2\x1b.CurrencyConverter.CurrencyR\x02to\x12\x16\n" +
+	"\x06result\x18\x04 \x01(\x01R\x06result\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"P\n" +
+	"\x15CreateCurrencyRequest\x127\n" +
+	"\bcurrency\x18\x01 \x01(\v2\x1b.CurrencyConverter.CurrencyR\bcurrency\"U\n" +
+	"\x16ListCurrenciesResponse\x12;\n" +
+	"\n" +
+	"currencies\x18\x01 \x03(\v2\x1b.CurrencyConverter.CurrencyR\n" +
+	"currencies\")\n" +
+	"\x11ListMoversRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\"\x80\x01\n" +
+	"\x12ListMoversResponse\x125\n" +
+	"\againers\x18\x01 \x03(\v2\x1b.CurrencyConverter.CurrencyR\againers\x123\n" +
+	"\x06losers\x18\x02 \x03(\v2\x1b.CurrencyConverter.CurrencyR\x06losers\"U\n" +
+	"\x17CreateConversionRequest\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x01R\x06amount\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\tR\x02to\"Z\n" +
+	"\x17ListConversionsResponse\x12?\n" +
+	"\vconversions\x18\x01 \x03(\v2\x1d.CurrencyConverter.ConversionR\vconversions\"\xe5\x02\n" +
+	"\tAlertRule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04base\x18\x02 \x01(\tR\x04base\x12\x14\n" +
+	"\x05quote\x18\x03 \x01(\tR\x05quote\x12\x1c\n" +
+	"\tcondition\x18\x04 \x01(\tR\tcondition\x12\x1c\n" +
+	"\tthreshold\x18\x05 \x01(\x01R\tthreshold\x12)\n" +
+	"\x10cooldown_seconds\x18\x06 \x01(\x03R\x0fcooldownSeconds\x12\x1f\n" +
+	"\vwebhook_url\x18\a \x01(\tR\n" +
+	"webhookUrl\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12>\n" +
+	"\rlast_fired_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\vlastFiredAt\x12\x1b\n" +
+	"\tlast_rate\x18\n" +
+	" \x01(\x01R\blastRate\"\xc4\x01\n" +
+	"\x10AlertRuleRequest\x12\x12\n" +
+	"\x04base\x18\x01 \x01(\tR\x04base\x12\x14\n" +
+	"\x05quote\x18\x02 \x01(\tR\x05quote\x12\x1c\n" +
+	"\tcondition\x18\x03 \x01(\tR\tcondition\x12\x1c\n" +
+	"\tthreshold\x18\x04 \x01(\x01R\tthreshold\x12)\n" +
+	"\x10cooldown_seconds\x18\x05 \x01(\x03R\x0fcooldownSeconds\x12\x1f\n" +
+	"\vwebhook_url\x18\x06 \x01(\tR\n" +
+	"webhookUrl\"]\n" +
+	"\x12UpdateAlertRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x127\n" +
+	"\x04rule\x18\x02 \x01(\v2#.CurrencyConverter.AlertRuleRequestR\x04rule\"\x19\n" +
+	"\aAlertID\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"J\n" +
+	"\x12ListAlertsResponse\x124\n" +
+	"\x06alerts\x18\x01 \x03(\v2\x1c.CurrencyConverter.AlertRuleR\x06alerts\"\xa2\x01\n" +
+	"\x13WebhookSubscription\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x16\n" +
+	"\x06events\x18\x03 \x03(\tR\x06events\x12\x16\n" +
+	"\x06secret\x18\x04 \x01(\tR\x06secret\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"^\n" +
+	"\x1aWebhookSubscriptionRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x16\n" +
+	"\x06events\x18\x02 \x03(\tR\x06events\x12\x16\n" +
+	"\x06secret\x18\x03 \x01(\tR\x06secret\"\x1b\n" +
+	"\tWebhookID\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"Z\n" +
+	"\x14ListWebhooksResponse\x12B\n" +
+	"\bwebhooks\x18\x01 \x03(\v2&.CurrencyConverter.WebhookSubscriptionR\bwebhooks\"\xe2\x03\n" +
+	"\x0fWebhookDelivery\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bevent_id\x18\x02 \x01(\tR\aeventId\x12'\n" +
+	"\x0fsubscription_id\x18\x03 \x01(\tR\x0esubscriptionId\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\x12\x14\n" +
+	"\x05event\x18\x05 \x01(\tR\x05event\x12\x18\n" +
+	"\apayload\x18\x06 \x01(\tR\apayload\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12\x1a\n" +
+	"\battempts\x18\b \x01(\x05R\battempts\x12#\n" +
+	"\rresponse_code\x18\t \x01(\x05R\fresponseCode\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\n" +
+	" \x01(\tR\tlastError\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12B\n" +
+	"\x0flast_attempt_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\rlastAttemptAt\x12B\n" +
+	"\x0fnext_attempt_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\rnextAttemptAt\"\x1c\n" +
+	"\n" +
+	"DeliveryID\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\\\n" +
+	"\x16ListDeliveriesResponse\x12B\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\".CurrencyConverter.WebhookDeliveryR\n" +
+	"deliveries\"\xb2\x01\n" +
+	"\x0eCandlesRequest\x12\x12\n" +
+	"\x04base\x18\x01 \x01(\tR\x04base\x12\x14\n" +
+	"\x05quote\x18\x02 \x01(\tR\x05quote\x12\x1a\n" +
+	"\binterval\x18\x03 \x01(\tR\binterval\x12.\n" +
+	"\x04from\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"\xcc\x01\n" +
+	"\x06Candle\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x12\x12\n" +
+	"\x04open\x18\x03 \x01(\x01R\x04open\x12\x12\n" +
+	"\x04high\x18\x04 \x01(\x01R\x04high\x12\x10\n" +
+	"\x03low\x18\x05 \x01(\x01R\x03low\x12\x14\n" +
+	"\x05close\x18\x06 \x01(\x01R\x05close\x12\x12\n" +
+	"\x04days\x18\a \x01(\x05R\x04days\"\x8c\x01\n" +
+	"\x0fCandlesResponse\x12\x12\n" +
+	"\x04base\x18\x01 \x01(\tR\x04base\x12\x14\n" +
+	"\x05quote\x18\x02 \x01(\tR\x05quote\x12\x1a\n" +
+	"\binterval\x18\x03 \x01(\tR\binterval\x123\n" +
+	"\acandles\x18\x04 \x03(\v2\x19.CurrencyConverter.CandleR\acandles2\xcf\x04\n" +
+	"\x0fCurrencyService\x12W\n" +
+	"\x0eCreateCurrency\x12(.CurrencyConverter.CreateCurrencyRequest\x1a\x1b.CurrencyConverter.Currency\x12W\n" +
+	"\x0eUpsertCurrency\x12(.CurrencyConverter.CreateCurrencyRequest\x1a\x1b.CurrencyConverter.Currency\x12G\n" +
+	"\vGetCurrency\x12\x1b.CurrencyConverter.Currency\x1a\x1b.CurrencyConverter.Currency\x12J\n" +
+	"\x0eUpdateCurrency\x12\x1b.CurrencyConverter.Currency\x1a\x1b.CurrencyConverter.Currency\x12E\n" +
+	"\x0eDeleteCurrency\x12\x1b.CurrencyConverter.Currency\x1a\x16.google.protobuf.Empty\x12S\n" +
+	"\x0eListCurrencies\x12\x16.google.protobuf.Empty\x1a).CurrencyConverter.ListCurrenciesResponse\x12Y\n" +
+	"\n" +
+	"ListMovers\x12$.CurrencyConverter.ListMoversRequest\x1a%.CurrencyConverter.ListMoversResponse2\xc9\x01\n" +
+	"\x11ConversionService\x12]\n" +
+	"\x10CreateConversion\x12*.CurrencyConverter.CreateConversionRequest\x1a\x1d.CurrencyConverter.Conversion\x12U\n" +
+	"\x0fListConversions\x12\x16.google.protobuf.Empty\x1a*.CurrencyConverter.ListConversionsResponse2\x8a\x03\n" +
+	"\fAlertService\x12P\n" +
+	"\vCreateAlert\x12#.CurrencyConverter.AlertRuleRequest\x1a\x1c.CurrencyConverter.AlertRule\x12D\n" +
+	"\bGetAlert\x12\x1a.CurrencyConverter.AlertID\x1a\x1c.CurrencyConverter.AlertRule\x12K\n" +
+	"\n" +
+	"ListAlerts\x12\x16.google.protobuf.Empty\x1a%.CurrencyConverter.ListAlertsResponse\x12R\n" +
+	"\vUpdateAlert\x12%.CurrencyConverter.UpdateAlertRequest\x1a\x1c.CurrencyConverter.AlertRule\x12A\n" +
+	"\vDeleteAlert\x12\x1a.CurrencyConverter.AlertID\x1a\x16.google.protobuf.Empty2\xe9\x04\n" +
+	"\x0eWebhookService\x12f\n" +
+	"\rCreateWebhook\x12-.CurrencyConverter.WebhookSubscriptionRequest\x1a&.CurrencyConverter.WebhookSubscription\x12R\n" +
+	"\n" +
+	"GetWebhook\x12\x1c.CurrencyConverter.WebhookID\x1a&.CurrencyConverter.WebhookSubscription\x12O\n" +
+	"\fListWebhooks\x12\x16.google.protobuf.Empty\x1a'.CurrencyConverter.ListWebhooksResponse\x12E\n" +
+	"\rDeleteWebhook\x12\x1c.CurrencyConverter.WebhookID\x1a\x16.google.protobuf.Empty\x12Y\n" +
+	"\x0eListDeliveries\x12\x1c.CurrencyConverter.WebhookID\x1a).CurrencyConverter.ListDeliveriesResponse\x12T\n" +
+	"\x0fListDeadLetters\x12\x16.google.protobuf.Empty\x1a).CurrencyConverter.ListDeliveriesResponse\x12R\n" +
+	"\rRetryDelivery\x12\x1d.CurrencyConverter.DeliveryID\x1a\".CurrencyConverter.WebhookDelivery2e\n" +
+	"\x0eHistoryService\x12S\n" +
+	"\n" +
+	"GetCandles\x12!.CurrencyConverter.CandlesRequest\x1a\".CurrencyConverter.CandlesResponseB)Z'currency-converter/internal/proto;protob\x06proto3"
 
 var (
 	file_proto_entities_proto_rawDescOnce sync.Once
@@ -399,39 +1677,107 @@ func file_proto_entities_proto_rawDescGZIP() []byte {
 	return file_proto_entities_proto_rawDescData
 }
 
-var file_proto_entities_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_entities_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
 var file_proto_entities_proto_goTypes = []any{
-	(*Currency)(nil),                // 0: CurrencyConverter.Currency
-	(*Conversion)(nil),              // 1: CurrencyConverter.Conversion
-	(*CreateCurrencyRequest)(nil),   // 2: CurrencyConverter.CreateCurrencyRequest
-	(*ListCurrenciesResponse)(nil),  // 3: CurrencyConverter.ListCurrenciesResponse
-	(*CreateConversionRequest)(nil), // 4: CurrencyConverter.CreateConversionRequest
-	(*ListConversionsResponse)(nil), // 5: CurrencyConverter.ListConversionsResponse
-	(*emptypb.Empty)(nil),           // 6: google.protobuf.Empty
+	(*Currency)(nil),                   // 0: CurrencyConverter.Currency
+	(*Conversion)(nil),                 // 1: CurrencyConverter.Conversion
+	(*CreateCurrencyRequest)(nil),      // 2: CurrencyConverter.CreateCurrencyRequest
+	(*ListCurrenciesResponse)(nil),     // 3: CurrencyConverter.ListCurrenciesResponse
+	(*ListMoversRequest)(nil),          // 4: CurrencyConverter.ListMoversRequest
+	(*ListMoversResponse)(nil),         // 5: CurrencyConverter.ListMoversResponse
+	(*CreateConversionRequest)(nil),    // 6: CurrencyConverter.CreateConversionRequest
+	(*ListConversionsResponse)(nil),    // 7: CurrencyConverter.ListConversionsResponse
+	(*AlertRule)(nil),                  // 8: CurrencyConverter.AlertRule
+	(*AlertRuleRequest)(nil),           // 9: CurrencyConverter.AlertRuleRequest
+	(*UpdateAlertRequest)(nil),         // 10: CurrencyConverter.UpdateAlertRequest
+	(*AlertID)(nil),                    // 11: CurrencyConverter.AlertID
+	(*ListAlertsResponse)(nil),         // 12: CurrencyConverter.ListAlertsResponse
+	(*WebhookSubscription)(nil),        // 13: CurrencyConverter.WebhookSubscription
+	(*WebhookSubscriptionRequest)(nil), // 14: CurrencyConverter.WebhookSubscriptionRequest
+	(*WebhookID)(nil),                  // 15: CurrencyConverter.WebhookID
+	(*ListWebhooksResponse)(nil),       // 16: CurrencyConverter.ListWebhooksResponse
+	(*WebhookDelivery)(nil),            // 17: CurrencyConverter.WebhookDelivery
+	(*DeliveryID)(nil),                 // 18: CurrencyConverter.DeliveryID
+	(*ListDeliveriesResponse)(nil),     // 19: CurrencyConverter.ListDeliveriesResponse
+	(*CandlesRequest)(nil),             // 20: CurrencyConverter.CandlesRequest
+	(*Candle)(nil),                     // 21: CurrencyConverter.Candle
+	(*CandlesResponse)(nil),            // 22: CurrencyConverter.CandlesResponse
+	(*timestamppb.Timestamp)(nil),      // 23: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),              // 24: google.protobuf.Empty
 }
 var file_proto_entities_proto_depIdxs = []int32{
 	0,  // 0: CurrencyConverter.Conversion.from:type_name -> CurrencyConverter.Currency
 	0,  // 1: CurrencyConverter.Conversion.to:type_name -> CurrencyConverter.Currency
-	0,  // 2: CurrencyConverter.CreateCurrencyRequest.currency:type_name -> CurrencyConverter.Currency
-	0,  // 3: CurrencyConverter.ListCurrenciesResponse.currencies:type_name -> CurrencyConverter.Currency
-	1,  // 4: CurrencyConverter.ListConversionsResponse.conversions:type_name -> CurrencyConverter.Conversion
-	2,  // 5: CurrencyConverter.CurrencyService.CreateCurrency:input_type -> CurrencyConverter.CreateCurrencyRequest
-	0,  // 6: CurrencyConverter.CurrencyService.GetCurrency:input_type -> CurrencyConverter.Currency
-	0,  // 7: CurrencyConverter.CurrencyService.UpdateCurrency:input_type -> CurrencyConverter.Currency
-	6,  // 8: CurrencyConverter.CurrencyService.ListCurrencies:input_type -> google.protobuf.Empty
-	4,  // 9: CurrencyConverter.ConversionService.CreateConversion:input_type -> CurrencyConverter.CreateConversionRequest
-	6,  // 10: CurrencyConverter.ConversionService.ListConversions:input_type -> google.protobuf.Empty
-	0,  // 11: CurrencyConverter.CurrencyService.CreateCurrency:output_type -> CurrencyConverter.Currency
-	0,  // 12: CurrencyConverter.CurrencyService.GetCurrency:output_type -> CurrencyConverter.Currency
-	0,  // 13: CurrencyConverter.CurrencyService.UpdateCurrency:output_type -> CurrencyConverter.Currency
-	3,  // 14: CurrencyConverter.CurrencyService.ListCurrencies:output_type -> CurrencyConverter.ListCurrenciesResponse
-	1,  // 15: CurrencyConverter.ConversionService.CreateConversion:output_type -> CurrencyConverter.Conversion
-	5,  // 16: CurrencyConverter.ConversionService.ListConversions:output_type -> CurrencyConverter.ListConversionsResponse
-	11, // [11:17] is the sub-list for method output_type
-	5,  // [5:11] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	23, // 2: CurrencyConverter.Conversion.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 3: CurrencyConverter.CreateCurrencyRequest.currency:type_name -> CurrencyConverter.Currency
+	0,  // 4: CurrencyConverter.ListCurrenciesResponse.currencies:type_name -> CurrencyConverter.Currency
+	0,  // 5: CurrencyConverter.ListMoversResponse.gainers:type_name -> CurrencyConverter.Currency
+	0,  // 6: CurrencyConverter.ListMoversResponse.losers:type_name -> CurrencyConverter.Currency
+	1,  // 7: CurrencyConverter.ListConversionsResponse.conversions:type_name -> CurrencyConverter.Conversion
+	23, // 8: CurrencyConverter.AlertRule.created_at:type_name -> google.protobuf.Timestamp
+	23, // 9: CurrencyConverter.AlertRule.last_fired_at:type_name -> google.protobuf.Timestamp
+	9,  // 10: CurrencyConverter.UpdateAlertRequest.rule:type_name -> CurrencyConverter.AlertRuleRequest
+	8,  // 11: CurrencyConverter.ListAlertsResponse.alerts:type_name -> CurrencyConverter.AlertRule
+	23, // 12: CurrencyConverter.WebhookSubscription.created_at:type_name -> google.protobuf.Timestamp
+	13, // 13: CurrencyConverter.ListWebhooksResponse.webhooks:type_name -> CurrencyConverter.WebhookSubscription
+	23, // 14: CurrencyConverter.WebhookDelivery.created_at:type_name -> google.protobuf.Timestamp
+	23, // 15: CurrencyConverter.WebhookDelivery.last_attempt_at:type_name -> google.protobuf.Timestamp
+	23, // 16: CurrencyConverter.WebhookDelivery.next_attempt_at:type_name -> google.protobuf.Timestamp
+	17, // 17: CurrencyConverter.ListDeliveriesResponse.deliveries:type_name -> CurrencyConverter.WebhookDelivery
+	23, // 18: CurrencyConverter.CandlesRequest.from:type_name -> google.protobuf.Timestamp
+	23, // 19: CurrencyConverter.CandlesRequest.to:type_name -> google.protobuf.Timestamp
+	23, // 20: CurrencyConverter.Candle.start:type_name -> google.protobuf.Timestamp
+	23, // 21: CurrencyConverter.Candle.end:type_name -> google.protobuf.Timestamp
+	21, // 22: CurrencyConverter.CandlesResponse.candles:type_name -> CurrencyConverter.Candle
+	2,  // 23: CurrencyConverter.CurrencyService.CreateCurrency:input_type -> CurrencyConverter.CreateCurrencyRequest
+	2,  // 24: CurrencyConverter.CurrencyService.UpsertCurrency:input_type -> CurrencyConverter.CreateCurrencyRequest
+	0,  // 25: CurrencyConverter.CurrencyService.GetCurrency:input_type -> CurrencyConverter.Currency
+	0,  // 26: CurrencyConverter.CurrencyService.UpdateCurrency:input_type -> CurrencyConverter.Currency
+	0,  // 27: CurrencyConverter.CurrencyService.DeleteCurrency:input_type -> CurrencyConverter.Currency
+	24, // 28: CurrencyConverter.CurrencyService.ListCurrencies:input_type -> google.protobuf.Empty
+	4,  // 29: CurrencyConverter.CurrencyService.ListMovers:input_type -> CurrencyConverter.ListMoversRequest
+	6,  // 30: CurrencyConverter.ConversionService.CreateConversion:input_type -> CurrencyConverter.CreateConversionRequest
+	24, // 31: CurrencyConverter.ConversionService.ListConversions:input_type -> google.protobuf.Empty
+	9,  // 32: CurrencyConverter.AlertService.CreateAlert:input_type -> CurrencyConverter.AlertRuleRequest
+	11, // 33: CurrencyConverter.AlertService.GetAlert:input_type -> CurrencyConverter.AlertID
+	24, // 34: CurrencyConverter.AlertService.ListAlerts:input_type -> google.protobuf.Empty
+	10, // 35: CurrencyConverter.AlertService.UpdateAlert:input_type -> CurrencyConverter.UpdateAlertRequest
+	11, // 36: CurrencyConverter.AlertService.DeleteAlert:input_type -> CurrencyConverter.AlertID
+	14, // 37: CurrencyConverter.WebhookService.CreateWebhook:input_type -> CurrencyConverter.WebhookSubscriptionRequest
+	15, // 38: CurrencyConverter.WebhookService.GetWebhook:input_type -> CurrencyConverter.WebhookID
+	24, // 39: CurrencyConverter.WebhookService.ListWebhooks:input_type -> google.protobuf.Empty
+	15, // 40: CurrencyConverter.WebhookService.DeleteWebhook:input_type -> CurrencyConverter.WebhookID
+	15, // 41: CurrencyConverter.WebhookService.ListDeliveries:input_type -> CurrencyConverter.WebhookID
+	24, // 42: CurrencyConverter.WebhookService.ListDeadLetters:input_type -> google.protobuf.Empty
+	18, // 43: CurrencyConverter.WebhookService.RetryDelivery:input_type -> CurrencyConverter.DeliveryID
+	20, // 44: CurrencyConverter.HistoryService.GetCandles:input_type -> CurrencyConverter.CandlesRequest
+	0,  // 45: CurrencyConverter.CurrencyService.CreateCurrency:output_type -> CurrencyConverter.Currency
+	0,  // 46: CurrencyConverter.CurrencyService.UpsertCurrency:output_type -> CurrencyConverter.Currency
+	0,  // 47: CurrencyConverter.CurrencyService.GetCurrency:output_type -> CurrencyConverter.Currency
+	0,  // 48: CurrencyConverter.CurrencyService.UpdateCurrency:output_type -> CurrencyConverter.Currency
+	24, // 49: CurrencyConverter.CurrencyService.DeleteCurrency:output_type -> google.protobuf.Empty
+	3,  // 50: CurrencyConverter.CurrencyService.ListCurrencies:output_type -> CurrencyConverter.ListCurrenciesResponse
+	5,  // 51: CurrencyConverter.CurrencyService.ListMovers:output_type -> CurrencyConverter.ListMoversResponse
+	1,  // 52: CurrencyConverter.ConversionService.CreateConversion:output_type -> CurrencyConverter.Conversion
+	7,  // 53: CurrencyConverter.ConversionService.ListConversions:output_type -> CurrencyConverter.ListConversionsResponse
+	8,  // 54: CurrencyConverter.AlertService.CreateAlert:output_type -> CurrencyConverter.AlertRule
+	8,  // 55: CurrencyConverter.AlertService.GetAlert:output_type -> CurrencyConverter.AlertRule
+	12, // 56: CurrencyConverter.AlertService.ListAlerts:output_type -> CurrencyConverter.ListAlertsResponse
+	8,  // 57: CurrencyConverter.AlertService.UpdateAlert:output_type -> CurrencyConverter.AlertRule
+	24, // 58: CurrencyConverter.AlertService.DeleteAlert:output_type -> google.protobuf.Empty
+	13, // 59: CurrencyConverter.WebhookService.CreateWebhook:output_type -> CurrencyConverter.WebhookSubscription
+	13, // 60: CurrencyConverter.WebhookService.GetWebhook:output_type -> CurrencyConverter.WebhookSubscription
+	16, // 61: CurrencyConverter.WebhookService.ListWebhooks:output_type -> CurrencyConverter.ListWebhooksResponse
+	24, // 62: CurrencyConverter.WebhookService.DeleteWebhook:output_type -> google.protobuf.Empty
+	19, // 63: CurrencyConverter.WebhookService.ListDeliveries:output_type -> CurrencyConverter.ListDeliveriesResponse
+	19, // 64: CurrencyConverter.WebhookService.ListDeadLetters:output_type -> CurrencyConverter.ListDeliveriesResponse
+	17, // 65: CurrencyConverter.WebhookService.RetryDelivery:output_type -> CurrencyConverter.WebhookDelivery
+	22, // 66: CurrencyConverter.HistoryService.GetCandles:output_type -> CurrencyConverter.CandlesResponse
+	45, // [45:67] is the sub-list for method output_type
+	23, // [23:45] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_proto_entities_proto_init() }
@@ -445,9 +1791,9 @@ func file_proto_entities_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_entities_proto_rawDesc), len(file_proto_entities_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   23,
 			NumExtensions: 0,
-			NumServices:   2,
+			NumServices:   5,
 		},
 		GoTypes:           file_proto_entities_proto_goTypes,
 		DependencyIndexes: file_proto_entities_proto_depIdxs,